@@ -0,0 +1,6 @@
+package dto
+
+// UpdateUserRoleRequest represents admin request to change a user's role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// AttendanceSummary represents the recent no-shows of a user and whether they
+// restrict booking
+type AttendanceSummary struct {
+	NoShowCount int64 `json:"no_show_count"` // No-shows within the window
+	WindowDays  int   `json:"window_days"`
+	Limit       int   `json:"limit"` // No-shows that block booking, 0 when never blocked
+	Restricted  bool  `json:"restricted"`
+}
@@ -14,14 +14,97 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// UpdateProfileRequest represents profile update request
+// UpdateProfileRequest represents profile update request. Notification channels
+// left empty are unchanged.
 type UpdateProfileRequest struct {
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
+	Name           string `json:"name"`
+	Phone          string `json:"phone"`
+	NotifyEmail    *bool  `json:"notify_email"`
+	NotifySMS      *bool  `json:"notify_sms"`
+	NotifyWhatsApp *bool  `json:"notify_whatsapp"`
+}
+
+// RefreshTokenRequest represents a request to renew or end a session
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest represents a logout request. All signs out every session of the user.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	All          bool   `json:"all"`
+}
+
+// ChangePasswordRequest represents a password change of a signed-in user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ChangeEmailRequest represents an email address change. The password confirms it.
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// DeleteAccountRequest represents deleting the account of a signed-in user
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// ForgotPasswordRequest represents a request for a password reset link
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents setting a new password with a reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// VerifyEmailRequest represents confirming an email address with a verification token
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// OIDCCallbackRequest represents the return from a provider's sign-in page
+type OIDCCallbackRequest struct {
+	Code         string `json:"code" binding:"required"`
+	State        string `json:"state" binding:"required"`
+	CodeVerifier string `json:"code_verifier" binding:"required"` // From the authorization
+}
+
+// OIDCTokenRequest represents sign-in with an ID token obtained by a mobile app
+type OIDCTokenRequest struct {
+	IDToken      string `json:"id_token" binding:"required"`
+	State        string `json:"state" binding:"required"`         // From the authorization
+	CodeVerifier string `json:"code_verifier" binding:"required"` // From the authorization
+}
+
+// OIDCAuthorization is where to send the user to sign in with a provider
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`         // Check it matches when the provider sends the user back
+	CodeVerifier     string `json:"code_verifier"` // Keep it in the browser and send it with the callback
+	Nonce            string `json:"nonce"`         // Mobile apps pass it to the provider's SDK
+}
+
+// ClientInfo describes the device a session is signed in from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// AuthTokens are the tokens issued for a session
+type AuthTokens struct {
+	Token        string `json:"token"` // Access token
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // Access token lifetime in seconds
 }
 
 // AuthResponse represents authentication response
 type AuthResponse struct {
 	Token string      `json:"token"`
 	User  interface{} `json:"user"`
-}
\ No newline at end of file
+}
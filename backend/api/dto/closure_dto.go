@@ -0,0 +1,11 @@
+package dto
+
+// ClosureRequest represents a request to close the studio, a court or a timeslot
+type ClosureRequest struct {
+	Name       string `json:"name" binding:"required"`
+	CourtID    *uint  `json:"court_id"`                      // Empty closes all courts
+	TimeslotID *uint  `json:"timeslot_id"`                   // Empty closes all timeslots
+	StartDate  string `json:"start_date" binding:"required"` // Format: YYYY-MM-DD
+	EndDate    string `json:"end_date"`                      // Format: YYYY-MM-DD, defaults to start_date
+	Recurring  bool   `json:"recurring"`                     // Repeat every year, e.g. public holidays
+}
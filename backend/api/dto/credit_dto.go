@@ -0,0 +1,16 @@
+package dto
+
+// CreditPackageRequest represents credit package create/update request
+type CreditPackageRequest struct {
+	Name         string  `json:"name" binding:"required"`
+	Description  string  `json:"description"`
+	Credits      int     `json:"credits" binding:"required,min=1"`
+	Price        float64 `json:"price" binding:"required,gt=0"`
+	ValidityDays int     `json:"validity_days" binding:"required,min=1"`
+	IsActive     *bool   `json:"is_active"`
+}
+
+// PurchasePackageRequest represents credit package purchase request
+type PurchasePackageRequest struct {
+	PackageID uint `json:"package_id" binding:"required"`
+}
@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// AvailabilityWindowRequest represents a weekly time range an instructor can teach
+type AvailabilityWindowRequest struct {
+	Weekday int    `json:"weekday" binding:"min=0,max=6"` // 0 = Sunday ... 6 = Saturday
+	Start   string `json:"start" binding:"required"`      // Format: HH:MM
+	End     string `json:"end" binding:"required"`        // Format: HH:MM
+}
+
+// InstructorRequest represents instructor create/update request
+type InstructorRequest struct {
+	UserID       uint                        `json:"user_id"` // Required on create
+	Name         string                      `json:"name"`    // Defaults to the user's name
+	Bio          string                      `json:"bio"`
+	Specialties  []string                    `json:"specialties"`
+	Availability []AvailabilityWindowRequest `json:"availability" binding:"dive"`
+	IsActive     *bool                       `json:"is_active"`
+}
+
+// ClassAssignmentRequest represents a request to assign an instructor to a class session
+type ClassAssignmentRequest struct {
+	CourtID      uint   `json:"court_id" binding:"required"`
+	TimeslotID   uint   `json:"timeslot_id" binding:"required"`
+	Date         string `json:"date" binding:"required"` // Format: YYYY-MM-DD
+	InstructorID uint   `json:"instructor_id" binding:"required"`
+}
+
+// InstructorSummary represents the instructor shown with a class
+type InstructorSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// RosterClass represents a class taught by an instructor with its attendees
+type RosterClass struct {
+	SessionID  uint             `json:"session_id"`
+	Status     string           `json:"status"` // Session status: scheduled or cancelled
+	CourtID    uint             `json:"court_id"`
+	CourtName  string           `json:"court_name"`
+	TimeslotID uint             `json:"timeslot_id"`
+	Time       string           `json:"time"`
+	Duration   int              `json:"duration"`
+	Date       string           `json:"date"`
+	Capacity   int              `json:"capacity"`
+	Attendees  []RosterAttendee `json:"attendees"`
+}
+
+// RosterAttendee represents a booked or held seat on a roster
+type RosterAttendee struct {
+	ReservationID uint       `json:"reservation_id"`
+	UserID        uint       `json:"user_id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Phone         string     `json:"phone"`
+	Status        string     `json:"status"`
+	CheckedInAt   *time.Time `json:"checked_in_at,omitempty"`
+	Notes         string     `json:"notes,omitempty"`
+}
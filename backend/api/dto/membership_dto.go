@@ -0,0 +1,21 @@
+package dto
+
+// MembershipPlanRequest represents membership plan create/update request
+type MembershipPlanRequest struct {
+	Name               string  `json:"name" binding:"required"`
+	Description        string  `json:"description"`
+	Price              float64 `json:"price" binding:"required,gt=0"`
+	WeeklyBookingLimit int     `json:"weekly_booking_limit" binding:"min=0"` // 0 means unlimited
+	IsActive           *bool   `json:"is_active"`
+}
+
+// SubscribeRequest represents membership signup request
+type SubscribeRequest struct {
+	PlanID    uint  `json:"plan_id" binding:"required"`
+	AutoRenew *bool `json:"auto_renew"` // Defaults to true
+}
+
+// UpdateAutoRenewRequest represents membership auto-renew change request
+type UpdateAutoRenewRequest struct {
+	AutoRenew *bool `json:"auto_renew" binding:"required"`
+}
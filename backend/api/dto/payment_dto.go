@@ -5,6 +5,11 @@ type CreatePaymentRequest struct {
 	ReservationID uint `json:"reservation_id" binding:"required"`
 }
 
+// SimulatePaymentRequest represents the outcome chosen on the dummy payment page
+type SimulatePaymentRequest struct {
+	Result string `json:"result" binding:"required,oneof=settlement deny"`
+}
+
 // PaymentCallbackRequest represents Midtrans callback
 type PaymentCallbackRequest struct {
 	OrderID           string `json:"order_id"`
@@ -12,6 +17,10 @@ type PaymentCallbackRequest struct {
 	TransactionID     string `json:"transaction_id"`
 	StatusCode        string `json:"status_code"`
 	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+	PaymentType       string `json:"payment_type"`
+	FraudStatus       string `json:"fraud_status"`
+	TransactionTime   string `json:"transaction_time"`
 }
 
 // MidtransRequest represents request to Midtrans API
@@ -19,6 +28,7 @@ type MidtransRequest struct {
 	TransactionDetails TransactionDetails `json:"transaction_details"`
 	CustomerDetails    CustomerDetails    `json:"customer_details"`
 	ItemDetails        []ItemDetail       `json:"item_details"`
+	Expiry             *Expiry            `json:"expiry,omitempty"`
 }
 
 // TransactionDetails for Midtrans
@@ -42,8 +52,29 @@ type ItemDetail struct {
 	Name     string  `json:"name"`
 }
 
+// Expiry for Midtrans
+type Expiry struct {
+	Unit     string `json:"unit"` // minute, hour or day
+	Duration int    `json:"duration"`
+}
+
 // MidtransResponse represents response from Midtrans
 type MidtransResponse struct {
 	Token       string `json:"token"`
 	RedirectURL string `json:"redirect_url"`
-}
\ No newline at end of file
+}
+
+// MidtransRefundRequest represents refund request to Midtrans Core API
+type MidtransRefundRequest struct {
+	RefundKey string  `json:"refund_key"`
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason"`
+}
+
+// MidtransRefundResponse represents refund response from Midtrans Core API
+type MidtransRefundResponse struct {
+	StatusCode        string `json:"status_code"`
+	StatusMessage     string `json:"status_message"`
+	TransactionStatus string `json:"transaction_status"`
+	RefundKey         string `json:"refund_key"`
+}
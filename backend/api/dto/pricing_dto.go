@@ -0,0 +1,15 @@
+package dto
+
+// PricingRuleRequest represents pricing rule create/update request
+type PricingRuleRequest struct {
+	Name       string  `json:"name" binding:"required"`
+	CourtID    *uint   `json:"court_id"`
+	TimeslotID *uint   `json:"timeslot_id"`
+	Weekday    *int    `json:"weekday"`    // 0 = Sunday ... 6 = Saturday
+	StartDate  string  `json:"start_date"` // Format: YYYY-MM-DD
+	EndDate    string  `json:"end_date"`   // Format: YYYY-MM-DD
+	Action     string  `json:"action" binding:"required"`
+	Value      float64 `json:"value"`
+	Priority   int     `json:"priority"`
+	IsActive   *bool   `json:"is_active"`
+}
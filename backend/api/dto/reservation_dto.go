@@ -1,11 +1,48 @@
 package dto
 
-// CreateReservationRequest represents reservation creation request
+import "time"
+
+// CreateReservationRequest represents reservation creation request. The class is
+// given either as a session ID or as court, timeslot and date.
 type CreateReservationRequest struct {
-	CourtID    uint   `json:"court_id" binding:"required"`
-	TimeslotID uint   `json:"timeslot_id" binding:"required"`
-	Date       string `json:"date" binding:"required"` // Format: YYYY-MM-DD
-	Notes      string `json:"notes"`
+	SessionID     uint   `json:"session_id"`
+	CourtID       uint   `json:"court_id" binding:"required_without=SessionID"`
+	TimeslotID    uint   `json:"timeslot_id" binding:"required_without=SessionID"`
+	Date          string `json:"date" binding:"required_without=SessionID"` // Format: YYYY-MM-DD
+	Notes         string `json:"notes"`
+	UseCredit     bool   `json:"use_credit"`     // Pay with a package credit instead of a payment
+	UseMembership bool   `json:"use_membership"` // Book under an active membership instead of a payment
+}
+
+// ClassAvailability represents seat usage of one class session
+type ClassAvailability struct {
+	SessionID      uint   `json:"session_id"`
+	CourtID        uint   `json:"court_id"`
+	TimeslotID     uint   `json:"timeslot_id"`
+	Date           string `json:"date"`
+	Capacity       int    `json:"capacity"`
+	SeatsBooked    int    `json:"seats_booked"`    // Confirmed reservations
+	SeatsHeld      int    `json:"seats_held"`      // Pending reservations awaiting payment
+	SeatsRemaining int    `json:"seats_remaining"` // Capacity minus booked and held seats
+
+	BookingWindow
+}
+
+// Reasons a class cannot be booked
+const (
+	LockNotOpenYet = "booking_not_open" // The advance booking window has not opened yet
+	LockClosed     = "booking_closed"   // Within the minimum lead time before class start
+	LockFull       = "full"             // No seats remaining
+)
+
+// BookingWindow represents when a class can be booked and cancelled under its booking rules
+type BookingWindow struct {
+	Bookable        bool      `json:"bookable"`
+	LockReason      string    `json:"lock_reason,omitempty"` // Why the class cannot be booked now
+	BookingOpensAt  time.Time `json:"booking_opens_at"`
+	BookingClosesAt time.Time `json:"booking_closes_at"`
+	CancelDeadline  time.Time `json:"cancel_deadline"`   // Later cancellations are late cancels
+	AllowLateCancel bool      `json:"allow_late_cancel"` // Whether late cancels are allowed
 }
 
 // TimeslotAvailability represents timeslot with availability info
@@ -14,17 +51,32 @@ type TimeslotAvailability struct {
 	Time            string `json:"time"`
 	Duration        int    `json:"duration"`
 	IsActive        bool   `json:"is_active"`
-	Available       bool   `json:"available"`
+	Available       bool   `json:"available"`             // At least one court can be booked
+	LockReason      string `json:"lock_reason,omitempty"` // Why no court can be booked
 	BookedCount     int    `json:"booked_count"`
 	AvailableCourts int    `json:"available_courts"`
+	Capacity        int    `json:"capacity"`
+	SeatsBooked     int    `json:"seats_booked"`
+	SeatsHeld       int    `json:"seats_held"`
+	SeatsRemaining  int    `json:"seats_remaining"`
+
+	Instructors []InstructorSummary `json:"instructors,omitempty"` // Instructors teaching this timeslot on the date
 }
 
 // CourtAvailability represents court with availability info
 type CourtAvailability struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	Capacity    int    `json:"capacity"`
-	Description string `json:"description"`
-	IsActive    bool   `json:"is_active"`
-	Available   bool   `json:"available"`
-}
\ No newline at end of file
+	ID             uint   `json:"id"`
+	SessionID      uint   `json:"session_id"`
+	Name           string `json:"name"`
+	Capacity       int    `json:"capacity"`
+	Description    string `json:"description"`
+	IsActive       bool   `json:"is_active"`
+	Available      bool   `json:"available"`
+	SeatsBooked    int    `json:"seats_booked"`
+	SeatsHeld      int    `json:"seats_held"`
+	SeatsRemaining int    `json:"seats_remaining"`
+
+	Instructor *InstructorSummary `json:"instructor,omitempty"`
+
+	BookingWindow
+}
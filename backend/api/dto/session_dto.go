@@ -0,0 +1,48 @@
+package dto
+
+// ClassTemplateRequest represents class template create/update request
+type ClassTemplateRequest struct {
+	CourtID      uint     `json:"court_id" binding:"required"`
+	TimeslotID   uint     `json:"timeslot_id" binding:"required"`
+	Weekday      int      `json:"weekday" binding:"min=0,max=6"` // 0 = Sunday ... 6 = Saturday
+	InstructorID *uint    `json:"instructor_id"`                 // Default instructor of generated sessions
+	Capacity     *int     `json:"capacity" binding:"omitempty,min=1"`
+	Price        *float64 `json:"price" binding:"omitempty,min=0"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+// CreateSessionRequest represents a request to schedule a one-off class session
+type CreateSessionRequest struct {
+	CourtID      uint     `json:"court_id" binding:"required"`
+	TimeslotID   uint     `json:"timeslot_id" binding:"required"`
+	Date         string   `json:"date" binding:"required"` // Format: YYYY-MM-DD
+	InstructorID *uint    `json:"instructor_id"`
+	Capacity     *int     `json:"capacity" binding:"omitempty,min=1"`
+	Price        *float64 `json:"price" binding:"omitempty,min=0"`
+
+	BookingRulesRequest
+}
+
+// UpdateSessionRequest replaces the overrides of a class session. An omitted field
+// clears its override.
+type UpdateSessionRequest struct {
+	InstructorID *uint    `json:"instructor_id"`
+	Capacity     *int     `json:"capacity" binding:"omitempty,min=1"` // Defaults to the court capacity
+	Price        *float64 `json:"price" binding:"omitempty,min=0"`    // Defaults to the pricing rules
+
+	BookingRulesRequest
+}
+
+// BookingRulesRequest sets the booking rules of a session. An omitted rule falls
+// back to the court, then to the studio defaults.
+type BookingRulesRequest struct {
+	MinLeadMinutes      *int  `json:"min_lead_minutes" binding:"omitempty,min=0"`
+	MaxAdvanceDays      *int  `json:"max_advance_days" binding:"omitempty,min=0"`
+	CancelDeadlineHours *int  `json:"cancel_deadline_hours" binding:"omitempty,min=0"`
+	AllowLateCancel     *bool `json:"allow_late_cancel"`
+}
+
+// CancelSessionRequest represents a request to cancel a class session
+type CancelSessionRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// TicketRequest represents a request with a scanned ticket token
+type TicketRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// TicketResponse represents the ticket of a confirmed reservation
+type TicketResponse struct {
+	ReservationID uint   `json:"reservation_id"`
+	Token         string `json:"token"`       // Signed ticket token, encoded in the QR code
+	QRCodeURL     string `json:"qr_code_url"` // PNG QR code of the token
+	TicketClass
+}
+
+// TicketVerification represents a verified ticket with its attendee and class
+type TicketVerification struct {
+	ReservationID uint           `json:"reservation_id"`
+	Status        string         `json:"status"`
+	CheckedInAt   *time.Time     `json:"checked_in_at,omitempty"`
+	Attendee      TicketAttendee `json:"attendee"`
+	Class         TicketClass    `json:"class"`
+}
+
+// TicketAttendee represents the member a ticket was issued to
+type TicketAttendee struct {
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Phone  string `json:"phone"`
+}
+
+// TicketClass represents the class a ticket admits to
+type TicketClass struct {
+	SessionID  uint               `json:"session_id"`
+	CourtID    uint               `json:"court_id"`
+	CourtName  string             `json:"court_name"`
+	Date       string             `json:"date"`
+	Time       string             `json:"time"`
+	Duration   int                `json:"duration"`
+	Instructor *InstructorSummary `json:"instructor,omitempty"`
+}
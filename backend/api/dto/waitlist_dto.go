@@ -0,0 +1,10 @@
+package dto
+
+// JoinWaitlistRequest represents a request to wait for a seat in a full class. The
+// class is given either as a session ID or as court, timeslot and date.
+type JoinWaitlistRequest struct {
+	SessionID  uint   `json:"session_id"`
+	CourtID    uint   `json:"court_id" binding:"required_without=SessionID"`
+	TimeslotID uint   `json:"timeslot_id" binding:"required_without=SessionID"`
+	Date       string `json:"date" binding:"required_without=SessionID"` // Format: YYYY-MM-DD
+}
@@ -2,37 +2,107 @@ package routes
 
 import (
 	"reservation-api/internal/config"
+	"reservation-api/internal/gateway"
 	"reservation-api/internal/handlers"
+	"reservation-api/internal/loginlimit"
 	"reservation-api/internal/middleware"
+	"reservation-api/internal/models"
+	"reservation-api/internal/notifier"
+	"reservation-api/internal/oidc"
 	"reservation-api/internal/repository"
+	"reservation-api/internal/scheduler"
 	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// SetupRoutes configures all application routes
-func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
+// SetupRoutes configures all application routes and registers the background jobs
+// of the services it creates
+func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config, jobs *scheduler.Scheduler) {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	courtRepo := repository.NewCourtRepository(db)
 	timeslotRepo := repository.NewTimeslotRepository(db)
 	reservationRepo := repository.NewReservationRepository(db)
 	paymentRepo := repository.NewPaymentRepository(db)
+	paymentNotificationRepo := repository.NewPaymentNotificationRepository(db)
+	refundRepo := repository.NewRefundRepository(db)
+	pricingRuleRepo := repository.NewPricingRuleRepository(db)
+	creditPackageRepo := repository.NewCreditPackageRepository(db)
+	creditRepo := repository.NewCreditRepository(db)
+	membershipPlanRepo := repository.NewMembershipPlanRepository(db)
+	membershipRepo := repository.NewMembershipRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	instructorRepo := repository.NewInstructorRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	closureRepo := repository.NewClosureRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	authSessionRepo := repository.NewAuthSessionRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	loginFailureRepo := repository.NewLoginFailureRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+
+	// Initialize payment gateway
+	paymentGateway := gateway.New(cfg)
+
+	// Initialize notification channels
+	notifiers := notifier.New(cfg)
+
+	// Initialize login brute-force protection
+	loginLimiter := loginlimit.NewFromConfig(cfg, loginAttemptRepo)
+
+	// Initialize OpenID Connect sign-in providers
+	oidcProviders := oidc.New(cfg)
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, cfg)
-	reservationService := services.NewReservationService(reservationRepo, courtRepo, timeslotRepo)
-	paymentService := services.NewPaymentService(paymentRepo, reservationRepo, cfg)
+	pricingService := services.NewPricingService(pricingRuleRepo, sessionRepo, cfg)
+	notificationService := services.NewNotificationService(outboxRepo, reservationRepo, notifiers, cfg, utils.SystemClock{})
+	waitlistService := services.NewWaitlistService(waitlistRepo, reservationRepo, sessionRepo, notificationService, cfg, utils.SystemClock{})
+	authService := services.NewAuthService(userRepo, authSessionRepo, userTokenRepo, loginFailureRepo, userIdentityRepo, reservationRepo, membershipRepo, waitlistService, notificationService, loginLimiter, oidcProviders, cfg, utils.SystemClock{})
+	refundService := services.NewRefundService(refundRepo, paymentRepo, notificationService, paymentGateway, cfg, utils.SystemClock{})
+	ticketService := services.NewTicketService(reservationRepo, cfg)
+	attendanceService := services.NewAttendanceService(reservationRepo, sessionRepo, ticketService, cfg, utils.SystemClock{})
+	reservationService := services.NewReservationService(reservationRepo, userRepo, sessionRepo, closureRepo, creditRepo, membershipRepo, refundService, waitlistService, attendanceService, notificationService, cfg, utils.SystemClock{})
+	paymentService := services.NewPaymentService(paymentRepo, reservationRepo, paymentNotificationRepo, creditRepo, membershipRepo, pricingService, waitlistService, notificationService, refundService, paymentGateway, cfg, utils.SystemClock{})
+	creditService := services.NewCreditService(creditPackageRepo, creditRepo, userRepo, paymentService)
+	membershipService := services.NewMembershipService(membershipPlanRepo, membershipRepo, userRepo, paymentService, notificationService, cfg, utils.SystemClock{})
+	instructorService := services.NewInstructorService(instructorRepo, sessionRepo, reservationRepo, userRepo, cfg)
+	sessionService := services.NewSessionService(sessionRepo, closureRepo, reservationRepo, courtRepo, timeslotRepo, creditRepo, instructorService, refundService, waitlistService, notificationService, cfg, utils.SystemClock{})
+	closureService := services.NewClosureService(closureRepo, courtRepo, timeslotRepo, sessionService)
+	expiryService := services.NewExpiryService(reservationRepo, waitlistService, notificationService, cfg, utils.SystemClock{})
+
+	// Register background jobs
+	jobs.Every("expire-holds", cfg.HoldCheckInterval, expiryService.ExpireHolds)
+	jobs.Every("expire-waitlist", cfg.HoldCheckInterval, waitlistService.ExpirePastEntries)
+	jobs.Every("renew-memberships", cfg.MembershipCheckInterval, membershipService.RenewMemberships)
+	jobs.Every("generate-sessions", cfg.SessionGenerateInterval, sessionService.GenerateSessions)
+	jobs.Every("settle-attendance", cfg.HoldCheckInterval, attendanceService.SettleAttendance)
+	jobs.Every("send-reminders", cfg.HoldCheckInterval, notificationService.SendReminders)
+	jobs.Every("deliver-notifications", cfg.OutboxInterval, notificationService.DeliverOutbox)
+	jobs.Every("purge-refresh-tokens", cfg.MembershipCheckInterval, authService.PurgeExpiredTokens)
+	jobs.Every("purge-login-attempts", cfg.MembershipCheckInterval, authService.PurgeLoginAttempts)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	reservationHandler := handlers.NewReservationHandler(reservationService, courtRepo, timeslotRepo)
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
-	adminHandler := handlers.NewAdminHandler(courtRepo, timeslotRepo)
+	pricingHandler := handlers.NewPricingHandler(pricingService)
+	creditHandler := handlers.NewCreditHandler(creditService)
+	membershipHandler := handlers.NewMembershipHandler(membershipService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
+	instructorHandler := handlers.NewInstructorHandler(instructorService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	closureHandler := handlers.NewClosureHandler(closureService)
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceService)
+	ticketHandler := handlers.NewTicketHandler(ticketService)
+	adminHandler := handlers.NewAdminHandler(courtRepo, timeslotRepo, userRepo, refundService, notificationService, authService)
 
 	// Setup middleware
 	router.Use(middleware.CORSMiddleware(cfg))
+	authMiddleware := middleware.AuthMiddleware(cfg, authService)
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -50,16 +120,33 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/password/forgot", authHandler.ForgotPassword)
+			auth.POST("/password/reset", authHandler.ResetPassword)
+			auth.POST("/email/verify", authHandler.VerifyEmail)
+			auth.GET("/oidc/providers", authHandler.GetOIDCProviders)
+			auth.GET("/oidc/:provider/authorize", authHandler.AuthorizeOIDC)
+			auth.POST("/oidc/:provider/callback", authHandler.OIDCCallback)
+			auth.POST("/oidc/:provider/token", authHandler.OIDCSignIn)
 		}
 
 		// Public routes - Browse available slots
 		v1.GET("/dates", reservationHandler.GetAvailableDates)
 		v1.GET("/timeslots", reservationHandler.GetTimeslots)
 		v1.GET("/courts", reservationHandler.GetAvailableCourts)
+		v1.GET("/sessions", sessionHandler.GetSessions)
+		v1.GET("/price", pricingHandler.GetPriceQuote)
+		v1.GET("/packages", creditHandler.GetPackages)
+		v1.GET("/membership-plans", membershipHandler.GetPlans)
+		v1.GET("/instructors", instructorHandler.GetInstructors)
+
+		// Public routes - Payment gateway notifications (authenticated by signature)
+		v1.POST("/payments/notification", paymentHandler.PaymentCallback)
 
 		// Protected routes - Require authentication
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(cfg))
+		protected.Use(authMiddleware)
 		{
 			// Reservations
 			reservations := protected.Group("/reservations")
@@ -68,14 +155,45 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				reservations.GET("", reservationHandler.GetUserReservations)
 				reservations.GET("/:id", reservationHandler.GetReservation)
 				reservations.PUT("/:id/cancel", reservationHandler.CancelReservation)
+				reservations.GET("/:id/ticket", ticketHandler.GetTicket)
+				reservations.GET("/:id/ticket/qr", ticketHandler.GetTicketQR)
+			}
+
+			// Attendance
+			protected.GET("/attendance", attendanceHandler.GetSummary)
+
+			// Waitlist
+			waitlist := protected.Group("/waitlist")
+			{
+				waitlist.POST("", waitlistHandler.JoinWaitlist)
+				waitlist.GET("", waitlistHandler.GetWaitlist)
+				waitlist.DELETE("/:id", waitlistHandler.LeaveWaitlist)
 			}
 
 			// Payments
 			payments := protected.Group("/payments")
 			{
 				payments.POST("/create", paymentHandler.CreatePayment)
-				payments.POST("/callback", paymentHandler.PaymentCallback)
 				payments.GET("/:id", paymentHandler.GetPayment)
+				payments.POST("/:id/refresh", paymentHandler.RefreshPaymentStatus)
+				payments.POST("/:id/simulate", paymentHandler.SimulatePayment)
+			}
+
+			// Class packages and credits
+			credits := protected.Group("/credits")
+			{
+				credits.GET("", creditHandler.GetWallet)
+				credits.GET("/transactions", creditHandler.GetTransactions)
+				credits.POST("/purchase", creditHandler.PurchasePackage)
+			}
+
+			// Memberships
+			memberships := protected.Group("/memberships")
+			{
+				memberships.GET("", membershipHandler.GetMemberships)
+				memberships.POST("", membershipHandler.Subscribe)
+				memberships.POST("/:id/renew", membershipHandler.RenewMembership)
+				memberships.PUT("/:id/auto-renew", membershipHandler.UpdateAutoRenew)
 			}
 
 			// User profile
@@ -83,12 +201,34 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 			{
 				profile.GET("", authHandler.GetProfile)
 				profile.PUT("", authHandler.UpdateProfile)
+				profile.DELETE("", authHandler.DeleteAccount)
+				profile.PUT("/password", authHandler.ChangePassword)
+				profile.PUT("/email", authHandler.ChangeEmail)
+				profile.POST("/email/verification", authHandler.ResendVerification)
 			}
 		}
 
+		// Instructor routes
+		instructor := v1.Group("/instructor")
+		instructor.Use(authMiddleware, middleware.RequireRole(models.RoleInstructor))
+		{
+			instructor.GET("/roster", instructorHandler.GetRoster)
+		}
+
+		// Front desk routes - Check-in
+		desk := v1.Group("/desk")
+		desk.Use(authMiddleware, middleware.RequireRole(models.RoleFrontDesk, models.RoleAdmin))
+		{
+			desk.GET("/sessions", sessionHandler.GetSessions)
+			desk.GET("/sessions/:id/roster", attendanceHandler.GetSessionRoster)
+			desk.POST("/reservations/:id/check-in", attendanceHandler.CheckIn)
+			desk.POST("/check-in", attendanceHandler.CheckInByTicket)
+			desk.POST("/tickets/verify", ticketHandler.VerifyTicket)
+		}
+
 		// Admin routes - For managing courts and timeslots
 		admin := v1.Group("/admin")
-		// admin.Use(middleware.AdminMiddleware()) // TODO: Implement admin middleware
+		admin.Use(authMiddleware, middleware.AdminMiddleware())
 		{
 			// Courts management
 			courts := admin.Group("/courts")
@@ -108,13 +248,104 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				timeslots.DELETE("/:id", adminHandler.DeleteTimeslot)
 			}
 
+			// Instructors management
+			instructors := admin.Group("/instructors")
+			{
+				instructors.GET("", instructorHandler.GetAllInstructors)
+				instructors.POST("", instructorHandler.CreateInstructor)
+				instructors.PUT("/:id", instructorHandler.UpdateInstructor)
+				instructors.DELETE("/:id", instructorHandler.DeleteInstructor)
+			}
+
+			// Instructor assignment to class sessions
+			admin.PUT("/class-assignments", instructorHandler.AssignInstructor)
+			admin.DELETE("/class-assignments", instructorHandler.UnassignInstructor)
+
+			// Weekly class schedule
+			classTemplates := admin.Group("/class-templates")
+			{
+				classTemplates.GET("", sessionHandler.GetTemplates)
+				classTemplates.POST("", sessionHandler.CreateTemplate)
+				classTemplates.PUT("/:id", sessionHandler.UpdateTemplate)
+				classTemplates.DELETE("/:id", sessionHandler.DeleteTemplate)
+			}
+
+			// Class sessions management
+			sessions := admin.Group("/sessions")
+			{
+				sessions.GET("", sessionHandler.GetSessions)
+				sessions.POST("", sessionHandler.CreateSession)
+				sessions.PUT("/:id", sessionHandler.UpdateSession)
+				sessions.POST("/:id/cancel", sessionHandler.CancelSession)
+			}
+
+			// Studio holidays and closures
+			closures := admin.Group("/closures")
+			{
+				closures.GET("", closureHandler.GetClosures)
+				closures.POST("", closureHandler.CreateClosure)
+				closures.DELETE("/:id", closureHandler.DeleteClosure)
+			}
+
+			// Users management
+			users := admin.Group("/users")
+			{
+				users.GET("", adminHandler.GetUsers)
+				users.PUT("/:id/role", adminHandler.UpdateUserRole)
+				users.POST("/:id/unlock", adminHandler.UnlockUser)
+			}
+
+			// Pricing rules management
+			pricingRules := admin.Group("/pricing-rules")
+			{
+				pricingRules.GET("", pricingHandler.GetRules)
+				pricingRules.POST("", pricingHandler.CreateRule)
+				pricingRules.PUT("/:id", pricingHandler.UpdateRule)
+				pricingRules.DELETE("/:id", pricingHandler.DeleteRule)
+			}
+
+			// Class packages management
+			packages := admin.Group("/packages")
+			{
+				packages.GET("", creditHandler.GetPackages)
+				packages.POST("", creditHandler.CreatePackage)
+				packages.PUT("/:id", creditHandler.UpdatePackage)
+				packages.DELETE("/:id", creditHandler.DeletePackage)
+			}
+
+			// Membership plans management
+			membershipPlans := admin.Group("/membership-plans")
+			{
+				membershipPlans.GET("", membershipHandler.GetPlans)
+				membershipPlans.POST("", membershipHandler.CreatePlan)
+				membershipPlans.PUT("/:id", membershipHandler.UpdatePlan)
+				membershipPlans.DELETE("/:id", membershipHandler.DeletePlan)
+			}
+
+			// Refunds management
+			refunds := admin.Group("/refunds")
+			{
+				refunds.GET("", adminHandler.GetRefunds)
+				refunds.POST("/:id/retry", adminHandler.RetryRefund)
+			}
+
+			// Notifications
+			notifications := admin.Group("/notifications")
+			{
+				notifications.GET("", adminHandler.GetNotifications)
+				notifications.POST("/:id/retry", adminHandler.RetryNotification)
+			}
+
+			// Failed login audit
+			admin.GET("/login-failures", adminHandler.GetLoginFailures)
+
 			// Dashboard statistics
 			admin.GET("/stats", adminHandler.GetStatistics)
 		}
 	}
 
 	// Legacy routes for backward compatibility
-	setupLegacyRoutes(router, authHandler, reservationHandler, paymentHandler, adminHandler, cfg)
+	setupLegacyRoutes(router, authHandler, reservationHandler, paymentHandler, adminHandler, authMiddleware)
 }
 
 // setupLegacyRoutes sets up backward compatible routes
@@ -124,7 +355,7 @@ func setupLegacyRoutes(
 	reservationHandler *handlers.ReservationHandler,
 	paymentHandler *handlers.PaymentHandler,
 	adminHandler *handlers.AdminHandler,
-	cfg *config.Config,
+	authMiddleware gin.HandlerFunc,
 ) {
 	api := router.Group("/api")
 	{
@@ -136,24 +367,25 @@ func setupLegacyRoutes(
 		api.GET("/dates", reservationHandler.GetAvailableDates)
 		api.GET("/timeslots", reservationHandler.GetTimeslots)
 		api.GET("/courts", reservationHandler.GetAvailableCourts)
+		api.POST("/payment/notification", paymentHandler.PaymentCallback)
 
 		// Protected
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware(cfg))
+		protected.Use(authMiddleware)
 		{
 			protected.POST("/reservations", reservationHandler.CreateReservation)
 			protected.GET("/reservations", reservationHandler.GetUserReservations)
 			protected.GET("/reservations/:id", reservationHandler.GetReservation)
 
 			protected.POST("/payment/create", paymentHandler.CreatePayment)
-			protected.POST("/payment/callback", paymentHandler.PaymentCallback)
 		}
 
 		// Admin
 		admin := api.Group("/admin")
+		admin.Use(authMiddleware, middleware.AdminMiddleware())
 		{
 			admin.POST("/courts", adminHandler.CreateCourt)
 			admin.POST("/timeslots", adminHandler.CreateTimeslot)
 		}
 	}
-}
\ No newline at end of file
+}
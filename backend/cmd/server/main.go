@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"reservation-api/api/routes"
 	"reservation-api/internal/config"
 	"reservation-api/internal/database"
+	"reservation-api/internal/scheduler"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,7 +34,7 @@ func main() {
 	db := database.InitDB(cfg)
 
 	// Run database seeding
-	database.SeedData(db)
+	database.SeedData(db, cfg)
 
 	// Setup Gin mode
 	if cfg.AppEnv == "production" {
@@ -34,17 +42,60 @@ func main() {
 	}
 
 	// Setup router
-	router := gin.Default()
+	router, err := newRouter(cfg)
+	if err != nil {
+		log.Fatal("Failed to set up router:", err)
+	}
+
+	// Initialize routes and background jobs
+	jobs := scheduler.New()
+	routes.SetupRoutes(router, db, cfg, jobs)
+
+	// Stop on interrupt or termination signal
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Initialize routes
-	routes.SetupRoutes(router, db, cfg)
+	// Start background jobs
+	jobs.Start(ctx)
 
 	// Start server
-	log.Printf("🚀 Server running on port %s", cfg.Port)
-	log.Printf("📝 API Documentation: http://localhost:%s/api/docs", cfg.Port)
-	log.Printf("🌍 Environment: %s", cfg.AppEnv)
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("🚀 Server running on port %s", cfg.Port)
+		log.Printf("📝 API Documentation: http://localhost:%s/api/docs", cfg.Port)
+		log.Printf("🌍 Environment: %s", cfg.AppEnv)
+
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
 
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	<-ctx.Done()
+	log.Println("🛑 Shutting down server...")
+
+	// Give in-flight requests time to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("⚠️  Server forced to shutdown:", err)
+	}
+
+	jobs.Stop()
+
+	log.Println("✅ Server exited")
+}
+
+// newRouter creates the Gin engine. Client IPs, used to limit login attempts, are
+// only taken from X-Forwarded-For when the request comes from a trusted proxy.
+func newRouter(cfg *config.Config) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
 	}
-}
\ No newline at end of file
+	return router, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reservation-api/internal/config"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientIPFromTrustedProxiesOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		{"no trusted proxies", nil, "192.0.2.1"},
+		{"untrusted peer", []string{"10.0.0.0/8"}, "192.0.2.1"},
+		{"trusted peer", []string{"192.0.2.0/24"}, "203.0.113.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, err := newRouter(&config.Config{TrustedProxies: tt.proxies})
+			if err != nil {
+				t.Fatal(err)
+			}
+			router.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = "192.0.2.1:41000"
+			req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("client IP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRouterRejectsInvalidProxy(t *testing.T) {
+	if _, err := newRouter(&config.Config{TrustedProxies: []string{"not-an-ip"}}); err == nil {
+		t.Error("invalid trusted proxy accepted")
+	}
+}
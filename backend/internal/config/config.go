@@ -3,6 +3,10 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Time zone database for hosts without one
 
 	"github.com/joho/godotenv"
 )
@@ -13,19 +17,125 @@ type Config struct {
 	DatabaseURL string
 
 	// Server
-	Port   string
-	AppEnv string
+	Port           string
+	AppEnv         string
+	TrustedProxies []string // Proxies whose X-Forwarded-For header gives the client IP; none by default
+
+	// Studio time zone. Class dates and timeslot times are local to it.
+	Location *time.Location
 
 	// JWT
-	JWTSecret string
+	JWTSecret       string
+	AccessTokenTTL  time.Duration // Lifetime of access tokens
+	RefreshTokenTTL time.Duration // Lifetime of refresh tokens; each refresh issues a new one
+
+	// Account emails
+	PasswordResetTTL     time.Duration // Lifetime of password reset links
+	EmailVerificationTTL time.Duration // Lifetime of email verification links
+	RequireVerifiedEmail bool          // Block booking until the email address is verified
+
+	// Login brute-force protection
+	LoginMaxFailures      int           // Failed logins of an account before it is locked out
+	LoginMaxFailuresPerIP int           // Failed logins from an IP address before it is locked out
+	LoginFailureWindow    time.Duration // Failed logins older than this are forgotten
+	LoginLockout          time.Duration // How long a lockout lasts
+	LoginLimitStore       string        // Where failed logins are counted: database or memory
+
+	// OpenID Connect sign-in providers (Google, or any other OIDC issuer)
+	OIDCProviders []OIDCProvider
+
+	// Signing key of reservation tickets, defaults to the JWT secret
+	TicketSecret string
+
+	// Initial admin account (seeded on startup when set)
+	AdminEmail    string
+	AdminPassword string
+
+	// Payment gateway: "midtrans" or "fake"
+	PaymentGateway string
+
+	// Pricing: base price of a session before pricing rules (IDR)
+	DefaultSessionPrice float64
 
 	// Midtrans
-	MidtransServerKey string
-	MidtransClientKey string
-	MidtransBaseURL   string
+	MidtransServerKey  string
+	MidtransClientKey  string
+	MidtransBaseURL    string // Snap API
+	MidtransAPIBaseURL string // Core API (status, refund)
 
 	// CORS
 	AllowedOrigins []string
+	FrontendURL    string
+
+	// Reservation holds
+	HoldExpiry        time.Duration // How long an unpaid reservation keeps its seat
+	HoldCheckInterval time.Duration // How often expired holds are cleaned up
+
+	// Cancellation policy
+	FullRefundWindow     time.Duration // Cancelling at least this long before class start refunds in full
+	PartialRefundPercent int           // Refund share when cancelling later but before class start
+
+	// Booking rules (studio defaults, courts and sessions can override them)
+	BookingMinLead        time.Duration // Booking closes this long before class start
+	BookingMaxAdvanceDays int           // Booking opens this many days before class start
+	CancelDeadline        time.Duration // Cancelling later than this before class start is a late cancel
+	AllowLateCancel       bool          // Late cancels are allowed and flagged instead of refused
+
+	// Attendance
+	CheckInOpens     time.Duration // Check-in opens this long before class start
+	NoShowLimit      int           // No-shows within the window that block booking (0 disables)
+	NoShowWindowDays int           // Days back over which no-shows are counted
+
+	// Waitlist
+	WaitlistClaimWindow time.Duration // How long a seat offered from the waitlist is held for payment
+
+	// Memberships
+	MembershipRenewalLead   time.Duration // Renewal payments are created this long before a period ends
+	MembershipCheckInterval time.Duration // How often memberships are renewed and lapsed
+
+	// Class sessions
+	SessionHorizon          int           // Days ahead for which sessions are generated from templates
+	SessionGenerateInterval time.Duration // How often sessions are generated
+
+	// Studio name shown in SMS and WhatsApp messages
+	StudioName string
+
+	// SMTP (emails are only logged when no host is set)
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// SMS via Twilio (messages are only logged when no account is set)
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	SMSFrom          string
+
+	// WhatsApp Business Cloud API (messages are only logged when no token is set)
+	WhatsAppToken            string
+	WhatsAppPhoneNumberID    string
+	WhatsAppTemplate         string // Approved template for messages outside the 24 hour window
+	WhatsAppTemplateLanguage string
+
+	// Calling code added to national phone numbers (starting with 0)
+	DefaultCountryCode string
+
+	// Notifications
+	ReminderLead            time.Duration // Class reminders are sent this long before class start
+	OutboxInterval          time.Duration // How often pending notifications are delivered
+	NotificationMaxAttempts int           // Delivery attempts before a notification is marked failed
+}
+
+// OIDCProvider configures sign-in with an OpenID Connect provider
+type OIDCProvider struct {
+	Name         string   // Used in URLs, e.g. google
+	Issuer       string   // Issuer URL, where the discovery document is served
+	ClientID     string   // OAuth client of the web sign-in flow
+	ClientSecret string   // Secret of the web client
+	RedirectURL  string   // Frontend page the provider sends the user back to
+	Audiences    []string // Other clients whose ID tokens are accepted, e.g. the mobile apps
+	Scopes       []string
 }
 
 // LoadConfig loads configuration from environment variables
@@ -38,8 +148,27 @@ func LoadConfig() *Config {
 	// Determine Midtrans environment
 	appEnv := getEnv("APP_ENV", "development")
 	midtransBaseURL := "https://app.sandbox.midtrans.com/snap/v1"
+	midtransAPIBaseURL := "https://api.sandbox.midtrans.com/v2"
 	if appEnv == "production" {
 		midtransBaseURL = "https://app.midtrans.com/snap/v1"
+		midtransAPIBaseURL = "https://api.midtrans.com/v2"
+	}
+
+	// Use the fake gateway unless Midtrans credentials are available
+	midtransServerKey := getEnv("MIDTRANS_SERVER_KEY", "")
+	midtransClientKey := getEnv("MIDTRANS_CLIENT_KEY", "")
+	defaultGateway := "fake"
+	if midtransServerKey != "" && midtransClientKey != "" {
+		defaultGateway = "midtrans"
+	}
+
+	frontendURL := getEnv("FRONTEND_URL", "http://localhost:3000")
+	jwtSecret := getEnv("JWT_SECRET", "your-secret-key-change-this-in-production")
+
+	timezone := getEnv("STUDIO_TIMEZONE", "Asia/Jakarta")
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Fatalf("❌ Unknown STUDIO_TIMEZONE %q: %v", timezone, err)
 	}
 
 	config := &Config{
@@ -47,23 +176,117 @@ func LoadConfig() *Config {
 		DatabaseURL: getEnv("DATABASE_URL", "host=localhost user=postgres password=postgres dbname=pilates_db port=5432 sslmode=disable"),
 
 		// Server
-		Port:   getEnv("PORT", "8080"),
-		AppEnv: appEnv,
+		Port:           getEnv("PORT", "8080"),
+		AppEnv:         appEnv,
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		// Studio
+		Location: location,
 
 		// JWT
-		JWTSecret: getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+		JWTSecret:       jwtSecret,
+		AccessTokenTTL:  time.Duration(getEnvInt("ACCESS_TOKEN_MINUTES", 15)) * time.Minute,
+		RefreshTokenTTL: time.Duration(getEnvInt("REFRESH_TOKEN_DAYS", 30)) * 24 * time.Hour,
+
+		// Account emails
+		PasswordResetTTL:     time.Duration(getEnvInt("PASSWORD_RESET_MINUTES", 60)) * time.Minute,
+		EmailVerificationTTL: time.Duration(getEnvInt("EMAIL_VERIFICATION_HOURS", 48)) * time.Hour,
+		RequireVerifiedEmail: getEnvBool("REQUIRE_VERIFIED_EMAIL", false),
+
+		// Login brute-force protection
+		LoginMaxFailures:      getEnvInt("LOGIN_MAX_FAILURES", 5),
+		LoginMaxFailuresPerIP: getEnvInt("LOGIN_MAX_FAILURES_PER_IP", 20),
+		LoginFailureWindow:    time.Duration(getEnvInt("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute,
+		LoginLockout:          time.Duration(getEnvInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		LoginLimitStore:       getEnv("LOGIN_LIMIT_STORE", "database"),
+
+		// OpenID Connect
+		OIDCProviders: loadOIDCProviders(frontendURL),
+
+		// Tickets
+		TicketSecret: getEnv("TICKET_SECRET", jwtSecret),
+
+		// Initial admin account
+		AdminEmail:    getEnv("ADMIN_EMAIL", ""),
+		AdminPassword: getEnv("ADMIN_PASSWORD", ""),
+
+		// Payment gateway
+		PaymentGateway: getEnv("PAYMENT_GATEWAY", defaultGateway),
+
+		// Pricing
+		DefaultSessionPrice: float64(getEnvInt("DEFAULT_SESSION_PRICE", 100000)),
 
 		// Midtrans
-		MidtransServerKey: getEnv("MIDTRANS_SERVER_KEY", ""),
-		MidtransClientKey: getEnv("MIDTRANS_CLIENT_KEY", ""),
-		MidtransBaseURL:   midtransBaseURL,
+		MidtransServerKey:  midtransServerKey,
+		MidtransClientKey:  midtransClientKey,
+		MidtransBaseURL:    midtransBaseURL,
+		MidtransAPIBaseURL: midtransAPIBaseURL,
 
 		// CORS
 		AllowedOrigins: []string{
 			"http://localhost:3000",
 			"http://localhost:3001",
-			getEnv("FRONTEND_URL", "http://localhost:3000"),
+			frontendURL,
 		},
+		FrontendURL: frontendURL,
+
+		// Reservation holds
+		HoldExpiry:        time.Duration(getEnvInt("HOLD_EXPIRY_MINUTES", 30)) * time.Minute,
+		HoldCheckInterval: time.Duration(getEnvInt("HOLD_CHECK_INTERVAL_SECONDS", 60)) * time.Second,
+
+		// Cancellation policy
+		FullRefundWindow:     time.Duration(getEnvInt("FULL_REFUND_HOURS", 24)) * time.Hour,
+		PartialRefundPercent: getEnvInt("PARTIAL_REFUND_PERCENT", 50),
+
+		// Booking rules
+		BookingMinLead:        time.Duration(getEnvInt("BOOKING_MIN_LEAD_MINUTES", 0)) * time.Minute,
+		BookingMaxAdvanceDays: getEnvInt("BOOKING_MAX_ADVANCE_DAYS", 30),
+		CancelDeadline:        time.Duration(getEnvInt("CANCEL_DEADLINE_HOURS", 0)) * time.Hour,
+		AllowLateCancel:       getEnvBool("ALLOW_LATE_CANCEL", true),
+
+		// Attendance
+		CheckInOpens:     time.Duration(getEnvInt("CHECK_IN_OPENS_MINUTES", 60)) * time.Minute,
+		NoShowLimit:      getEnvInt("NO_SHOW_LIMIT", 0),
+		NoShowWindowDays: getEnvInt("NO_SHOW_WINDOW_DAYS", 30),
+
+		// Waitlist
+		WaitlistClaimWindow: time.Duration(getEnvInt("WAITLIST_CLAIM_MINUTES", 60)) * time.Minute,
+
+		// Memberships
+		MembershipRenewalLead:   time.Duration(getEnvInt("MEMBERSHIP_RENEWAL_DAYS", 3)) * 24 * time.Hour,
+		MembershipCheckInterval: time.Duration(getEnvInt("MEMBERSHIP_CHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+
+		// Class sessions
+		SessionHorizon:          getEnvInt("SESSION_HORIZON_DAYS", 30),
+		SessionGenerateInterval: time.Duration(getEnvInt("SESSION_GENERATE_INTERVAL_MINUTES", 60)) * time.Minute,
+
+		// Studio name
+		StudioName: getEnv("STUDIO_NAME", "Pilates Studio"),
+
+		// SMTP
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 1025),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "Pilates Studio <no-reply@pilates.local>"),
+
+		// SMS
+		TwilioAccountSID: getEnv("TWILIO_ACCOUNT_SID", ""),
+		TwilioAuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
+		SMSFrom:          getEnv("SMS_FROM", ""),
+
+		// WhatsApp
+		WhatsAppToken:            getEnv("WHATSAPP_TOKEN", ""),
+		WhatsAppPhoneNumberID:    getEnv("WHATSAPP_PHONE_NUMBER_ID", ""),
+		WhatsAppTemplate:         getEnv("WHATSAPP_TEMPLATE", ""),
+		WhatsAppTemplateLanguage: getEnv("WHATSAPP_TEMPLATE_LANGUAGE", "id"),
+
+		DefaultCountryCode: getEnv("DEFAULT_COUNTRY_CODE", "62"),
+
+		// Notifications
+		ReminderLead:            time.Duration(getEnvInt("REMINDER_HOURS", 24)) * time.Hour,
+		OutboxInterval:          time.Duration(getEnvInt("OUTBOX_INTERVAL_SECONDS", 30)) * time.Second,
+		NotificationMaxAttempts: getEnvInt("NOTIFICATION_MAX_ATTEMPTS", 8),
 	}
 
 	// Validate required configs
@@ -78,11 +301,77 @@ func (c *Config) validate() {
 		log.Fatal("❌ JWT_SECRET must be set in production environment")
 	}
 
-	if c.AppEnv == "production" {
-		if c.MidtransServerKey == "" || c.MidtransClientKey == "" {
-			log.Println("⚠️  Warning: Midtrans credentials not set. Payment features will not work.")
+	if c.PaymentGateway != "midtrans" && c.PaymentGateway != "fake" {
+		log.Fatalf("❌ Unknown PAYMENT_GATEWAY %q (use midtrans or fake)", c.PaymentGateway)
+	}
+
+	if c.PaymentGateway == "midtrans" && c.MidtransServerKey == "" {
+		log.Fatal("❌ MIDTRANS_SERVER_KEY must be set when PAYMENT_GATEWAY is midtrans")
+	}
+
+	if c.LoginLimitStore != "database" && c.LoginLimitStore != "memory" {
+		log.Fatalf("❌ Unknown LOGIN_LIMIT_STORE %q (use database or memory)", c.LoginLimitStore)
+	}
+
+	for _, provider := range c.OIDCProviders {
+		if provider.Issuer == "" || provider.ClientID == "" {
+			log.Fatalf("❌ OIDC provider %q needs an issuer and a client ID", provider.Name)
+		}
+	}
+
+	intervals := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HOLD_CHECK_INTERVAL_SECONDS", c.HoldCheckInterval},
+		{"MEMBERSHIP_CHECK_INTERVAL_MINUTES", c.MembershipCheckInterval},
+		{"SESSION_GENERATE_INTERVAL_MINUTES", c.SessionGenerateInterval},
+		{"OUTBOX_INTERVAL_SECONDS", c.OutboxInterval},
+	}
+	for _, interval := range intervals {
+		if interval.value <= 0 {
+			log.Fatalf("❌ %s must be greater than zero", interval.name)
 		}
 	}
+
+	if c.TwilioAccountSID != "" && c.SMSFrom == "" {
+		log.Fatal("❌ SMS_FROM must be set when TWILIO_ACCOUNT_SID is set")
+	}
+
+	if c.AppEnv == "production" && c.PaymentGateway == "fake" {
+		log.Fatal("❌ The fake payment gateway cannot be used in production; set the Midtrans keys")
+	}
+}
+
+// loadOIDCProviders loads the providers listed in OIDC_PROVIDERS. Each is set up
+// with OIDC_<NAME>_* variables; Google's issuer is known and need not be set.
+func loadOIDCProviders(frontendURL string) []OIDCProvider {
+	var providers []OIDCProvider
+	for _, name := range getEnvList("OIDC_PROVIDERS") {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		defaultIssuer := ""
+		if name == "google" {
+			defaultIssuer = "https://accounts.google.com"
+		}
+
+		scopes := getEnvList(prefix + "SCOPES")
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+
+		providers = append(providers, OIDCProvider{
+			Name:         name,
+			Issuer:       strings.TrimRight(getEnv(prefix+"ISSUER", defaultIssuer), "/"),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", strings.TrimRight(frontendURL, "/")+"/auth/"+name+"/callback"),
+			Audiences:    getEnvList(prefix + "AUDIENCES"),
+			Scopes:       scopes,
+		})
+	}
+	return providers
 }
 
 // getEnv gets environment variable or returns default value
@@ -94,6 +383,35 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// getEnvInt gets integer environment variable or returns default value
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvBool gets boolean environment variable or returns default value
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvList gets a comma separated environment variable, skipping empty items
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // IsDevelopment checks if app is in development mode
 func (c *Config) IsDevelopment() bool {
 	return c.AppEnv == "development"
@@ -102,4 +420,4 @@ func (c *Config) IsDevelopment() bool {
 // IsProduction checks if app is in production mode
 func (c *Config) IsProduction() bool {
 	return c.AppEnv == "production"
-}
\ No newline at end of file
+}
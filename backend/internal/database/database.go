@@ -30,15 +30,15 @@ func InitDB(cfg *config.Config) *gorm.DB {
 	log.Println("✅ Database connected successfully")
 
 	// Auto migrate all models
-	if err := autoMigrate(db); err != nil {
+	if err := AutoMigrate(db); err != nil {
 		log.Fatal("❌ Failed to migrate database:", err)
 	}
 
 	return db
 }
 
-// autoMigrate runs database migrations
-func autoMigrate(db *gorm.DB) error {
+// AutoMigrate runs database migrations
+func AutoMigrate(db *gorm.DB) error {
 	log.Println("🔄 Running database migrations...")
 
 	err := db.AutoMigrate(
@@ -47,17 +47,61 @@ func autoMigrate(db *gorm.DB) error {
 		&models.Timeslot{},
 		&models.Reservation{},
 		&models.Payment{},
+		&models.PaymentNotification{},
+		&models.Refund{},
+		&models.PricingRule{},
+		&models.CreditPackage{},
+		&models.CreditPurchase{},
+		&models.CreditTransaction{},
+		&models.MembershipPlan{},
+		&models.Membership{},
+		&models.WaitlistEntry{},
+		&models.Instructor{},
+		&models.ClassTemplate{},
+		&models.ClassSession{},
+		&models.Closure{},
+		&models.OutboxMessage{},
+		&models.AuthSession{},
+		&models.RefreshToken{},
+		&models.UserToken{},
+		&models.LoginAttempt{},
+		&models.LoginFailure{},
+		&models.UserIdentity{},
 	)
 
 	if err != nil {
 		return err
 	}
 
+	if err := migrateClassSessions(db); err != nil {
+		return err
+	}
+
 	log.Println("✅ Database migrations completed")
 	return nil
 }
 
+// migrateClassSessions gives reservations from before class sessions existed the
+// session of their court, timeslot and date
+func migrateClassSessions(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO class_sessions (created_at, updated_at, court_id, timeslot_id, date, status)
+			SELECT DISTINCT NOW(), NOW(), court_id, timeslot_id, date, ? FROM reservations
+			WHERE session_id IS NULL
+			ON CONFLICT DO NOTHING`, models.SessionScheduled).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE reservations SET session_id = class_sessions.id
+			FROM class_sessions
+			WHERE reservations.session_id IS NULL
+			AND class_sessions.court_id = reservations.court_id
+			AND class_sessions.timeslot_id = reservations.timeslot_id
+			AND class_sessions.date = reservations.date`).Error
+	})
+}
+
 // GetDB returns database instance (for testing purposes)
 func GetDB(cfg *config.Config) *gorm.DB {
 	return InitDB(cfg)
-}
\ No newline at end of file
+}
@@ -0,0 +1,65 @@
+package database_test
+
+import (
+	"reservation-api/internal/database"
+	"reservation-api/internal/models"
+	"reservation-api/internal/testdb"
+	"testing"
+	"time"
+)
+
+func TestMigrateClassSessions(t *testing.T) {
+	db := testdb.Open(t)
+
+	user := &models.User{Name: "Member", Email: "member@example.com", Password: "x", IsActive: true}
+	court := &models.Court{Name: "Reformer", Capacity: 5, IsActive: true}
+	timeslot := &models.Timeslot{Time: "07:00", Duration: 60, IsActive: true}
+	for _, record := range []interface{}{user, court, timeslot} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two bookings of the same class from before sessions existed
+	date := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	var reservations []*models.Reservation
+	for i := 0; i < 2; i++ {
+		reservation := &models.Reservation{UserID: user.ID, CourtID: court.ID, TimeslotID: timeslot.ID, Date: date, Status: models.StatusConfirmed}
+		if err := db.Create(reservation).Error; err != nil {
+			t.Fatal(err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err := database.AutoMigrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var sessions []models.ClassSession
+	if err := db.Find(&sessions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].CourtID != court.ID || sessions[0].TimeslotID != timeslot.ID || !sessions[0].Date.Equal(date) {
+		t.Fatalf("sessions = %+v, want one session of the class", sessions)
+	}
+	for _, reservation := range reservations {
+		if err := db.First(reservation, reservation.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if reservation.SessionID == nil || *reservation.SessionID != sessions[0].ID {
+			t.Errorf("reservation %d has session %v, want %d", reservation.ID, reservation.SessionID, sessions[0].ID)
+		}
+	}
+
+	// Running the migrations again changes nothing
+	if err := database.AutoMigrate(db); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	if err := db.Model(&models.ClassSession{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("%d sessions after migrating twice, want 1", count)
+	}
+}
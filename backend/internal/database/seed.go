@@ -2,31 +2,74 @@ package database
 
 import (
 	"log"
+	"reservation-api/internal/config"
 	"reservation-api/internal/models"
+	"time"
 
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 // SeedData seeds initial data into database
-func SeedData(db *gorm.DB) {
+func SeedData(db *gorm.DB, cfg *config.Config) {
 	log.Println("🌱 Seeding database...")
 
+	// Seed admin account
+	seedAdmin(db, cfg)
+
 	// Check if data already exists
 	var courtCount int64
 	db.Model(&models.Court{}).Count(&courtCount)
 
 	if courtCount > 0 {
-		log.Println("ℹ️  Database already seeded, skipping...")
+		log.Println("ℹ️  Courts and timeslots already seeded, skipping...")
+	} else {
+		// Seed courts
+		seedCourts(db)
+
+		// Seed timeslots
+		seedTimeslots(db)
+	}
+
+	// Seed the weekly class schedule
+	seedClassTemplates(db)
+
+	log.Println("✅ Database seeding completed")
+}
+
+// seedAdmin creates the initial admin account from configuration
+func seedAdmin(db *gorm.DB, cfg *config.Config) {
+	if cfg.AdminEmail == "" || cfg.AdminPassword == "" {
+		return
+	}
+
+	var count int64
+	db.Model(&models.User{}).Where("email = ?", cfg.AdminEmail).Count(&count)
+	if count > 0 {
 		return
 	}
 
-	// Seed courts
-	seedCourts(db)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(cfg.AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Printf("⚠️  Failed to hash admin password: %v", err)
+		return
+	}
 
-	// Seed timeslots
-	seedTimeslots(db)
+	verifiedAt := time.Now()
+	admin := models.User{
+		Name:            "Administrator",
+		Email:           cfg.AdminEmail,
+		Password:        string(hashedPassword),
+		Role:            models.RoleAdmin,
+		IsActive:        true,
+		EmailVerifiedAt: &verifiedAt,
+	}
 
-	log.Println("✅ Database seeding completed")
+	if err := db.Create(&admin).Error; err != nil {
+		log.Printf("⚠️  Failed to seed admin: %s - %v", admin.Email, err)
+	} else {
+		log.Printf("✓ Seeded admin: %s", admin.Email)
+	}
 }
 
 // seedCourts seeds court data
@@ -82,17 +125,115 @@ func seedTimeslots(db *gorm.DB) {
 	}
 }
 
+// seedClassTemplates schedules every active court for every active timeslot on every
+// weekday, matching the schedule from before class sessions existed. Skipped once
+// any template exists.
+func seedClassTemplates(db *gorm.DB) {
+	var templateCount int64
+	db.Model(&models.ClassTemplate{}).Count(&templateCount)
+	if templateCount > 0 {
+		return
+	}
+
+	var courts []models.Court
+	db.Where("is_active = ?", true).Find(&courts)
+
+	var timeslots []models.Timeslot
+	db.Where("is_active = ?", true).Find(&timeslots)
+
+	var templates []models.ClassTemplate
+	for _, court := range courts {
+		for _, timeslot := range timeslots {
+			for weekday := 0; weekday < 7; weekday++ {
+				templates = append(templates, models.ClassTemplate{
+					CourtID:    court.ID,
+					TimeslotID: timeslot.ID,
+					Weekday:    weekday,
+					IsActive:   true,
+				})
+			}
+		}
+	}
+
+	if len(templates) == 0 {
+		return
+	}
+
+	if err := db.Create(&templates).Error; err != nil {
+		log.Printf("⚠️  Failed to seed class templates: %v", err)
+	} else {
+		log.Printf("✓ Seeded %d class templates", len(templates))
+	}
+}
+
 // ClearDatabase clears all data from database (for testing)
 func ClearDatabase(db *gorm.DB) error {
 	log.Println("🗑️  Clearing database...")
 
 	// Delete in reverse order of foreign keys
+	if err := db.Exec("DELETE FROM outbox_messages").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM credit_transactions").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM waitlist_entries").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM refunds").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM payment_notifications").Error; err != nil {
+		return err
+	}
 	if err := db.Exec("DELETE FROM payments").Error; err != nil {
 		return err
 	}
+	if err := db.Exec("DELETE FROM credit_purchases").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM credit_packages").Error; err != nil {
+		return err
+	}
 	if err := db.Exec("DELETE FROM reservations").Error; err != nil {
 		return err
 	}
+	if err := db.Exec("DELETE FROM closures").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM class_sessions").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM class_templates").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM instructors").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM memberships").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM membership_plans").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM refresh_tokens").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM auth_sessions").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM login_attempts").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM login_failures").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM user_identities").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM user_tokens").Error; err != nil {
+		return err
+	}
 	if err := db.Exec("DELETE FROM users").Error; err != nil {
 		return err
 	}
@@ -105,4 +246,4 @@ func ClearDatabase(db *gorm.DB) error {
 
 	log.Println("✅ Database cleared")
 	return nil
-}
\ No newline at end of file
+}
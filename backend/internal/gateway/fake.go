@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reservation-api/api/dto"
+	"sync"
+	"time"
+)
+
+// fakeCharge is a charge recorded by the fake gateway
+type fakeCharge struct {
+	request  ChargeRequest
+	status   string
+	refunded float64
+}
+
+// FakeGateway is an in-process payment gateway for development and tests. Charges
+// never leave the process; call Settle, Deny or Expire to simulate the customer
+// finishing the payment page and receive the notification the gateway would send.
+// Notifications are signed with a key generated for the process, so only those
+// produced by the gateway itself are accepted.
+type FakeGateway struct {
+	baseURL    string
+	signingKey string
+
+	mu      sync.Mutex
+	charges map[string]*fakeCharge
+
+	// OnNotification, when set, receives every simulated notification
+	OnNotification func(notification dto.PaymentCallbackRequest)
+}
+
+// NewFakeGateway creates a new fake gateway whose payment pages live under baseURL
+func NewFakeGateway(baseURL string) *FakeGateway {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("fake gateway: generate signing key: %v", err))
+	}
+
+	return &FakeGateway{
+		baseURL:    baseURL,
+		signingKey: hex.EncodeToString(key),
+		charges:    make(map[string]*fakeCharge),
+	}
+}
+
+// Name returns the gateway identifier
+func (g *FakeGateway) Name() string {
+	return Fake
+}
+
+// CreateCharge records the charge and returns the dummy payment page
+func (g *FakeGateway) CreateCharge(req ChargeRequest) (*Charge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.charges[req.OrderID] = &fakeCharge{request: req, status: "pending"}
+
+	return &Charge{
+		Token:       "DUMMY_TOKEN_" + req.OrderID,
+		RedirectURL: fmt.Sprintf("%s/payment/dummy?transaction_id=%s", g.baseURL, req.OrderID),
+	}, nil
+}
+
+// GetStatus returns the simulated status of a charge
+func (g *FakeGateway) GetStatus(orderID string) (*dto.PaymentCallbackRequest, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[orderID]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+
+	notification := g.notification(orderID, charge)
+	return &notification, nil
+}
+
+// Refund records a refund against a settled charge
+func (g *FakeGateway) Refund(req RefundRequest) (*Refund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[req.OrderID]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+	if charge.status != "settlement" && charge.status != "partial_refund" {
+		return nil, fmt.Errorf("cannot refund transaction with status %s", charge.status)
+	}
+	if charge.refunded+req.Amount > charge.request.Amount {
+		return nil, errors.New("refund amount exceeds paid amount")
+	}
+
+	charge.refunded += req.Amount
+	charge.status = "partial_refund"
+	if charge.refunded >= charge.request.Amount {
+		charge.status = "refund"
+	}
+
+	return &Refund{
+		RefundKey: req.RefundKey,
+		Amount:    req.Amount,
+		Status:    charge.status,
+	}, nil
+}
+
+// VerifyNotification accepts notifications signed by the fake gateway only
+func (g *FakeGateway) VerifyNotification(notification dto.PaymentCallbackRequest) bool {
+	expected := g.signature(notification)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(notification.SignatureKey)) == 1
+}
+
+// Settle simulates a successful payment
+func (g *FakeGateway) Settle(orderID string) (dto.PaymentCallbackRequest, error) {
+	return g.transition(orderID, "settlement")
+}
+
+// Deny simulates a rejected payment
+func (g *FakeGateway) Deny(orderID string) (dto.PaymentCallbackRequest, error) {
+	return g.transition(orderID, "deny")
+}
+
+// Expire simulates a payment page that was never completed
+func (g *FakeGateway) Expire(orderID string) (dto.PaymentCallbackRequest, error) {
+	return g.transition(orderID, "expire")
+}
+
+// transition moves a pending charge to a final status and emits its notification
+func (g *FakeGateway) transition(orderID, status string) (dto.PaymentCallbackRequest, error) {
+	g.mu.Lock()
+	charge, ok := g.charges[orderID]
+	if !ok {
+		g.mu.Unlock()
+		return dto.PaymentCallbackRequest{}, errors.New("transaction not found")
+	}
+	if charge.status != "pending" {
+		g.mu.Unlock()
+		return dto.PaymentCallbackRequest{}, fmt.Errorf("transaction is already %s", charge.status)
+	}
+
+	charge.status = status
+	notification := g.notification(orderID, charge)
+	handler := g.OnNotification
+	g.mu.Unlock()
+
+	if handler != nil {
+		handler(notification)
+	}
+	return notification, nil
+}
+
+// notification builds a signed notification for the current state of a charge
+func (g *FakeGateway) notification(orderID string, charge *fakeCharge) dto.PaymentCallbackRequest {
+	statusCode := "200"
+	switch charge.status {
+	case "pending":
+		statusCode = "201"
+	case "deny", "expire":
+		statusCode = "202"
+	}
+
+	notification := dto.PaymentCallbackRequest{
+		OrderID:           orderID,
+		TransactionStatus: charge.status,
+		TransactionID:     "FAKE-" + orderID,
+		StatusCode:        statusCode,
+		GrossAmount:       fmt.Sprintf("%.2f", charge.request.Amount),
+		PaymentType:       "fake",
+		FraudStatus:       "accept",
+		TransactionTime:   time.Now().Format("2006-01-02 15:04:05"),
+	}
+	notification.SignatureKey = g.signature(notification)
+	return notification
+}
+
+// signature mirrors the Midtrans signature scheme with the signing key of the gateway
+func (g *FakeGateway) signature(notification dto.PaymentCallbackRequest) string {
+	sum := sha512.Sum512([]byte(notification.OrderID + notification.StatusCode + notification.GrossAmount + g.signingKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"reservation-api/api/dto"
+	"testing"
+	"time"
+)
+
+func TestFakeGatewaySettleAndRefund(t *testing.T) {
+	g := NewFakeGateway("http://localhost:3000")
+
+	charge, err := g.CreateCharge(ChargeRequest{OrderID: "TRX-1", Amount: 150000, ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if charge.RedirectURL != "http://localhost:3000/payment/dummy?transaction_id=TRX-1" {
+		t.Errorf("redirect URL = %s", charge.RedirectURL)
+	}
+
+	if _, err := g.Refund(RefundRequest{OrderID: "TRX-1", RefundKey: "R-0", Amount: 150000}); err == nil {
+		t.Error("refunded a charge that was never paid")
+	}
+
+	var delivered int
+	g.OnNotification = func(notification dto.PaymentCallbackRequest) { delivered++ }
+
+	notification, err := g.Settle("TRX-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered != 1 {
+		t.Errorf("OnNotification called %d times, want 1", delivered)
+	}
+	if notification.TransactionStatus != "settlement" || notification.StatusCode != "200" || notification.GrossAmount != "150000.00" {
+		t.Errorf("notification = %+v", notification)
+	}
+	if !g.VerifyNotification(notification) {
+		t.Error("notification of the gateway was rejected")
+	}
+
+	if _, err := g.Settle("TRX-1"); err == nil {
+		t.Error("settled a charge twice")
+	}
+
+	status, err := g.GetStatus("TRX-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.TransactionStatus != "settlement" {
+		t.Errorf("status = %s, want settlement", status.TransactionStatus)
+	}
+
+	refund, err := g.Refund(RefundRequest{OrderID: "TRX-1", RefundKey: "R-1", Amount: 50000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Status != "partial_refund" {
+		t.Errorf("refund status = %s, want partial_refund", refund.Status)
+	}
+	if _, err := g.Refund(RefundRequest{OrderID: "TRX-1", RefundKey: "R-2", Amount: 150000}); err == nil {
+		t.Error("refunded more than was paid")
+	}
+	refund, err = g.Refund(RefundRequest{OrderID: "TRX-1", RefundKey: "R-3", Amount: 100000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Status != "refund" {
+		t.Errorf("refund status = %s, want refund", refund.Status)
+	}
+}
+
+func TestFakeGatewayRejectsForgedNotifications(t *testing.T) {
+	g := NewFakeGateway("http://localhost:3000")
+	if _, err := g.CreateCharge(ChargeRequest{OrderID: "TRX-1", Amount: 150000}); err != nil {
+		t.Fatal(err)
+	}
+	notification, err := g.Settle("TRX-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned := notification
+	unsigned.SignatureKey = ""
+	if g.VerifyNotification(unsigned) {
+		t.Error("unsigned notification accepted")
+	}
+
+	tampered := notification
+	tampered.GrossAmount = "1.00"
+	if g.VerifyNotification(tampered) {
+		t.Error("notification with a changed amount accepted")
+	}
+
+	other := NewFakeGateway("http://localhost:3000")
+	if other.VerifyNotification(notification) {
+		t.Error("notification signed by another gateway accepted")
+	}
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"time"
+)
+
+// Gateway names selectable via config
+const (
+	Midtrans = "midtrans"
+	Fake     = "fake"
+)
+
+// ChargeRequest describes a payment to be collected by the gateway
+type ChargeRequest struct {
+	OrderID   string
+	Amount    float64
+	ExpiresAt time.Time
+	Customer  dto.CustomerDetails
+	Items     []dto.ItemDetail
+}
+
+// Charge is the gateway-side payment page created for a charge request
+type Charge struct {
+	Token       string
+	RedirectURL string
+}
+
+// RefundRequest describes money to be returned for a paid order
+type RefundRequest struct {
+	OrderID   string
+	RefundKey string // Unique per refund so retries are not refunded twice
+	Amount    float64
+	Reason    string
+}
+
+// Refund is the gateway result of a refund request
+type Refund struct {
+	RefundKey string
+	Amount    float64
+	Status    string
+}
+
+// PaymentGateway is a payment provider able to collect, inspect and refund payments
+type PaymentGateway interface {
+	// Name returns the gateway identifier
+	Name() string
+
+	// CreateCharge creates a payment page for an order
+	CreateCharge(req ChargeRequest) (*Charge, error)
+
+	// GetStatus queries the current transaction status of an order, in the same
+	// shape as a webhook notification
+	GetStatus(orderID string) (*dto.PaymentCallbackRequest, error)
+
+	// Refund returns money for a paid order
+	Refund(req RefundRequest) (*Refund, error)
+
+	// VerifyNotification checks that a webhook notification was sent by the gateway
+	VerifyNotification(notification dto.PaymentCallbackRequest) bool
+}
+
+// New creates the payment gateway selected in configuration
+func New(cfg *config.Config) PaymentGateway {
+	if cfg.PaymentGateway == Midtrans {
+		return NewMidtransGateway(cfg.MidtransServerKey, cfg.MidtransBaseURL, cfg.MidtransAPIBaseURL)
+	}
+	return NewFakeGateway(cfg.FrontendURL)
+}
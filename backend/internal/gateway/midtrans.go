@@ -0,0 +1,161 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reservation-api/api/dto"
+	"time"
+)
+
+// MidtransGateway collects payments through Midtrans Snap and the Midtrans Core API
+type MidtransGateway struct {
+	serverKey  string
+	snapURL    string
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewMidtransGateway creates a new Midtrans gateway
+func NewMidtransGateway(serverKey, snapURL, apiURL string) *MidtransGateway {
+	return &MidtransGateway{
+		serverKey:  serverKey,
+		snapURL:    snapURL,
+		apiURL:     apiURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the gateway identifier
+func (g *MidtransGateway) Name() string {
+	return Midtrans
+}
+
+// CreateCharge creates a Snap transaction
+func (g *MidtransGateway) CreateCharge(req ChargeRequest) (*Charge, error) {
+	// Let Midtrans close the payment page no later than the charge expires
+	expiryMinutes := int(time.Until(req.ExpiresAt).Minutes())
+	if expiryMinutes < 1 {
+		expiryMinutes = 1
+	}
+
+	midtransReq := dto.MidtransRequest{
+		TransactionDetails: dto.TransactionDetails{
+			OrderID:     req.OrderID,
+			GrossAmount: req.Amount,
+		},
+		Expiry: &dto.Expiry{
+			Unit:     "minute",
+			Duration: expiryMinutes,
+		},
+		CustomerDetails: req.Customer,
+		ItemDetails:     req.Items,
+	}
+
+	var midtransResp dto.MidtransResponse
+	if err := g.do("POST", g.snapURL+"/transactions", midtransReq, &midtransResp); err != nil {
+		return nil, err
+	}
+
+	return &Charge{
+		Token:       midtransResp.Token,
+		RedirectURL: midtransResp.RedirectURL,
+	}, nil
+}
+
+// GetStatus queries the Core API transaction status
+func (g *MidtransGateway) GetStatus(orderID string) (*dto.PaymentCallbackRequest, error) {
+	var status dto.PaymentCallbackRequest
+	if err := g.do("GET", fmt.Sprintf("%s/%s/status", g.apiURL, orderID), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Refund requests a (partial) refund through the Core API
+func (g *MidtransGateway) Refund(req RefundRequest) (*Refund, error) {
+	body := dto.MidtransRefundRequest{
+		RefundKey: req.RefundKey,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+	}
+
+	var resp dto.MidtransRefundResponse
+	if err := g.do("POST", fmt.Sprintf("%s/%s/refund", g.apiURL, req.OrderID), body, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != "200" {
+		return nil, fmt.Errorf("midtrans refund failed: %s", resp.StatusMessage)
+	}
+
+	return &Refund{
+		RefundKey: req.RefundKey,
+		Amount:    req.Amount,
+		Status:    resp.TransactionStatus,
+	}, nil
+}
+
+// VerifyNotification checks the signature key, which is the SHA512 hex digest of
+// order_id + status_code + gross_amount + server key
+func (g *MidtransGateway) VerifyNotification(notification dto.PaymentCallbackRequest) bool {
+	expected := g.signature(notification.OrderID, notification.StatusCode, notification.GrossAmount)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(notification.SignatureKey)) == 1
+}
+
+// signature computes the Midtrans signature key
+func (g *MidtransGateway) signature(orderID, statusCode, grossAmount string) string {
+	sum := sha512.Sum512([]byte(orderID + statusCode + grossAmount + g.serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// do sends an authenticated JSON request to Midtrans and decodes the response
+func (g *MidtransGateway) do(method, url string, body interface{}, out interface{}) error {
+	// Check if Midtrans credentials are set
+	if g.serverKey == "" {
+		return errors.New("midtrans credentials not configured")
+	}
+
+	var reader *bytes.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(jsonData)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	httpReq, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+
+	// Set headers
+	auth := base64.StdEncoding.EncodeToString([]byte(g.serverKey + ":"))
+	httpReq.Header.Set("Authorization", "Basic "+auth)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	// Send request
+	resp, err := g.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("midtrans API returned status %d", resp.StatusCode)
+	}
+
+	// Parse response
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,49 @@
+package gateway
+
+import (
+	"reservation-api/api/dto"
+	"strings"
+	"testing"
+)
+
+func TestMidtransVerifyNotification(t *testing.T) {
+	g := NewMidtransGateway("SB-Mid-server-test", "", "")
+
+	// SHA512("TRX-1" + "200" + "150000.00" + "SB-Mid-server-test")
+	const signature = "9c385692d963e24ba7277dfdb59ea20714fc6bf7eb651e364d1b146a2ed15418" +
+		"c71b5173eeb563560dfd0494e85cea1d4d539cacdf1093aab9b0a2098a5915d0"
+
+	valid := dto.PaymentCallbackRequest{
+		OrderID:           "TRX-1",
+		TransactionStatus: "settlement",
+		StatusCode:        "200",
+		GrossAmount:       "150000.00",
+		SignatureKey:      signature,
+	}
+
+	tests := []struct {
+		name    string
+		gateway *MidtransGateway
+		modify  func(n *dto.PaymentCallbackRequest)
+		want    bool
+	}{
+		{"known good signature", g, func(n *dto.PaymentCallbackRequest) {}, true},
+		{"uppercase signature", g, func(n *dto.PaymentCallbackRequest) { n.SignatureKey = strings.ToUpper(n.SignatureKey) }, false},
+		{"tampered amount", g, func(n *dto.PaymentCallbackRequest) { n.GrossAmount = "1500.00" }, false},
+		{"tampered order", g, func(n *dto.PaymentCallbackRequest) { n.OrderID = "TRX-2" }, false},
+		{"tampered status code", g, func(n *dto.PaymentCallbackRequest) { n.StatusCode = "201" }, false},
+		{"missing signature", g, func(n *dto.PaymentCallbackRequest) { n.SignatureKey = "" }, false},
+		{"truncated signature", g, func(n *dto.PaymentCallbackRequest) { n.SignatureKey = n.SignatureKey[:64] }, false},
+		{"wrong server key", NewMidtransGateway("SB-Mid-server-other", "", ""), func(n *dto.PaymentCallbackRequest) {}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notification := valid
+			tt.modify(&notification)
+			if got := tt.gateway.VerifyNotification(notification); got != tt.want {
+				t.Errorf("VerifyNotification = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
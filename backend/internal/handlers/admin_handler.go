@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"reservation-api/api/dto"
 	"reservation-api/internal/models"
 	"reservation-api/internal/repository"
+	"reservation-api/internal/services"
 	"reservation-api/internal/utils"
 	"strconv"
 
@@ -12,18 +14,30 @@ import (
 
 // AdminHandler handles admin requests
 type AdminHandler struct {
-	courtRepo    *repository.CourtRepository
-	timeslotRepo *repository.TimeslotRepository
+	courtRepo           *repository.CourtRepository
+	timeslotRepo        *repository.TimeslotRepository
+	userRepo            *repository.UserRepository
+	refundService       *services.RefundService
+	notificationService *services.NotificationService
+	authService         *services.AuthService
 }
 
 // NewAdminHandler creates a new admin handler
 func NewAdminHandler(
 	courtRepo *repository.CourtRepository,
 	timeslotRepo *repository.TimeslotRepository,
+	userRepo *repository.UserRepository,
+	refundService *services.RefundService,
+	notificationService *services.NotificationService,
+	authService *services.AuthService,
 ) *AdminHandler {
 	return &AdminHandler{
-		courtRepo:    courtRepo,
-		timeslotRepo: timeslotRepo,
+		courtRepo:           courtRepo,
+		timeslotRepo:        timeslotRepo,
+		userRepo:            userRepo,
+		refundService:       refundService,
+		notificationService: notificationService,
+		authService:         authService,
 	}
 }
 
@@ -93,6 +107,18 @@ func (h *AdminHandler) UpdateCourt(c *gin.Context) {
 	if updates.Description != "" {
 		court.Description = updates.Description
 	}
+	if updates.MinLeadMinutes != nil {
+		court.MinLeadMinutes = updates.MinLeadMinutes
+	}
+	if updates.MaxAdvanceDays != nil {
+		court.MaxAdvanceDays = updates.MaxAdvanceDays
+	}
+	if updates.CancelDeadlineHours != nil {
+		court.CancelDeadlineHours = updates.CancelDeadlineHours
+	}
+	if updates.AllowLateCancel != nil {
+		court.AllowLateCancel = updates.AllowLateCancel
+	}
 
 	if err := h.courtRepo.Update(court); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update court")
@@ -210,6 +236,178 @@ func (h *AdminHandler) DeleteTimeslot(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Timeslot deleted successfully", nil)
 }
 
+// Users Management
+
+// GetUsers gets all users
+func (h *AdminHandler) GetUsers(c *gin.Context) {
+	users, err := h.userRepo.FindAll()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve users")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", gin.H{
+		"users": users,
+	})
+}
+
+// UpdateUserRole changes the role of a user
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	role := models.UserRole(req.Role)
+	if !role.IsValid() {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid role")
+		return
+	}
+
+	user, err := h.userRepo.FindByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	user.Role = role
+	if err := h.userRepo.Update(user); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update user role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User role updated successfully", gin.H{
+		"user": user,
+	})
+}
+
+// UnlockUser ends the login lockout of a user
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.authService.UnlockAccount(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unlocked successfully", gin.H{
+		"user": user,
+	})
+}
+
+// GetLoginFailures gets the latest failed logins, optionally filtered by email and
+// IP address
+func (h *AdminHandler) GetLoginFailures(c *gin.Context) {
+	failures, err := h.authService.GetLoginFailures(c.Query("email"), c.Query("ip"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve failed logins")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Failed logins retrieved successfully", gin.H{
+		"login_failures": failures,
+	})
+}
+
+// Refunds Management
+
+// GetRefunds gets all refunds
+func (h *AdminHandler) GetRefunds(c *gin.Context) {
+	refunds, err := h.refundService.GetRefunds()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve refunds")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Refunds retrieved successfully", gin.H{
+		"refunds": refunds,
+	})
+}
+
+// RetryRefund retries a failed refund
+func (h *AdminHandler) RetryRefund(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid refund ID")
+		return
+	}
+
+	refund, err := h.refundService.RetryRefund(uint(id))
+	if err != nil {
+		status := http.StatusBadGateway
+		switch err.Error() {
+		case "refund not found":
+			status = http.StatusNotFound
+		case "only failed refunds can be retried":
+			status = http.StatusBadRequest
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Refund processed successfully", gin.H{
+		"refund": refund,
+	})
+}
+
+// Notifications Management
+
+// GetNotifications gets the latest outgoing notifications, optionally filtered by
+// status and channel
+func (h *AdminHandler) GetNotifications(c *gin.Context) {
+	messages, err := h.notificationService.GetMessages(c.Query("status"), c.Query("channel"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve notifications")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications retrieved successfully", gin.H{
+		"notifications": messages,
+	})
+}
+
+// RetryNotification queues a failed notification for delivery again
+func (h *AdminHandler) RetryNotification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	message, err := h.notificationService.RetryMessage(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "notification not found":
+			status = http.StatusNotFound
+		case "only failed notifications can be retried":
+			status = http.StatusBadRequest
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification queued for delivery", gin.H{
+		"notification": message,
+	})
+}
+
 // GetStatistics gets admin statistics
 func (h *AdminHandler) GetStatistics(c *gin.Context) {
 	// TODO: Implement statistics gathering
@@ -222,4 +420,4 @@ func (h *AdminHandler) GetStatistics(c *gin.Context) {
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Statistics retrieved successfully", stats)
-}
\ No newline at end of file
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/middleware"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceHandler handles check-in and attendance requests
+type AttendanceHandler struct {
+	attendanceService *services.AttendanceService
+}
+
+// NewAttendanceHandler creates a new attendance handler
+func NewAttendanceHandler(attendanceService *services.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{
+		attendanceService: attendanceService,
+	}
+}
+
+// GetSummary gets the recent no-shows of the logged-in user
+func (h *AttendanceHandler) GetSummary(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	summary, err := h.attendanceService.GetSummary(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve attendance")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attendance retrieved successfully", gin.H{
+		"attendance": summary,
+	})
+}
+
+// GetSessionRoster gets the attendees of a session with their check-in status
+func (h *AttendanceHandler) GetSessionRoster(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	roster, err := h.attendanceService.GetSessionRoster(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "session not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Roster retrieved successfully", gin.H{
+		"roster": roster,
+	})
+}
+
+// CheckIn marks the attendee of a reservation as present
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reservation ID")
+		return
+	}
+
+	reservation, err := h.attendanceService.CheckIn(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, checkInErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Checked in successfully", gin.H{
+		"reservation": reservation,
+	})
+}
+
+// CheckInByTicket marks the attendee of the reservation with a scanned ticket as present
+func (h *AttendanceHandler) CheckInByTicket(c *gin.Context) {
+	var req dto.TicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	reservation, err := h.attendanceService.CheckInByTicket(req.Token)
+	if err != nil {
+		utils.ErrorResponse(c, checkInErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Checked in successfully", gin.H{
+		"reservation": reservation,
+	})
+}
+
+// checkInErrorStatus maps check-in errors to HTTP status codes
+func checkInErrorStatus(err error) int {
+	switch err.Error() {
+	case "reservation not found":
+		return http.StatusNotFound
+	case "reservation is already checked in":
+		return http.StatusConflict
+	case "failed to check in":
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
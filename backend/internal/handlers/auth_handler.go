@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"reservation-api/api/dto"
+	"reservation-api/internal/loginlimit"
 	"reservation-api/internal/middleware"
+	"reservation-api/internal/oidc"
 	"reservation-api/internal/services"
 	"reservation-api/internal/utils"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -39,15 +45,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.authService.Register(req)
+	user, tokens, err := h.authService.Register(req, clientInfo(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusCreated, "User registered successfully", gin.H{
-		"token": token,
-		"user":  user,
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
 	})
 }
 
@@ -60,6 +68,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Param request body dto.LoginRequest true "Login credentials"
 // @Success 200 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -68,18 +77,293 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.authService.Login(req)
+	user, tokens, err := h.authService.Login(req, clientInfo(c))
 	if err != nil {
+		var blocked *loginlimit.BlockedError
+		if errors.As(err, &blocked) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", gin.H{
-		"token": token,
-		"user":  user,
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
 	})
 }
 
+// GetOIDCProviders lists the providers users can sign in with
+// @Summary List sign-in providers
+// @Description Names of the OpenID Connect providers users can sign in with, e.g. google
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /auth/oidc/providers [get]
+func (h *AuthHandler) GetOIDCProviders(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Sign-in providers retrieved successfully", gin.H{
+		"providers": h.authService.OIDCProviders(),
+	})
+}
+
+// AuthorizeOIDC starts a sign-in with a provider
+// @Summary Start provider sign-in
+// @Description Get the provider page to send the user to. Keep the state to compare with the one the provider sends back, and the code verifier to finish the sign-in.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /auth/oidc/{provider}/authorize [get]
+func (h *AuthHandler) AuthorizeOIDC(c *gin.Context) {
+	authorization, err := h.authService.AuthorizeOIDC(c.Param("provider"))
+	if err != nil {
+		utils.ErrorResponse(c, oidcErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sign-in started", authorization)
+}
+
+// OIDCCallback finishes a sign-in with a provider
+// @Summary Finish provider sign-in
+// @Description Exchange the code and state the provider sent back, with the code verifier of the authorization, for a session. Links the account with the same verified email, or creates one.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param request body dto.OIDCCallbackRequest true "Code, state and code verifier"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/oidc/{provider}/callback [post]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, tokens, err := h.authService.OIDCCallback(c.Param("provider"), req, clientInfo(c))
+	if err != nil {
+		utils.ErrorResponse(c, oidcErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", gin.H{
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
+	})
+}
+
+// OIDCSignIn signs in with an ID token from a provider's mobile SDK
+// @Summary Sign in with ID token
+// @Description Sign in with an ID token the mobile app got from the provider, with the state and code verifier of the authorization whose nonce the app passed to the provider. Links the account with the same verified email, or creates one.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param request body dto.OIDCTokenRequest true "ID token, state and code verifier"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/oidc/{provider}/token [post]
+func (h *AuthHandler) OIDCSignIn(c *gin.Context) {
+	var req dto.OIDCTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, tokens, err := h.authService.OIDCSignIn(c.Param("provider"), req, clientInfo(c))
+	if err != nil {
+		utils.ErrorResponse(c, oidcErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", gin.H{
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
+	})
+}
+
+// oidcErrorStatus maps provider sign-in errors to HTTP status codes
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, oidc.ErrUnknownProvider):
+		return http.StatusNotFound
+	case err.Error() == "failed to reach sign-in provider":
+		return http.StatusBadGateway
+	case strings.HasPrefix(err.Error(), "failed to"):
+		return http.StatusInternalServerError
+	}
+	return http.StatusUnauthorized
+}
+
+// Refresh exchanges a refresh token for new tokens
+// @Summary Refresh session
+// @Description Exchange a refresh token for a new access token and refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, tokens, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		utils.ErrorResponse(c, sessionErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session refreshed successfully", gin.H{
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
+	})
+}
+
+// Logout ends the session of a refresh token
+// @Summary User logout
+// @Description Revoke the session of a refresh token, or all sessions of the user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LogoutRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.authService.Logout(req.RefreshToken, req.All); err != nil {
+		utils.ErrorResponse(c, sessionErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+// ForgotPassword emails a password reset link
+// @Summary Request password reset
+// @Description Email a single-use password reset link. Responds the same whether or not the email is registered.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(req.Email); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// ResetPassword sets a new password with a reset token
+// @Summary Reset password
+// @Description Set a new password with a reset token. Signs the user out of all sessions.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(req.Token, req.Password); err != nil {
+		utils.ErrorResponse(c, tokenErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully, please log in again", nil)
+}
+
+// VerifyEmail confirms an email address with a verification token
+// @Summary Verify email
+// @Description Confirm the email address of an account with a verification token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/email/verify [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.authService.VerifyEmail(req.Token)
+	if err != nil {
+		utils.ErrorResponse(c, tokenErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", gin.H{
+		"user": user,
+	})
+}
+
+// clientInfo describes the device of a sign-in request
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// sessionErrorStatus maps refresh and logout errors to HTTP status codes
+func sessionErrorStatus(err error) int {
+	if strings.HasPrefix(err.Error(), "failed to") {
+		return http.StatusInternalServerError
+	}
+	return http.StatusUnauthorized
+}
+
+// tokenErrorStatus maps password reset and email verification errors to HTTP status codes
+func tokenErrorStatus(err error) int {
+	if strings.HasPrefix(err.Error(), "failed to") {
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
+
 // GetProfile gets user profile
 // @Summary Get user profile
 // @Description Get profile of logged in user
@@ -140,4 +424,156 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Profile updated successfully", gin.H{
 		"user": user,
 	})
-}
\ No newline at end of file
+}
+
+// ResendVerification emails a new verification link
+// @Summary Resend verification email
+// @Description Email a new link to verify the email address of the logged in user
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /profile/email/verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	if err := h.authService.ResendVerification(userID); err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case strings.HasPrefix(err.Error(), "please wait"):
+			status = http.StatusTooManyRequests
+		case strings.HasPrefix(err.Error(), "failed to"):
+			status = http.StatusInternalServerError
+		case err.Error() == "user not found":
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verification email sent", nil)
+}
+
+// ChangePassword changes the password of the logged in user
+// @Summary Change password
+// @Description Change the password, confirmed with the current one. Signs out every other session.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /profile/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(userID, sessionID, req); err != nil {
+		utils.ErrorResponse(c, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password changed successfully", nil)
+}
+
+// ChangeEmail changes the email address of the logged in user
+// @Summary Change email
+// @Description Change the email address, confirmed with the password. The new address has to be verified again.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangeEmailRequest true "New email and password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /profile/email [put]
+func (h *AuthHandler) ChangeEmail(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.authService.ChangeEmail(userID, req)
+	if err != nil {
+		utils.ErrorResponse(c, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email changed, please verify your new address", gin.H{
+		"user": user,
+	})
+}
+
+// DeleteAccount deletes the account of the logged in user
+// @Summary Delete account
+// @Description Delete the account, confirmed with the password. Personal data is erased; reservations and payments are kept anonymized.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DeleteAccountRequest true "Password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /profile [delete]
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.authService.DeleteAccount(userID, req); err != nil {
+		utils.ErrorResponse(c, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account deleted successfully", nil)
+}
+
+// accountErrorStatus maps password, email and account deletion errors to HTTP status codes
+func accountErrorStatus(err error) int {
+	switch {
+	case err.Error() == "user not found":
+		return http.StatusNotFound
+	case err.Error() == "email already exists",
+		strings.HasPrefix(err.Error(), "cancel your upcoming reservations"):
+		return http.StatusConflict
+	case err.Error() == "admin accounts cannot be deleted":
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "failed to"):
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClosureHandler handles studio closure requests
+type ClosureHandler struct {
+	closureService *services.ClosureService
+}
+
+// NewClosureHandler creates a new closure handler
+func NewClosureHandler(closureService *services.ClosureService) *ClosureHandler {
+	return &ClosureHandler{
+		closureService: closureService,
+	}
+}
+
+// GetClosures gets all closures
+func (h *ClosureHandler) GetClosures(c *gin.Context) {
+	closures, err := h.closureService.GetClosures()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve closures")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Closures retrieved successfully", gin.H{
+		"closures": closures,
+	})
+}
+
+// CreateClosure creates a closure and cancels the classes it covers
+func (h *ClosureHandler) CreateClosure(c *gin.Context) {
+	var req dto.ClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	closure, cancelled, err := h.closureService.CreateClosure(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case closure != nil, err.Error() == "failed to create closure":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Closure created successfully", gin.H{
+		"closure":            closure,
+		"cancelled_sessions": cancelled,
+	})
+}
+
+// DeleteClosure removes a closure
+func (h *ClosureHandler) DeleteClosure(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid closure ID")
+		return
+	}
+
+	if err := h.closureService.DeleteClosure(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "closure not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Closure deleted successfully", nil)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/middleware"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreditHandler handles class package and credit requests
+type CreditHandler struct {
+	creditService *services.CreditService
+}
+
+// NewCreditHandler creates a new credit handler
+func NewCreditHandler(creditService *services.CreditService) *CreditHandler {
+	return &CreditHandler{
+		creditService: creditService,
+	}
+}
+
+// GetPackages gets purchasable class packages
+// @Summary Get class packages
+// @Description Get all class packages available for purchase
+// @Tags public
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /packages [get]
+func (h *CreditHandler) GetPackages(c *gin.Context) {
+	packages, err := h.creditService.GetPackages()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve packages")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Packages retrieved successfully", gin.H{
+		"packages": packages,
+	})
+}
+
+// PurchasePackage starts a class package purchase
+// @Summary Purchase class package
+// @Description Create a payment for a class package; credits are added once paid
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PurchasePackageRequest true "Package to purchase"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /credits/purchase [post]
+func (h *CreditHandler) PurchasePackage(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req dto.PurchasePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	payment, paymentURL, snapToken, err := h.creditService.PurchasePackage(userID, req)
+	if err != nil {
+		if payment != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		status := http.StatusBadRequest
+		if err.Error() == "package not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment created successfully", gin.H{
+		"payment":     payment,
+		"payment_url": paymentURL,
+		"snap_token":  snapToken,
+	})
+}
+
+// GetWallet gets the credit balance of the logged-in user
+// @Summary Get credit wallet
+// @Description Get usable credit balance and package purchases
+// @Tags credits
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /credits [get]
+func (h *CreditHandler) GetWallet(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	balance, purchases, err := h.creditService.GetWallet(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve credits")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Credits retrieved successfully", gin.H{
+		"balance":   balance,
+		"purchases": purchases,
+	})
+}
+
+// GetTransactions gets the credit ledger of the logged-in user
+// @Summary Get credit transactions
+// @Description Get credit purchases, redemptions and returns
+// @Tags credits
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /credits/transactions [get]
+func (h *CreditHandler) GetTransactions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	transactions, err := h.creditService.GetTransactions(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve credit transactions")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Credit transactions retrieved successfully", gin.H{
+		"transactions": transactions,
+	})
+}
+
+// CreatePackage creates a new class package
+func (h *CreditHandler) CreatePackage(c *gin.Context) {
+	var req dto.CreditPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	pkg, err := h.creditService.CreatePackage(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Package created successfully", gin.H{
+		"package": pkg,
+	})
+}
+
+// UpdatePackage updates a class package
+func (h *CreditHandler) UpdatePackage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid package ID")
+		return
+	}
+
+	var req dto.CreditPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	pkg, err := h.creditService.UpdatePackage(uint(id), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "package not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Package updated successfully", gin.H{
+		"package": pkg,
+	})
+}
+
+// DeletePackage deletes a class package
+func (h *CreditHandler) DeletePackage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid package ID")
+		return
+	}
+
+	if err := h.creditService.DeletePackage(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "package not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Package deleted successfully", nil)
+}
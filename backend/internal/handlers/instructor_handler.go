@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/middleware"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InstructorHandler handles instructor requests
+type InstructorHandler struct {
+	instructorService *services.InstructorService
+}
+
+// NewInstructorHandler creates a new instructor handler
+func NewInstructorHandler(instructorService *services.InstructorService) *InstructorHandler {
+	return &InstructorHandler{
+		instructorService: instructorService,
+	}
+}
+
+// GetInstructors gets active instructors
+// @Summary Get instructors
+// @Description Get active instructors with their bio, specialties and availability
+// @Tags public
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /instructors [get]
+func (h *InstructorHandler) GetInstructors(c *gin.Context) {
+	instructors, err := h.instructorService.GetInstructors(true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve instructors")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Instructors retrieved successfully", gin.H{
+		"instructors": instructors,
+	})
+}
+
+// GetRoster gets the classes the logged-in instructor teaches on a day
+// @Summary Get instructor roster
+// @Description Get classes taught by the logged-in instructor on a date with their attendees
+// @Tags instructor
+// @Produce json
+// @Security BearerAuth
+// @Param date query string true "Date in YYYY-MM-DD format"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /instructor/roster [get]
+func (h *InstructorHandler) GetRoster(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	dateStr := c.Query("date")
+	if dateStr == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "date is required")
+		return
+	}
+
+	classes, err := h.instructorService.GetRoster(userID, dateStr)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "instructor profile not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Roster retrieved successfully", gin.H{
+		"date":    dateStr,
+		"classes": classes,
+	})
+}
+
+// GetAllInstructors gets all instructors including inactive ones
+func (h *InstructorHandler) GetAllInstructors(c *gin.Context) {
+	instructors, err := h.instructorService.GetInstructors(false)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve instructors")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Instructors retrieved successfully", gin.H{
+		"instructors": instructors,
+	})
+}
+
+// CreateInstructor creates an instructor profile for a user
+func (h *InstructorHandler) CreateInstructor(c *gin.Context) {
+	var req dto.InstructorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	instructor, err := h.instructorService.CreateInstructor(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "user not found":
+			status = http.StatusNotFound
+		case "user already has an instructor profile":
+			status = http.StatusConflict
+		case "failed to create instructor", "failed to update user role":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Instructor created successfully", gin.H{
+		"instructor": instructor,
+	})
+}
+
+// UpdateInstructor updates an instructor profile
+func (h *InstructorHandler) UpdateInstructor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid instructor ID")
+		return
+	}
+
+	var req dto.InstructorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	instructor, err := h.instructorService.UpdateInstructor(uint(id), req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "instructor not found":
+			status = http.StatusNotFound
+		case "failed to update instructor":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Instructor updated successfully", gin.H{
+		"instructor": instructor,
+	})
+}
+
+// DeleteInstructor deletes an instructor
+func (h *InstructorHandler) DeleteInstructor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid instructor ID")
+		return
+	}
+
+	if err := h.instructorService.DeleteInstructor(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "instructor not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Instructor deleted successfully", nil)
+}
+
+// AssignInstructor assigns an instructor to a class session
+func (h *InstructorHandler) AssignInstructor(c *gin.Context) {
+	var req dto.ClassAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	session, err := h.instructorService.AssignInstructor(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.HasPrefix(err.Error(), "instructor already teaches"):
+			status = http.StatusConflict
+		case err.Error() == "failed to assign instructor":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Instructor assigned successfully", gin.H{
+		"session": session,
+	})
+}
+
+// UnassignInstructor removes the instructor of a class session
+func (h *InstructorHandler) UnassignInstructor(c *gin.Context) {
+	courtID, err := strconv.ParseUint(c.Query("court_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid court_id")
+		return
+	}
+
+	timeslotID, err := strconv.ParseUint(c.Query("timeslot_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid timeslot_id")
+		return
+	}
+
+	if err := h.instructorService.UnassignInstructor(uint(courtID), uint(timeslotID), c.Query("date")); err != nil {
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "class not found", "class has no instructor assigned":
+			status = http.StatusNotFound
+		case "failed to unassign instructor":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Instructor unassigned successfully", nil)
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/middleware"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MembershipHandler handles membership requests
+type MembershipHandler struct {
+	membershipService *services.MembershipService
+}
+
+// NewMembershipHandler creates a new membership handler
+func NewMembershipHandler(membershipService *services.MembershipService) *MembershipHandler {
+	return &MembershipHandler{
+		membershipService: membershipService,
+	}
+}
+
+// GetPlans gets membership plans open for signup
+// @Summary Get membership plans
+// @Description Get all monthly membership plans available for signup
+// @Tags public
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /membership-plans [get]
+func (h *MembershipHandler) GetPlans(c *gin.Context) {
+	plans, err := h.membershipService.GetPlans()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve membership plans")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Membership plans retrieved successfully", gin.H{
+		"plans": plans,
+	})
+}
+
+// Subscribe signs the logged-in user up to a membership plan
+// @Summary Subscribe to membership
+// @Description Create a membership and the payment for its first month
+// @Tags memberships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SubscribeRequest true "Plan to subscribe to"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /memberships [post]
+func (h *MembershipHandler) Subscribe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req dto.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	payment, paymentURL, snapToken, err := h.membershipService.Subscribe(userID, req)
+	if err != nil {
+		if payment != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "membership plan not found":
+			status = http.StatusNotFound
+		case "you already have a membership":
+			status = http.StatusConflict
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment created successfully", gin.H{
+		"payment":     payment,
+		"payment_url": paymentURL,
+		"snap_token":  snapToken,
+	})
+}
+
+// GetMemberships gets memberships of the logged-in user
+// @Summary Get memberships
+// @Description Get memberships of the logged-in user with their monthly payments
+// @Tags memberships
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /memberships [get]
+func (h *MembershipHandler) GetMemberships(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	memberships, err := h.membershipService.GetMemberships(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve memberships")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Memberships retrieved successfully", gin.H{
+		"memberships": memberships,
+	})
+}
+
+// RenewMembership creates a payment to reactivate a suspended or expired membership
+// @Summary Renew membership
+// @Description Create a payment for the next month of a suspended or expired membership
+// @Tags memberships
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Membership ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /memberships/{id}/renew [post]
+func (h *MembershipHandler) RenewMembership(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid membership ID")
+		return
+	}
+
+	payment, paymentURL, snapToken, err := h.membershipService.Renew(uint(id), userID)
+	if err != nil {
+		if payment != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, membershipErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment created successfully", gin.H{
+		"payment":     payment,
+		"payment_url": paymentURL,
+		"snap_token":  snapToken,
+	})
+}
+
+// UpdateAutoRenew turns monthly renewal of a membership on or off
+// @Summary Update membership auto-renew
+// @Description Turn monthly renewal on or off; without it the membership expires at the end of the paid month
+// @Tags memberships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Membership ID"
+// @Param request body dto.UpdateAutoRenewRequest true "Auto-renew setting"
+// @Success 200 {object} map[string]interface{}
+// @Router /memberships/{id}/auto-renew [put]
+func (h *MembershipHandler) UpdateAutoRenew(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid membership ID")
+		return
+	}
+
+	var req dto.UpdateAutoRenewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	membership, err := h.membershipService.UpdateAutoRenew(uint(id), userID, *req.AutoRenew)
+	if err != nil {
+		utils.ErrorResponse(c, membershipErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Membership updated successfully", gin.H{
+		"membership": membership,
+	})
+}
+
+// membershipErrorStatus maps a membership service error to an HTTP status
+func membershipErrorStatus(err error) int {
+	switch err.Error() {
+	case "membership not found":
+		return http.StatusNotFound
+	case "unauthorized access to membership":
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// CreatePlan creates a new membership plan
+func (h *MembershipHandler) CreatePlan(c *gin.Context) {
+	var req dto.MembershipPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	plan, err := h.membershipService.CreatePlan(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Membership plan created successfully", gin.H{
+		"plan": plan,
+	})
+}
+
+// UpdatePlan updates a membership plan
+func (h *MembershipHandler) UpdatePlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid membership plan ID")
+		return
+	}
+
+	var req dto.MembershipPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	plan, err := h.membershipService.UpdatePlan(uint(id), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "membership plan not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Membership plan updated successfully", gin.H{
+		"plan": plan,
+	})
+}
+
+// DeletePlan deletes a membership plan
+func (h *MembershipHandler) DeletePlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid membership plan ID")
+		return
+	}
+
+	if err := h.membershipService.DeletePlan(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "membership plan not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Membership plan deleted successfully", nil)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"reservation-api/api/dto"
 	"reservation-api/internal/middleware"
@@ -69,25 +70,89 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 	})
 }
 
-// PaymentCallback handles payment callback from Midtrans
-// @Summary Payment callback
-// @Description Handle payment status callback from Midtrans
+// PaymentCallback handles payment notification from Midtrans
+// @Summary Payment notification
+// @Description Handle payment status notification from Midtrans (verified by signature_key)
 // @Tags payments
 // @Accept json
 // @Produce json
-// @Param request body dto.PaymentCallbackRequest true "Callback data"
+// @Param request body dto.PaymentCallbackRequest true "Notification data"
 // @Success 200 {object} map[string]interface{}
-// @Router /payments/callback [post]
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /payments/notification [post]
 func (h *PaymentHandler) PaymentCallback(c *gin.Context) {
+	rawBody, err := c.GetRawData()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+
 	var req dto.PaymentCallbackRequest
+	if err := json.Unmarshal(rawBody, &req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	payment, err := h.paymentService.HandleCallback(req, rawBody)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "invalid signature" {
+			status = http.StatusUnauthorized
+		} else if err.Error() == "payment not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment status updated", gin.H{
+		"payment": payment,
+	})
+}
+
+// SimulatePayment completes the dummy payment page of the fake gateway
+// @Summary Simulate payment
+// @Description Settle or deny a pending payment of the user at the fake payment gateway (development only)
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment ID"
+// @Param request body dto.SimulatePaymentRequest true "Payment result"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /payments/{id}/simulate [post]
+func (h *PaymentHandler) SimulatePayment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid payment ID")
+		return
+	}
+
+	var req dto.SimulatePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
-	payment, err := h.paymentService.HandleCallback(req)
+	payment, err := h.paymentService.SimulatePayment(uint(id), userID, req.Result)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "payment not found":
+			status = http.StatusNotFound
+		case "unauthorized access to payment", "payments can only be simulated with the fake gateway":
+			status = http.StatusForbidden
+		}
+		utils.ErrorResponse(c, status, err.Error())
 		return
 	}
 
@@ -132,4 +197,45 @@ func (h *PaymentHandler) GetPayment(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Payment retrieved successfully", gin.H{
 		"payment": payment,
 	})
-}
\ No newline at end of file
+}
+
+// RefreshPaymentStatus re-checks payment status at the payment gateway
+// @Summary Refresh payment status
+// @Description Query the payment gateway for the latest status of a payment
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /payments/{id}/refresh [post]
+func (h *PaymentHandler) RefreshPaymentStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid payment ID")
+		return
+	}
+
+	payment, err := h.paymentService.RefreshStatus(uint(id), userID)
+	if err != nil {
+		status := http.StatusBadGateway
+		switch err.Error() {
+		case "payment not found":
+			status = http.StatusNotFound
+		case "unauthorized access to payment":
+			status = http.StatusForbidden
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment status refreshed", gin.H{
+		"payment": payment,
+	})
+}
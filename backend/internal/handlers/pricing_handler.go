@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PricingHandler handles pricing requests
+type PricingHandler struct {
+	pricingService *services.PricingService
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(pricingService *services.PricingService) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+	}
+}
+
+// GetPriceQuote gets the price of a class
+// @Summary Get price quote
+// @Description Get the price of a class for a court, timeslot and date
+// @Tags public
+// @Produce json
+// @Param court_id query int true "Court ID"
+// @Param timeslot_id query int true "Timeslot ID"
+// @Param date query string true "Date in YYYY-MM-DD format"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /price [get]
+func (h *PricingHandler) GetPriceQuote(c *gin.Context) {
+	courtID, err := strconv.ParseUint(c.Query("court_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid court_id")
+		return
+	}
+
+	timeslotID, err := strconv.ParseUint(c.Query("timeslot_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid timeslot_id")
+		return
+	}
+
+	amount, breakdown, err := h.pricingService.GetPriceQuote(uint(courtID), uint(timeslotID), c.Query("date"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Price calculated successfully", gin.H{
+		"amount":          amount,
+		"price_breakdown": breakdown,
+	})
+}
+
+// GetRules gets all pricing rules
+func (h *PricingHandler) GetRules(c *gin.Context) {
+	rules, err := h.pricingService.GetRules()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve pricing rules")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pricing rules retrieved successfully", gin.H{
+		"pricing_rules": rules,
+	})
+}
+
+// CreateRule creates a new pricing rule
+func (h *PricingHandler) CreateRule(c *gin.Context) {
+	var req dto.PricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	rule, err := h.pricingService.CreateRule(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Pricing rule created successfully", gin.H{
+		"pricing_rule": rule,
+	})
+}
+
+// UpdateRule updates a pricing rule
+func (h *PricingHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid pricing rule ID")
+		return
+	}
+
+	var req dto.PricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	rule, err := h.pricingService.UpdateRule(uint(id), req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "pricing rule not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pricing rule updated successfully", gin.H{
+		"pricing_rule": rule,
+	})
+}
+
+// DeleteRule deletes a pricing rule
+func (h *PricingHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid pricing rule ID")
+		return
+	}
+
+	if err := h.pricingService.DeleteRule(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "pricing rule not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pricing rule deleted successfully", nil)
+}
@@ -8,7 +8,7 @@ import (
 	"reservation-api/internal/services"
 	"reservation-api/internal/utils"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,18 +35,16 @@ func NewReservationHandler(
 
 // GetAvailableDates gets available dates for booking
 // @Summary Get available dates
-// @Description Get list of available dates for next 30 days
+// @Description Get list of dates in the next 30 days with scheduled classes
 // @Tags public
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Router /dates [get]
 func (h *ReservationHandler) GetAvailableDates(c *gin.Context) {
-	dates := []string{}
-	today := time.Now()
-
-	for i := 0; i < 30; i++ {
-		date := today.AddDate(0, 0, i)
-		dates = append(dates, date.Format("2006-01-02"))
+	dates, err := h.reservationService.GetAvailableDates()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve dates")
+		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Dates retrieved successfully", gin.H{
@@ -56,18 +54,25 @@ func (h *ReservationHandler) GetAvailableDates(c *gin.Context) {
 
 // GetTimeslots gets timeslots with optional availability check
 // @Summary Get timeslots
-// @Description Get all timeslots with availability info if date is provided
+// @Description Get all timeslots, or the timeslots with classes on a date with availability info
 // @Tags public
 // @Produce json
 // @Param date query string false "Date in YYYY-MM-DD format"
+// @Param instructor_id query int false "Only timeslots taught by this instructor (requires date)"
 // @Success 200 {object} map[string]interface{}
 // @Router /timeslots [get]
 func (h *ReservationHandler) GetTimeslots(c *gin.Context) {
 	dateStr := c.Query("date")
 
+	instructorID, err := parseInstructorID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid instructor_id")
+		return
+	}
+
 	// If date provided, return with availability
 	if dateStr != "" {
-		timeslots, err := h.reservationService.GetTimeslotsAvailability(dateStr)
+		timeslots, err := h.reservationService.GetTimeslotsAvailability(dateStr, instructorID)
 		if err != nil {
 			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
@@ -79,6 +84,11 @@ func (h *ReservationHandler) GetTimeslots(c *gin.Context) {
 		return
 	}
 
+	if instructorID != 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "date is required to filter by instructor")
+		return
+	}
+
 	// Return all timeslots without availability
 	timeslots, err := h.timeslotRepo.FindAll()
 	if err != nil {
@@ -98,6 +108,7 @@ func (h *ReservationHandler) GetTimeslots(c *gin.Context) {
 // @Produce json
 // @Param date query string true "Date in YYYY-MM-DD format"
 // @Param timeslot_id query int true "Timeslot ID"
+// @Param instructor_id query int false "Only courts where this instructor teaches"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Router /courts [get]
@@ -116,7 +127,13 @@ func (h *ReservationHandler) GetAvailableCourts(c *gin.Context) {
 		return
 	}
 
-	courts, err := h.reservationService.GetCourtsAvailability(dateStr, uint(timeslotID))
+	instructorID, err := parseInstructorID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid instructor_id")
+		return
+	}
+
+	courts, err := h.reservationService.GetCourtsAvailability(dateStr, uint(timeslotID), instructorID)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -127,9 +144,19 @@ func (h *ReservationHandler) GetAvailableCourts(c *gin.Context) {
 	})
 }
 
+// parseInstructorID parses the optional instructor_id query filter
+func parseInstructorID(c *gin.Context) (uint, error) {
+	value := c.Query("instructor_id")
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	return uint(id), err
+}
+
 // CreateReservation creates a new reservation
 // @Summary Create reservation
-// @Description Create a new reservation for a court
+// @Description Create a new reservation in a class session, given by session_id or court, timeslot and date
 // @Tags reservations
 // @Accept json
 // @Produce json
@@ -154,8 +181,10 @@ func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 	reservation, err := h.reservationService.CreateReservation(userID, req)
 	if err != nil {
 		status := http.StatusBadRequest
-		if err.Error() == "court is already booked for this timeslot" {
+		if err.Error() == "this class is already full. Please select another court or timeslot." {
 			status = http.StatusConflict
+		} else if strings.HasPrefix(err.Error(), "booking restricted") {
+			status = http.StatusForbidden
 		}
 		utils.ErrorResponse(c, status, err.Error())
 		return
@@ -268,4 +297,4 @@ func (h *ReservationHandler) CancelReservation(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Reservation cancelled successfully", gin.H{
 		"reservation": reservation,
 	})
-}
\ No newline at end of file
+}
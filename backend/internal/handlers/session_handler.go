@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler handles class schedule requests
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// GetSessions gets the class sessions on a date
+// @Summary Get class sessions
+// @Description Get scheduled and cancelled class sessions on a date with their court, timeslot and instructor
+// @Tags public
+// @Produce json
+// @Param date query string true "Date in YYYY-MM-DD format"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /sessions [get]
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	dateStr := c.Query("date")
+	if dateStr == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "date is required")
+		return
+	}
+
+	sessions, err := h.sessionService.GetSessions(dateStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", gin.H{
+		"sessions": sessions,
+	})
+}
+
+// GetTemplates gets all class templates
+func (h *SessionHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.sessionService.GetTemplates()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve class templates")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Class templates retrieved successfully", gin.H{
+		"templates": templates,
+	})
+}
+
+// CreateTemplate creates a class template
+func (h *SessionHandler) CreateTemplate(c *gin.Context) {
+	var req dto.ClassTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template, err := h.sessionService.CreateTemplate(req)
+	if err != nil {
+		utils.ErrorResponse(c, templateErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Class template created successfully", gin.H{
+		"template": template,
+	})
+}
+
+// UpdateTemplate updates a class template
+func (h *SessionHandler) UpdateTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid class template ID")
+		return
+	}
+
+	var req dto.ClassTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template, err := h.sessionService.UpdateTemplate(uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, templateErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Class template updated successfully", gin.H{
+		"template": template,
+	})
+}
+
+// DeleteTemplate deletes a class template
+func (h *SessionHandler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid class template ID")
+		return
+	}
+
+	if err := h.sessionService.DeleteTemplate(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "class template not found" {
+			status = http.StatusNotFound
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Class template deleted successfully", nil)
+}
+
+// templateErrorStatus maps class template errors to HTTP status codes
+func templateErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "a class template already exists"):
+		return http.StatusConflict
+	case strings.HasPrefix(err.Error(), "failed to"):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// CreateSession schedules a one-off class session
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	var req dto.CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	session, err := h.sessionService.CreateSession(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.HasPrefix(err.Error(), "a session is already scheduled"),
+			strings.HasPrefix(err.Error(), "instructor already teaches"):
+			status = http.StatusConflict
+		case err.Error() == "failed to create session":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Session created successfully", gin.H{
+		"session": session,
+	})
+}
+
+// UpdateSession replaces the overrides of a class session
+func (h *SessionHandler) UpdateSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	var req dto.UpdateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	session, err := h.sessionService.UpdateSession(uint(id), req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			status = http.StatusNotFound
+		case strings.HasPrefix(err.Error(), "instructor already teaches"),
+			strings.HasPrefix(err.Error(), "capacity cannot be below"):
+			status = http.StatusConflict
+		case err.Error() == "failed to update session":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session updated successfully", gin.H{
+		"session": session,
+	})
+}
+
+// CancelSession cancels a class session and its reservations
+func (h *SessionHandler) CancelSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	var req dto.CancelSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	session, err := h.sessionService.CancelSession(uint(id), req.Reason)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "session not found":
+			status = http.StatusNotFound
+		case "session is already cancelled":
+			status = http.StatusConflict
+		case "failed to cancel session":
+			status = http.StatusInternalServerError
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session cancelled successfully", gin.H{
+		"session": session,
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/middleware"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TicketHandler handles reservation ticket requests
+type TicketHandler struct {
+	ticketService *services.TicketService
+}
+
+// NewTicketHandler creates a new ticket handler
+func NewTicketHandler(ticketService *services.TicketService) *TicketHandler {
+	return &TicketHandler{
+		ticketService: ticketService,
+	}
+}
+
+// GetTicket gets the signed ticket of a confirmed reservation
+func (h *TicketHandler) GetTicket(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reservation ID")
+		return
+	}
+
+	ticket, err := h.ticketService.GetTicket(uint(id), userID)
+	if err != nil {
+		utils.ErrorResponse(c, ticketErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ticket retrieved successfully", gin.H{
+		"ticket": ticket,
+	})
+}
+
+// GetTicketQR gets the ticket of a confirmed reservation as a PNG QR code
+func (h *TicketHandler) GetTicketQR(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reservation ID")
+		return
+	}
+
+	png, err := h.ticketService.GetTicketQR(uint(id), userID)
+	if err != nil {
+		utils.ErrorResponse(c, ticketErrorStatus(err), err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// VerifyTicket validates a scanned ticket and returns its attendee and class
+func (h *TicketHandler) VerifyTicket(c *gin.Context) {
+	var req dto.TicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	verification, err := h.ticketService.GetVerification(req.Token)
+	if err != nil {
+		utils.ErrorResponse(c, ticketErrorStatus(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ticket is valid", gin.H{
+		"ticket": verification,
+	})
+}
+
+// ticketErrorStatus maps ticket errors to HTTP status codes
+func ticketErrorStatus(err error) int {
+	switch {
+	case err.Error() == "reservation not found":
+		return http.StatusNotFound
+	case err.Error() == "unauthorized access to reservation":
+		return http.StatusForbidden
+	case err.Error() == "invalid ticket",
+		err.Error() == "ticket does not match the reservation",
+		strings.HasPrefix(err.Error(), "ticket is no longer valid"):
+		return http.StatusUnprocessableEntity
+	case strings.HasPrefix(err.Error(), "tickets are only available"):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"reservation-api/api/dto"
+	"reservation-api/internal/middleware"
+	"reservation-api/internal/services"
+	"reservation-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WaitlistHandler handles waitlist requests
+type WaitlistHandler struct {
+	waitlistService *services.WaitlistService
+}
+
+// NewWaitlistHandler creates a new waitlist handler
+func NewWaitlistHandler(waitlistService *services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: waitlistService,
+	}
+}
+
+// JoinWaitlist puts the logged-in user on the waitlist of a full class
+// @Summary Join waitlist
+// @Description Wait for a seat in a full class. When a seat is released it is held as a pending reservation for the next user in line.
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.JoinWaitlistRequest true "Class to wait for"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /waitlist [post]
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req dto.JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	entry, err := h.waitlistService.Join(userID, req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "court not found", "timeslot not found":
+			status = http.StatusNotFound
+		case "you already have a reservation for this class", "you are already on the waitlist for this class":
+			status = http.StatusConflict
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Joined waitlist successfully", gin.H{
+		"entry": entry,
+	})
+}
+
+// GetWaitlist gets waitlist entries of the logged-in user
+// @Summary Get waitlist entries
+// @Description Get waitlist entries of the logged-in user with their place in line
+// @Tags waitlist
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /waitlist [get]
+func (h *WaitlistHandler) GetWaitlist(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	entries, err := h.waitlistService.GetUserWaitlist(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve waitlist")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Waitlist retrieved successfully", gin.H{
+		"entries": entries,
+	})
+}
+
+// LeaveWaitlist takes the logged-in user off a waitlist
+// @Summary Leave waitlist
+// @Description Leave a waitlist. An open seat offer is given to the next user in line.
+// @Tags waitlist
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Waitlist entry ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /waitlist/{id} [delete]
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid waitlist entry ID")
+		return
+	}
+
+	if err := h.waitlistService.Leave(uint(id), userID); err != nil {
+		status := http.StatusBadRequest
+		switch err.Error() {
+		case "waitlist entry not found":
+			status = http.StatusNotFound
+		case "unauthorized access to waitlist entry":
+			status = http.StatusForbidden
+		}
+		utils.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Left waitlist successfully", nil)
+}
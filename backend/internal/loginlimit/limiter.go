@@ -0,0 +1,234 @@
+package loginlimit
+
+import (
+	"fmt"
+	"math"
+	"reservation-api/internal/config"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store names selectable via config
+const (
+	Database = "database"
+	Memory   = "memory"
+)
+
+// Attempts are the recent failed logins counted against a key
+type Attempts struct {
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+// Store keeps failed login attempts by key. A record may be dropped once it expires.
+type Store interface {
+	// Get returns the attempts of a key, zero if there are none
+	Get(key string) (Attempts, error)
+
+	// Set stores the attempts of a key until they expire
+	Set(key string, attempts Attempts, expiresAt time.Time) error
+
+	// Delete forgets the attempts of a key
+	Delete(key string) error
+
+	// DeleteExpired drops records that expired before a time. Returns the number dropped.
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// Policy decides how failures slow down and lock out a key. After FreeFailures each
+// failure doubles the wait before the next attempt, starting at BaseDelay and up to
+// MaxDelay. MaxFailures failures within Window lock the key out for Lockout.
+type Policy struct {
+	MaxFailures  int
+	Window       time.Duration
+	Lockout      time.Duration
+	FreeFailures int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+}
+
+// delay returns how long to wait after a number of failures
+func (p Policy) delay(failures int) time.Duration {
+	if failures <= p.FreeFailures {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeFailures + 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// BlockedError is returned while a login is not allowed yet
+type BlockedError struct {
+	RetryAfter time.Duration
+	Locked     bool // Locked out, rather than asked to slow down
+}
+
+func (e *BlockedError) Error() string {
+	if e.RetryAfter >= time.Minute {
+		minutes := int(math.Ceil(e.RetryAfter.Minutes()))
+		return fmt.Sprintf("too many failed login attempts, try again in %d minute(s)", minutes)
+	}
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	return fmt.Sprintf("too many failed login attempts, try again in %d second(s)", seconds)
+}
+
+// Limiter tracks failed logins per account and per IP address and blocks logins
+// that come too fast or after too many failures
+type Limiter struct {
+	store   Store
+	account Policy
+	ip      Policy
+
+	// Serializes read-modify-write of the store within this process
+	mu sync.Mutex
+}
+
+// New creates a new limiter
+func New(store Store, account, ip Policy) *Limiter {
+	return &Limiter{store: store, account: account, ip: ip}
+}
+
+// NewFromConfig creates the limiter set up in configuration, counting failures in the
+// database store or in memory
+func NewFromConfig(cfg *config.Config, database Store) *Limiter {
+	store := database
+	if cfg.LoginLimitStore == Memory {
+		store = NewMemoryStore()
+	}
+
+	// Delays start after a couple of typos on an account, and later for an IP
+	// address that may be shared by many users
+	account := Policy{
+		MaxFailures:  cfg.LoginMaxFailures,
+		Window:       cfg.LoginFailureWindow,
+		Lockout:      cfg.LoginLockout,
+		FreeFailures: 2,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+	ip := Policy{
+		MaxFailures:  cfg.LoginMaxFailuresPerIP,
+		Window:       cfg.LoginFailureWindow,
+		Lockout:      cfg.LoginLockout,
+		FreeFailures: cfg.LoginMaxFailuresPerIP / 2,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+	return New(store, account, ip)
+}
+
+// Check checks that a login for an email address from an IP address is allowed now
+func (l *Limiter) Check(email, ip string, now time.Time) error {
+	var blocked *BlockedError
+	for _, k := range l.keys(email, ip) {
+		attempts, err := l.store.Get(k.key)
+		if err != nil {
+			return err
+		}
+
+		until, locked := k.policy.blockedUntil(attempts)
+		if !now.Before(until) {
+			continue
+		}
+		if blocked == nil || until.Sub(now) > blocked.RetryAfter {
+			blocked = &BlockedError{RetryAfter: until.Sub(now), Locked: locked}
+		}
+	}
+
+	if blocked != nil {
+		return blocked
+	}
+	return nil
+}
+
+// Fail records a failed login. Returns true if the account got locked out by it.
+func (l *Limiter) Fail(email, ip string, now time.Time) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	accountLocked := false
+	for i, k := range l.keys(email, ip) {
+		attempts, err := l.store.Get(k.key)
+		if err != nil {
+			return false, err
+		}
+
+		// Failures outside the window are forgotten
+		if now.Sub(attempts.LastFailureAt) > k.policy.Window {
+			attempts.Failures = 0
+		}
+		attempts.Failures++
+		attempts.LastFailureAt = now
+
+		// A lockout starts the count again once it ends
+		if k.policy.MaxFailures > 0 && attempts.Failures >= k.policy.MaxFailures {
+			attempts.Failures = 0
+			attempts.LockedUntil = now.Add(k.policy.Lockout)
+			accountLocked = accountLocked || i == 0
+		}
+
+		expiresAt := now.Add(k.policy.Window)
+		if attempts.LockedUntil.After(expiresAt) {
+			expiresAt = attempts.LockedUntil
+		}
+		if err := l.store.Set(k.key, attempts, expiresAt); err != nil {
+			return false, err
+		}
+	}
+
+	return accountLocked, nil
+}
+
+// Reset forgets the failed logins of an account, after a successful login or when an
+// admin unlocks it
+func (l *Limiter) Reset(email string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.store.Delete(AccountKey(email))
+}
+
+// DeleteExpired drops attempts that no longer count. Returns the number dropped.
+func (l *Limiter) DeleteExpired(now time.Time) (int64, error) {
+	return l.store.DeleteExpired(now)
+}
+
+// AccountKey is the key of the failed logins of an email address
+func AccountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// IPKey is the key of the failed logins from an IP address
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+type policyKey struct {
+	key    string
+	policy Policy
+}
+
+// keys returns the keys a login counts against with their policies, the account first
+func (l *Limiter) keys(email, ip string) []policyKey {
+	keys := []policyKey{{AccountKey(email), l.account}}
+	if ip != "" {
+		keys = append(keys, policyKey{IPKey(ip), l.ip})
+	}
+	return keys
+}
+
+// blockedUntil returns until when attempts block a key and whether it is a lockout
+func (p Policy) blockedUntil(attempts Attempts) (time.Time, bool) {
+	if !attempts.LockedUntil.IsZero() && attempts.LockedUntil.After(attempts.LastFailureAt) {
+		return attempts.LockedUntil, true
+	}
+	return attempts.LastFailureAt.Add(p.delay(attempts.Failures)), false
+}
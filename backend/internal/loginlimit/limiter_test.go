@@ -0,0 +1,217 @@
+package loginlimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	testAccountPolicy = Policy{
+		MaxFailures:  5,
+		Window:       15 * time.Minute,
+		Lockout:      15 * time.Minute,
+		FreeFailures: 2,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+	testIPPolicy = Policy{
+		MaxFailures:  10,
+		Window:       15 * time.Minute,
+		Lockout:      15 * time.Minute,
+		FreeFailures: 5,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+)
+
+func newTestLimiter() *Limiter {
+	return New(NewMemoryStore(), testAccountPolicy, testIPPolicy)
+}
+
+// checkBlocked asserts that a login is blocked for retryAfter, or allowed if it is zero
+func checkBlocked(t *testing.T, l *Limiter, email, ip string, now time.Time, retryAfter time.Duration, locked bool) {
+	t.Helper()
+	err := l.Check(email, ip, now)
+	if retryAfter == 0 {
+		if err != nil {
+			t.Errorf("login blocked: %v", err)
+		}
+		return
+	}
+
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("err = %v, want a BlockedError", err)
+	}
+	if blocked.RetryAfter != retryAfter || blocked.Locked != locked {
+		t.Errorf("blocked for %s (locked %v), want %s (locked %v)", blocked.RetryAfter, blocked.Locked, retryAfter, locked)
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	want := []time.Duration{
+		0, 0, 0, // Two free failures
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second,
+		30 * time.Second, 30 * time.Second,
+	}
+	for failures, delay := range want {
+		if got := testAccountPolicy.delay(failures); got != delay {
+			t.Errorf("delay(%d) = %s, want %s", failures, got, delay)
+		}
+	}
+}
+
+func TestLimiterProgressiveDelay(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	// The wait after each failure: two are free, then it doubles
+	for _, wait := range []time.Duration{0, 0, time.Second, 2 * time.Second} {
+		checkBlocked(t, l, "member@example.com", "203.0.113.1", now, 0, false)
+		if _, err := l.Fail("member@example.com", "203.0.113.1", now); err != nil {
+			t.Fatal(err)
+		}
+		checkBlocked(t, l, "member@example.com", "203.0.113.1", now, wait, false)
+		if wait > 0 {
+			checkBlocked(t, l, "member@example.com", "203.0.113.1", now.Add(wait-time.Millisecond), time.Millisecond, false)
+		}
+		now = now.Add(wait)
+	}
+
+	// Another account from the same address is not slowed down yet
+	checkBlocked(t, l, "other@example.com", "203.0.113.1", now, 0, false)
+}
+
+func TestLimiterLocksOutAtMaxFailures(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= testAccountPolicy.MaxFailures; i++ {
+		locked, err := l.Fail("Member@Example.com ", "", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked != (i == testAccountPolicy.MaxFailures) {
+			t.Errorf("failure %d: locked = %v", i, locked)
+		}
+		now = now.Add(time.Minute)
+	}
+	lockedAt := now.Add(-time.Minute)
+
+	// Email addresses are counted case-insensitively
+	checkBlocked(t, l, "member@example.com", "", now, lockedAt.Add(testAccountPolicy.Lockout).Sub(now), true)
+	if err := l.Check("member@example.com", "", now); err == nil || err.Error() != "too many failed login attempts, try again in 14 minute(s)" {
+		t.Errorf("err = %v", err)
+	}
+
+	// The count starts again once the lockout ends
+	now = lockedAt.Add(testAccountPolicy.Lockout)
+	checkBlocked(t, l, "member@example.com", "", now, 0, false)
+	if locked, err := l.Fail("member@example.com", "", now); err != nil || locked {
+		t.Fatalf("first failure after lockout: locked = %v, err = %v", locked, err)
+	}
+	checkBlocked(t, l, "member@example.com", "", now, 0, false)
+}
+
+func TestLimiterPerIP(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	// One failure for each of many accounts, as in credential stuffing
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com",
+		"f@example.com", "g@example.com", "h@example.com", "i@example.com", "j@example.com"}
+	for i, email := range emails {
+		locked, err := l.Fail(email, "203.0.113.1", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked {
+			t.Errorf("account %s locked by an IP address lockout", email)
+		}
+		if i == testIPPolicy.FreeFailures {
+			checkBlocked(t, l, "new@example.com", "203.0.113.1", now, time.Second, false)
+		}
+		now = now.Add(30 * time.Second)
+	}
+	lockedAt := now.Add(-30 * time.Second)
+
+	checkBlocked(t, l, "new@example.com", "203.0.113.1", now, lockedAt.Add(testIPPolicy.Lockout).Sub(now), true)
+	checkBlocked(t, l, "new@example.com", "198.51.100.7", now, 0, false)
+	checkBlocked(t, l, "new@example.com", "", now, 0, false)
+}
+
+func TestLimiterReset(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < testAccountPolicy.MaxFailures; i++ {
+		if _, err := l.Fail("member@example.com", "203.0.113.1", now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkBlocked(t, l, "member@example.com", "198.51.100.7", now, testAccountPolicy.Lockout, true)
+
+	// A successful login or an admin unlock clears the account, not the address
+	if err := l.Reset("MEMBER@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	checkBlocked(t, l, "member@example.com", "198.51.100.7", now, 0, false)
+
+	attempts, err := l.store.Get(IPKey("203.0.113.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts.Failures != testAccountPolicy.MaxFailures {
+		t.Errorf("IP address failures = %d after reset, want %d", attempts.Failures, testAccountPolicy.MaxFailures)
+	}
+}
+
+func TestLimiterWindowExpiry(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	// Failures further apart than the window never add up to a delay or lockout
+	for i := 0; i < 2*testAccountPolicy.MaxFailures; i++ {
+		locked, err := l.Fail("member@example.com", "", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked {
+			t.Fatalf("failure %d locked the account", i+1)
+		}
+		checkBlocked(t, l, "member@example.com", "", now, 0, false)
+		now = now.Add(testAccountPolicy.Window + time.Second)
+	}
+
+	// Records are dropped once they no longer count
+	deleted, err := l.DeleteExpired(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted %d record(s), want 1", deleted)
+	}
+	if attempts, _ := l.store.Get(AccountKey("member@example.com")); attempts.Failures != 0 {
+		t.Errorf("failures = %d after expiry, want 0", attempts.Failures)
+	}
+}
+
+func TestLockoutOutlivesWindow(t *testing.T) {
+	account := testAccountPolicy
+	account.Lockout = time.Hour
+	l := New(NewMemoryStore(), account, testIPPolicy)
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < account.MaxFailures; i++ {
+		if _, err := l.Fail("member@example.com", "", now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The record is kept until the lockout ends, not just for the window
+	if deleted, _ := l.DeleteExpired(now.Add(account.Window + time.Minute)); deleted != 0 {
+		t.Errorf("deleted %d record(s) of a locked account", deleted)
+	}
+	checkBlocked(t, l, "member@example.com", "", now.Add(account.Window+time.Minute), account.Lockout-account.Window-time.Minute, true)
+}
@@ -0,0 +1,64 @@
+package loginlimit
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps failed logins in process memory. Counts are lost on restart and
+// not shared between instances, so it suits tests and single-instance development.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+}
+
+type memoryRecord struct {
+	attempts  Attempts
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]memoryRecord)}
+}
+
+// Get returns the attempts of a key, zero if there are none
+func (s *MemoryStore) Get(key string) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.records[key].attempts, nil
+}
+
+// Set stores the attempts of a key until they expire
+func (s *MemoryStore) Set(key string, attempts Attempts, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = memoryRecord{attempts: attempts, expiresAt: expiresAt}
+	return nil
+}
+
+// Delete forgets the attempts of a key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// DeleteExpired drops records that expired before a time
+func (s *MemoryStore) DeleteExpired(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, record := range s.records {
+		if record.expiresAt.Before(now) {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
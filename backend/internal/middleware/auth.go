@@ -3,14 +3,22 @@ package middleware
 import (
 	"net/http"
 	"reservation-api/internal/config"
+	"reservation-api/internal/models"
 	"reservation-api/internal/utils"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token and sets user info in context
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+// SessionValidator checks that the session an access token was issued for is still
+// active, returning its current user
+type SessionValidator interface {
+	ValidateSession(sessionID, userID uint) (*models.User, error)
+}
+
+// AuthMiddleware validates JWT token and its session, and sets user info in context.
+// Tokens of revoked sessions or deactivated users are rejected even before they expire.
+func AuthMiddleware(cfg *config.Config, sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -44,34 +52,58 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Set user info in context
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
+		// Validate session
+		user, err := sessions.ValidateSession(claims.SessionID, claims.UserID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Session is no longer valid: " + err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		// Set user info in context, taking email and role from the current user
+		c.Set("user_id", user.ID)
+		c.Set("email", user.Email)
+		c.Set("role", user.Role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
 }
 
-// AdminMiddleware checks if user has admin privileges
-func AdminMiddleware() gin.HandlerFunc {
+// RequireRole allows the request only if the authenticated user has one of the given roles.
+// It must run after AuthMiddleware.
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// TODO: Implement admin check
-		// For now, we'll allow all authenticated users to access admin routes
-		// In production, you should check user role from database
-
-		// Example implementation:
-		// userID := c.GetUint("user_id")
-		// user, err := userRepo.FindByID(userID)
-		// if err != nil || !user.IsAdmin {
-		//     c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-		//     c.Abort()
-		//     return
-		// }
+		role, exists := GetRole(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User not authenticated",
+			})
+			c.Abort()
+			return
+		}
 
-		c.Next()
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "You do not have permission to access this resource",
+		})
+		c.Abort()
 	}
 }
 
+// AdminMiddleware checks if user has admin privileges
+func AdminMiddleware() gin.HandlerFunc {
+	return RequireRole(models.RoleAdmin)
+}
+
 // GetUserID gets user ID from context
 func GetUserID(c *gin.Context) (uint, bool) {
 	userID, exists := c.Get("user_id")
@@ -88,4 +120,22 @@ func GetEmail(c *gin.Context) (string, bool) {
 		return "", false
 	}
 	return email.(string), true
-}
\ No newline at end of file
+}
+
+// GetSessionID gets the session of the access token from context
+func GetSessionID(c *gin.Context) (uint, bool) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		return 0, false
+	}
+	return sessionID.(uint), true
+}
+
+// GetRole gets user role from context
+func GetRole(c *gin.Context) (models.UserRole, bool) {
+	role, exists := c.Get("role")
+	if !exists {
+		return "", false
+	}
+	return role.(models.UserRole), true
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeSessions validates sessions against a fixed set of users, one session each
+type fakeSessions map[uint]*models.User
+
+func (f fakeSessions) ValidateSession(sessionID, userID uint) (*models.User, error) {
+	user, ok := f[sessionID]
+	if !ok || user.ID != userID {
+		return nil, errors.New("session not found")
+	}
+	return user, nil
+}
+
+func TestRoleMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	roles := []models.UserRole{models.RoleMember, models.RoleInstructor, models.RoleFrontDesk, models.RoleAdmin}
+	sessions := fakeSessions{}
+	tokens := map[models.UserRole]string{}
+	for i, role := range roles {
+		user := &models.User{Email: string(role) + "@example.com", Role: role}
+		user.ID = uint(i + 1)
+		sessions[uint(100+i)] = user
+
+		token, err := utils.GenerateToken(user.ID, user.Email, string(role), uint(100+i), time.Hour, cfg.JWTSecret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens[role] = token
+	}
+
+	// The route groups of the API
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	auth := AuthMiddleware(cfg, sessions)
+	router.GET("/member", auth, ok)
+	router.GET("/instructor", auth, RequireRole(models.RoleInstructor), ok)
+	router.GET("/desk", auth, RequireRole(models.RoleFrontDesk, models.RoleAdmin), ok)
+	router.GET("/admin", auth, AdminMiddleware(), ok)
+	router.GET("/unauthenticated", AdminMiddleware(), ok)
+
+	tests := []struct {
+		path string
+		want map[models.UserRole]int
+	}{
+		{"/member", map[models.UserRole]int{
+			models.RoleMember: http.StatusOK, models.RoleInstructor: http.StatusOK,
+			models.RoleFrontDesk: http.StatusOK, models.RoleAdmin: http.StatusOK,
+		}},
+		{"/instructor", map[models.UserRole]int{
+			models.RoleMember: http.StatusForbidden, models.RoleInstructor: http.StatusOK,
+			models.RoleFrontDesk: http.StatusForbidden, models.RoleAdmin: http.StatusForbidden,
+		}},
+		{"/desk", map[models.UserRole]int{
+			models.RoleMember: http.StatusForbidden, models.RoleInstructor: http.StatusForbidden,
+			models.RoleFrontDesk: http.StatusOK, models.RoleAdmin: http.StatusOK,
+		}},
+		{"/admin", map[models.UserRole]int{
+			models.RoleMember: http.StatusForbidden, models.RoleInstructor: http.StatusForbidden,
+			models.RoleFrontDesk: http.StatusForbidden, models.RoleAdmin: http.StatusOK,
+		}},
+		// Without AuthMiddleware there is no role to check
+		{"/unauthenticated", map[models.UserRole]int{
+			models.RoleMember: http.StatusUnauthorized, models.RoleAdmin: http.StatusUnauthorized,
+		}},
+	}
+
+	for _, tt := range tests {
+		for role, want := range tt.want {
+			t.Run(tt.path+" as "+string(role), func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				req.Header.Set("Authorization", "Bearer "+tokens[role])
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if rec.Code != want {
+					t.Errorf("status = %d, want %d: %s", rec.Code, want, rec.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestRoleComesFromCurrentUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	// Demoted after the token was issued
+	user := &models.User{Email: "former-admin@example.com", Role: models.RoleMember}
+	user.ID = 1
+	token, err := utils.GenerateToken(user.ID, user.Email, string(models.RoleAdmin), 7, time.Hour, cfg.JWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.GET("/admin", AuthMiddleware(cfg, fakeSessions{7: user}), AdminMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestAuthMiddlewareRejectsBadTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	user := &models.User{Email: "member@example.com", Role: models.RoleMember}
+	user.ID = 1
+	valid, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), 7, time.Hour, cfg.JWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := utils.GenerateToken(user.ID, user.Email, string(models.RoleAdmin), 7, time.Hour, "other-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), 8, time.Hour, cfg.JWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.GET("/member", AuthMiddleware(cfg, fakeSessions{7: user}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"valid", "Bearer " + valid, http.StatusOK},
+		{"no header", "", http.StatusUnauthorized},
+		{"not bearer", "Basic " + valid, http.StatusUnauthorized},
+		{"other secret", "Bearer " + forged, http.StatusUnauthorized},
+		{"revoked session", "Bearer " + revoked, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/member", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
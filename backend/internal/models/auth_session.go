@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reasons a session was revoked
+const (
+	RevokeLogout         = "logout"
+	RevokeTokenReuse     = "refresh token reuse"
+	RevokeUserInactive   = "user inactive"
+	RevokePasswordReset  = "password reset"
+	RevokePasswordChange = "password change"
+	RevokeAccountDeleted = "account deleted"
+	RevokeIdentityLinked = "identity linked"
+)
+
+// AuthSession is a sign-in of a user on a device. Access tokens carry the session ID
+// so revoking the session signs them out; its refresh tokens rotate on every use.
+type AuthSession struct {
+	gorm.Model
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	UserAgent     string     `json:"user_agent"`
+	IPAddress     string     `json:"ip_address"`
+	LastUsedAt    time.Time  `json:"last_used_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"`
+
+	// Relation
+	User *User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for AuthSession model
+func (AuthSession) TableName() string {
+	return "auth_sessions"
+}
+
+// IsRevoked checks if the session was revoked
+func (s *AuthSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// RefreshToken is a single-use token that renews the access token of a session.
+// Only a hash of the token is stored.
+type RefreshToken struct {
+	gorm.Model
+	SessionID uint       `gorm:"not null;index"`
+	TokenHash string     `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	UsedAt    *time.Time // Set when rotated. Presenting it again revokes the session.
+
+	// Relation
+	Session *AuthSession `gorm:"foreignKey:SessionID"`
+}
+
+// TableName specifies the table name for RefreshToken model
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// IsUsed checks if the token was already rotated
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// BookingRules are the booking and cancellation rules of a court or session. A rule
+// left empty falls back to the court, then to the studio defaults.
+type BookingRules struct {
+	MinLeadMinutes      *int  `json:"min_lead_minutes,omitempty" binding:"omitempty,min=0"`      // Booking closes this long before class start
+	MaxAdvanceDays      *int  `json:"max_advance_days,omitempty" binding:"omitempty,min=0"`      // Booking opens this many days before class start
+	CancelDeadlineHours *int  `json:"cancel_deadline_hours,omitempty" binding:"omitempty,min=0"` // Later cancellations are late cancels
+	AllowLateCancel     *bool `json:"allow_late_cancel,omitempty"`                               // Late cancels are flagged instead of refused
+}
+
+// BookingPolicy is the effective set of booking rules of a class
+type BookingPolicy struct {
+	MinLead         time.Duration
+	MaxAdvanceDays  int
+	CancelDeadline  time.Duration
+	AllowLateCancel bool
+}
+
+// With returns the policy overridden by the rules that are set
+func (p BookingPolicy) With(rules BookingRules) BookingPolicy {
+	if rules.MinLeadMinutes != nil {
+		p.MinLead = time.Duration(*rules.MinLeadMinutes) * time.Minute
+	}
+	if rules.MaxAdvanceDays != nil {
+		p.MaxAdvanceDays = *rules.MaxAdvanceDays
+	}
+	if rules.CancelDeadlineHours != nil {
+		p.CancelDeadline = time.Duration(*rules.CancelDeadlineHours) * time.Hour
+	}
+	if rules.AllowLateCancel != nil {
+		p.AllowLateCancel = *rules.AllowLateCancel
+	}
+	return p
+}
+
+// BookingOpensAt returns when booking of a class starting at classStart opens.
+// Days are counted in the time zone of classStart.
+func (p BookingPolicy) BookingOpensAt(classStart time.Time) time.Time {
+	return classStart.AddDate(0, 0, -p.MaxAdvanceDays)
+}
+
+// BookingClosesAt returns when booking of a class starting at classStart closes
+func (p BookingPolicy) BookingClosesAt(classStart time.Time) time.Time {
+	return classStart.Add(-p.MinLead)
+}
+
+// CancelDeadlineAt returns the time after which cancelling a class starting at
+// classStart is a late cancel
+func (p BookingPolicy) CancelDeadlineAt(classStart time.Time) time.Time {
+	return classStart.Add(-p.CancelDeadline)
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClassTemplate is a weekly schedule entry: a court runs a timeslot on a weekday.
+// Class sessions are generated from active templates.
+type ClassTemplate struct {
+	gorm.Model
+	CourtID    uint `json:"court_id" gorm:"not null;uniqueIndex:idx_class_template"`
+	TimeslotID uint `json:"timeslot_id" gorm:"not null;uniqueIndex:idx_class_template"`
+	Weekday    int  `json:"weekday" gorm:"not null;uniqueIndex:idx_class_template"` // 0 = Sunday ... 6 = Saturday
+	IsActive   bool `json:"is_active" gorm:"default:true"`
+
+	// Defaults copied to generated sessions
+	InstructorID *uint    `json:"instructor_id,omitempty"`
+	Capacity     *int     `json:"capacity,omitempty"`
+	Price        *float64 `json:"price,omitempty"`
+
+	// Relations
+	Court      *Court      `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Timeslot   *Timeslot   `json:"timeslot,omitempty" gorm:"foreignKey:TimeslotID"`
+	Instructor *Instructor `json:"instructor,omitempty" gorm:"foreignKey:InstructorID"`
+}
+
+// TableName specifies the table name for ClassTemplate model
+func (ClassTemplate) TableName() string {
+	return "class_templates"
+}
+
+// SessionStatus defines the status of a class session
+type SessionStatus string
+
+const (
+	SessionScheduled SessionStatus = "scheduled"
+	SessionCancelled SessionStatus = "cancelled"
+)
+
+// ClassSession is one scheduled class: a court running a timeslot on a date.
+// Reservations book seats in a session.
+type ClassSession struct {
+	gorm.Model
+	CourtID    uint          `json:"court_id" gorm:"not null;uniqueIndex:idx_class_session"`
+	TimeslotID uint          `json:"timeslot_id" gorm:"not null;uniqueIndex:idx_class_session"`
+	Date       time.Time     `json:"date" gorm:"not null;uniqueIndex:idx_class_session;index"`
+	TemplateID *uint         `json:"template_id,omitempty" gorm:"index"` // Empty for one-off sessions
+	Status     SessionStatus `json:"status" gorm:"default:'scheduled'"`
+
+	// Per-session overrides
+	InstructorID *uint    `json:"instructor_id,omitempty" gorm:"index"`
+	Capacity     *int     `json:"capacity,omitempty"` // Overrides the court capacity
+	Price        *float64 `json:"price,omitempty"`    // Overrides the calculated price
+
+	// Booking rules overriding those of the court
+	BookingRules `gorm:"embedded"`
+
+	CancelReason string `json:"cancel_reason,omitempty"`
+	ClosureID    *uint  `json:"closure_id,omitempty" gorm:"index"` // Set when cancelled by a closure
+
+	// Relations
+	Court      *Court      `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Timeslot   *Timeslot   `json:"timeslot,omitempty" gorm:"foreignKey:TimeslotID"`
+	Instructor *Instructor `json:"instructor,omitempty" gorm:"foreignKey:InstructorID"`
+}
+
+// TableName specifies the table name for ClassSession model
+func (ClassSession) TableName() string {
+	return "class_sessions"
+}
+
+// IsCancelled checks if the session was cancelled
+func (s *ClassSession) IsCancelled() bool {
+	return s.Status == SessionCancelled
+}
+
+// BookingPolicy returns the defaults overridden by the booking rules of the court,
+// when loaded, and then of the session
+func (s *ClassSession) BookingPolicy(defaults BookingPolicy) BookingPolicy {
+	if s.Court != nil {
+		defaults = defaults.With(s.Court.BookingRules)
+	}
+	return defaults.With(s.BookingRules)
+}
+
+// SeatCapacity returns the number of seats of the session. Court must be loaded
+// unless the capacity is overridden.
+func (s *ClassSession) SeatCapacity() int {
+	if s.Capacity != nil {
+		return *s.Capacity
+	}
+	if s.Court != nil {
+		return s.Court.Capacity
+	}
+	return 0
+}
+
+// StartTime returns the session start in the given location. Timeslot must be loaded.
+func (s *ClassSession) StartTime(loc *time.Location) (time.Time, error) {
+	if s.Timeslot == nil {
+		return time.Time{}, errors.New("session timeslot not loaded")
+	}
+	return classStart(s.Date, s.Timeslot, loc)
+}
+
+// EndTime returns the session end in the given location. Timeslot must be loaded.
+func (s *ClassSession) EndTime(loc *time.Location) (time.Time, error) {
+	start, err := s.StartTime(loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.Add(time.Duration(s.Timeslot.Duration) * time.Minute), nil
+}
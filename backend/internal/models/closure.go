@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Closure closes the studio, a single court or a single timeslot for a date range.
+// No classes are scheduled or booked while a closure covers them.
+type Closure struct {
+	gorm.Model
+	Name       string    `json:"name" gorm:"not null"`
+	CourtID    *uint     `json:"court_id,omitempty" gorm:"index"`    // Empty closes all courts
+	TimeslotID *uint     `json:"timeslot_id,omitempty" gorm:"index"` // Empty closes all timeslots
+	StartDate  time.Time `json:"start_date" gorm:"not null;index"`
+	EndDate    time.Time `json:"end_date" gorm:"not null;index"` // Inclusive
+
+	// Recurring closures repeat every year on the same days, e.g. public holidays
+	Recurring bool `json:"recurring" gorm:"default:false"`
+
+	// Relations
+	Court    *Court    `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Timeslot *Timeslot `json:"timeslot,omitempty" gorm:"foreignKey:TimeslotID"`
+}
+
+// TableName specifies the table name for Closure model
+func (Closure) TableName() string {
+	return "closures"
+}
+
+// Covers checks if the closure applies to a court and timeslot on a date
+func (c *Closure) Covers(courtID, timeslotID uint, date time.Time) bool {
+	if c.CourtID != nil && *c.CourtID != courtID {
+		return false
+	}
+	if c.TimeslotID != nil && *c.TimeslotID != timeslotID {
+		return false
+	}
+
+	if !c.Recurring {
+		return !date.Before(c.StartDate) && !date.After(c.EndDate)
+	}
+
+	// Compare month and day only; a range may wrap over the new year
+	day := monthDay(date)
+	start, end := monthDay(c.StartDate), monthDay(c.EndDate)
+	if start <= end {
+		return day >= start && day <= end
+	}
+	return day >= start || day <= end
+}
+
+// monthDay encodes the month and day of a date as MMDD
+func monthDay(date time.Time) int {
+	return int(date.Month())*100 + date.Day()
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClosureCovers(t *testing.T) {
+	id := func(v uint) *uint { return &v }
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	once := Closure{StartDate: day(2030, 3, 10), EndDate: day(2030, 3, 12)}
+	yearEnd := Closure{StartDate: day(2029, 12, 24), EndDate: day(2030, 1, 2), Recurring: true}
+	holiday := Closure{StartDate: day(2029, 8, 17), EndDate: day(2029, 8, 17), Recurring: true}
+	leapDay := Closure{StartDate: day(2028, 2, 29), EndDate: day(2028, 2, 29), Recurring: true}
+	court := Closure{CourtID: id(1), StartDate: day(2030, 3, 10), EndDate: day(2030, 3, 12)}
+	timeslot := Closure{TimeslotID: id(6), StartDate: day(2030, 3, 10), EndDate: day(2030, 3, 12)}
+	courtSlot := Closure{CourtID: id(1), TimeslotID: id(6), StartDate: day(2030, 3, 10), EndDate: day(2030, 3, 12)}
+
+	tests := []struct {
+		name       string
+		closure    Closure
+		courtID    uint
+		timeslotID uint
+		date       time.Time
+		want       bool
+	}{
+		{"one-off first day", once, 1, 6, day(2030, 3, 10), true},
+		{"one-off last day", once, 1, 6, day(2030, 3, 12), true},
+		{"one-off day before", once, 1, 6, day(2030, 3, 9), false},
+		{"one-off day after", once, 1, 6, day(2030, 3, 13), false},
+		{"one-off a year later", once, 1, 6, day(2031, 3, 11), false},
+
+		{"recurring same day another year", holiday, 1, 6, day(2035, 8, 17), true},
+		{"recurring year before it was created", holiday, 1, 6, day(2028, 8, 17), true},
+		{"recurring next day", holiday, 1, 6, day(2035, 8, 18), false},
+		{"recurring leap day in a leap year", leapDay, 1, 6, day(2032, 2, 29), true},
+		{"recurring leap day in other years", leapDay, 1, 6, day(2031, 3, 1), false},
+
+		{"wrapping over year end: first day", yearEnd, 1, 6, day(2033, 12, 24), true},
+		{"wrapping over year end: new year's eve", yearEnd, 1, 6, day(2033, 12, 31), true},
+		{"wrapping over year end: new year's day", yearEnd, 1, 6, day(2034, 1, 1), true},
+		{"wrapping over year end: last day", yearEnd, 1, 6, day(2034, 1, 2), true},
+		{"wrapping over year end: day before", yearEnd, 1, 6, day(2033, 12, 23), false},
+		{"wrapping over year end: day after", yearEnd, 1, 6, day(2034, 1, 3), false},
+		{"wrapping over year end: mid year", yearEnd, 1, 6, day(2034, 6, 15), false},
+
+		{"court closure, same court", court, 1, 6, day(2030, 3, 11), true},
+		{"court closure, other court", court, 2, 6, day(2030, 3, 11), false},
+		{"court closure, any timeslot", court, 1, 9, day(2030, 3, 11), true},
+		{"timeslot closure, same timeslot", timeslot, 2, 6, day(2030, 3, 11), true},
+		{"timeslot closure, other timeslot", timeslot, 2, 7, day(2030, 3, 11), false},
+		{"court and timeslot closure, both match", courtSlot, 1, 6, day(2030, 3, 11), true},
+		{"court and timeslot closure, other timeslot", courtSlot, 1, 7, day(2030, 3, 11), false},
+		{"court and timeslot closure, other court", courtSlot, 2, 6, day(2030, 3, 11), false},
+		{"court closure, other court outside dates", court, 2, 6, day(2030, 3, 20), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.closure.Covers(tt.courtID, tt.timeslotID, tt.date); got != tt.want {
+				t.Errorf("Covers(%d, %d, %s) = %v, want %v",
+					tt.courtID, tt.timeslotID, tt.date.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
@@ -12,9 +12,12 @@ type Court struct {
 	Description  string        `json:"description"`
 	IsActive     bool          `json:"is_active" gorm:"default:true"`
 	Reservations []Reservation `json:"reservations,omitempty" gorm:"foreignKey:CourtID"`
+
+	// Booking rules of classes on the court, defaulting to the studio rules
+	BookingRules `gorm:"embedded"`
 }
 
 // TableName specifies the table name for Court model
 func (Court) TableName() string {
 	return "courts"
-}
\ No newline at end of file
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreditPackage is a purchasable bundle of class credits
+type CreditPackage struct {
+	gorm.Model
+	Name         string  `json:"name" gorm:"not null"`
+	Description  string  `json:"description"`
+	Credits      int     `json:"credits" gorm:"not null"`
+	Price        float64 `json:"price" gorm:"not null"`
+	ValidityDays int     `json:"validity_days" gorm:"not null"` // Credits expire this many days after purchase
+	IsActive     bool    `json:"is_active" gorm:"default:true"`
+}
+
+// TableName specifies the table name for CreditPackage model
+func (CreditPackage) TableName() string {
+	return "credit_packages"
+}
+
+// CreditPurchaseStatus defines the status of a credit purchase
+type CreditPurchaseStatus string
+
+const (
+	PurchasePending   CreditPurchaseStatus = "pending"
+	PurchasePaid      CreditPurchaseStatus = "paid"
+	PurchaseCancelled CreditPurchaseStatus = "cancelled"
+)
+
+// CreditPurchase is a package bought by a user. Once paid it is a lot of credits
+// that are redeemed for bookings until used up or expired.
+type CreditPurchase struct {
+	gorm.Model
+	UserID           uint                 `json:"user_id" gorm:"not null;index"`
+	PackageID        uint                 `json:"package_id" gorm:"not null"`
+	Credits          int                  `json:"credits" gorm:"not null"`
+	CreditsRemaining int                  `json:"credits_remaining" gorm:"not null;default:0"`
+	Price            float64              `json:"price" gorm:"not null"`
+	Status           CreditPurchaseStatus `json:"status" gorm:"default:'pending'"`
+	PaidAt           *time.Time           `json:"paid_at,omitempty"`
+	ExpiresAt        *time.Time           `json:"expires_at,omitempty"`
+
+	// Relations
+	User    *User          `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Package *CreditPackage `json:"package,omitempty" gorm:"foreignKey:PackageID"`
+}
+
+// TableName specifies the table name for CreditPurchase model
+func (CreditPurchase) TableName() string {
+	return "credit_purchases"
+}
+
+// IsUsable checks if the purchase still has unexpired credits at the given time
+func (p *CreditPurchase) IsUsable(now time.Time) bool {
+	return p.Status == PurchasePaid && p.CreditsRemaining > 0 &&
+		p.ExpiresAt != nil && now.Before(*p.ExpiresAt)
+}
+
+// CreditTransactionType defines the kind of credit movement
+type CreditTransactionType string
+
+const (
+	CreditPurchased CreditTransactionType = "purchase" // Credits added by a paid package
+	CreditRedeemed  CreditTransactionType = "redeem"   // Credit spent on a booking
+	CreditReturned  CreditTransactionType = "return"   // Credit given back on cancellation
+)
+
+// CreditTransaction is an entry of a user's credit ledger
+type CreditTransaction struct {
+	gorm.Model
+	UserID        uint                  `json:"user_id" gorm:"not null;index"`
+	PurchaseID    uint                  `json:"purchase_id" gorm:"not null;index"`
+	ReservationID *uint                 `json:"reservation_id,omitempty" gorm:"index"`
+	Type          CreditTransactionType `json:"type" gorm:"not null"`
+	Amount        int                   `json:"amount" gorm:"not null"` // Positive adds credits, negative spends them
+	Note          string                `json:"note,omitempty"`
+}
+
+// TableName specifies the table name for CreditTransaction model
+func (CreditTransaction) TableName() string {
+	return "credit_transactions"
+}
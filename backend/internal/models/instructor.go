@@ -0,0 +1,146 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Instructor is a teacher profile of a user with the instructor role
+type Instructor struct {
+	gorm.Model
+	UserID       uint               `json:"user_id" gorm:"not null;uniqueIndex"`
+	Name         string             `json:"name" gorm:"not null"`
+	Bio          string             `json:"bio"`
+	Specialties  StringList         `json:"specialties" gorm:"type:text"`
+	Availability WeeklyAvailability `json:"availability" gorm:"type:text"` // Empty means any time
+	IsActive     bool               `json:"is_active" gorm:"default:true"`
+
+	// Relations
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for Instructor model
+func (Instructor) TableName() string {
+	return "instructors"
+}
+
+// IsAvailable checks if the instructor can teach a class of the given length
+// starting at start. Instructors without availability windows are always available.
+func (i *Instructor) IsAvailable(start time.Time, duration int) bool {
+	if len(i.Availability) == 0 {
+		return true
+	}
+
+	begin := start.Hour()*60 + start.Minute()
+	end := begin + duration
+	for _, window := range i.Availability {
+		if window.Weekday != int(start.Weekday()) {
+			continue
+		}
+		from, err := parseClock(window.Start)
+		if err != nil {
+			continue
+		}
+		to, err := parseClock(window.End)
+		if err != nil {
+			continue
+		}
+		if begin >= from && end <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailabilityWindow is a weekly time range, e.g. Monday 07:00-12:00
+type AvailabilityWindow struct {
+	Weekday int    `json:"weekday"` // 0 = Sunday ... 6 = Saturday
+	Start   string `json:"start"`   // Format: "HH:MM"
+	End     string `json:"end"`     // Format: "HH:MM"
+}
+
+// WeeklyAvailability lists the weekly time ranges of an instructor, stored as JSON
+type WeeklyAvailability []AvailabilityWindow
+
+// Value implements driver.Valuer
+func (a WeeklyAvailability) Value() (driver.Value, error) {
+	return jsonValue(a)
+}
+
+// Scan implements sql.Scanner
+func (a *WeeklyAvailability) Scan(value interface{}) error {
+	return jsonScan(value, a)
+}
+
+// StringList is a list of strings stored as JSON
+type StringList []string
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	return jsonValue(l)
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	return jsonScan(value, l)
+}
+
+// jsonValue encodes a value as a JSON string column
+func jsonValue(v interface{}) (driver.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// jsonScan decodes a JSON string column into dest
+func jsonScan(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	case []byte:
+		return json.Unmarshal(v, dest)
+	}
+	return errors.New("invalid JSON column value")
+}
+
+// parseClock parses "HH:MM" into minutes since midnight
+func parseClock(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// ValidateAvailability checks that every window has a valid weekday and time range
+func ValidateAvailability(windows []AvailabilityWindow) error {
+	for _, window := range windows {
+		if window.Weekday < 0 || window.Weekday > 6 {
+			return fmt.Errorf("invalid weekday %d", window.Weekday)
+		}
+		from, err := parseClock(window.Start)
+		if err != nil {
+			return err
+		}
+		to, err := parseClock(window.End)
+		if err != nil {
+			return err
+		}
+		if to <= from {
+			return fmt.Errorf("availability end %s must be after start %s", window.End, window.Start)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginFailureReason explains why a login failed
+type LoginFailureReason string
+
+const (
+	LoginUnknownEmail  LoginFailureReason = "unknown_email"
+	LoginWrongPassword LoginFailureReason = "wrong_password"
+	LoginBlocked       LoginFailureReason = "blocked" // Rejected by the limiter without checking the password
+)
+
+// LoginAttempt holds the recent failed logins of an account or IP address, keyed as
+// "account:<email>" or "ip:<address>", for brute-force protection
+type LoginAttempt struct {
+	Key           string     `gorm:"primaryKey"`
+	Failures      int        `gorm:"not null;default:0"`
+	LastFailureAt time.Time  `gorm:"not null"`
+	LockedUntil   *time.Time // Set while locked out
+	ExpiresAt     time.Time  `gorm:"not null;index"` // The record no longer counts after this
+	UpdatedAt     time.Time
+}
+
+// TableName specifies the table name for LoginAttempt model
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// LoginFailure is an audit record of a failed login
+type LoginFailure struct {
+	gorm.Model
+	Email     string             `json:"email" gorm:"not null;index"`
+	UserID    *uint              `json:"user_id,omitempty" gorm:"index"` // Set if the email belongs to an account
+	IPAddress string             `json:"ip_address" gorm:"index"`
+	UserAgent string             `json:"user_agent"`
+	Reason    LoginFailureReason `json:"reason" gorm:"not null"`
+	Locked    bool               `json:"locked"` // The failure locked the account out
+}
+
+// TableName specifies the table name for LoginFailure model
+func (LoginFailure) TableName() string {
+	return "login_failures"
+}
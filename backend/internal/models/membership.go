@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MembershipPlan is a monthly subscription giving access to classes
+type MembershipPlan struct {
+	gorm.Model
+	Name               string  `json:"name" gorm:"not null"`
+	Description        string  `json:"description"`
+	Price              float64 `json:"price" gorm:"not null"`                 // Charged every month
+	WeeklyBookingLimit int     `json:"weekly_booking_limit" gorm:"default:0"` // 0 means unlimited
+	IsActive           bool    `json:"is_active" gorm:"default:true"`
+}
+
+// TableName specifies the table name for MembershipPlan model
+func (MembershipPlan) TableName() string {
+	return "membership_plans"
+}
+
+// MembershipStatus defines the status of a membership
+type MembershipStatus string
+
+const (
+	MembershipPending   MembershipStatus = "pending"   // Waiting for the first payment
+	MembershipActive    MembershipStatus = "active"    // Paid through EndDate
+	MembershipSuspended MembershipStatus = "suspended" // Renewal failed or unpaid; no booking rights
+	MembershipExpired   MembershipStatus = "expired"   // Ended without auto-renew
+	MembershipCancelled MembershipStatus = "cancelled" // First payment failed
+)
+
+// Membership is a user's subscription to a plan. Each paid month extends EndDate.
+type Membership struct {
+	gorm.Model
+	UserID    uint             `json:"user_id" gorm:"not null;index"`
+	PlanID    uint             `json:"plan_id" gorm:"not null"`
+	Price     float64          `json:"price" gorm:"not null"` // Monthly price locked in at signup
+	Status    MembershipStatus `json:"status" gorm:"default:'pending';index"`
+	AutoRenew bool             `json:"auto_renew" gorm:"default:true"`
+	StartDate *time.Time       `json:"start_date,omitempty"`
+	EndDate   *time.Time       `json:"end_date,omitempty"` // End of the current paid period
+
+	// Relations
+	User     *User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Plan     *MembershipPlan `json:"plan,omitempty" gorm:"foreignKey:PlanID"`
+	Payments []Payment       `json:"payments,omitempty" gorm:"foreignKey:MembershipID"`
+}
+
+// TableName specifies the table name for Membership model
+func (Membership) TableName() string {
+	return "memberships"
+}
+
+// Covers checks if the membership gives booking rights at the given time
+func (m *Membership) Covers(at time.Time) bool {
+	return m.Status == MembershipActive && m.StartDate != nil && m.EndDate != nil &&
+		!at.Before(*m.StartDate) && at.Before(*m.EndDate)
+}
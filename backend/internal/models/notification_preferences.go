@@ -0,0 +1,42 @@
+package models
+
+// NotificationChannel is a channel over which users are notified
+type NotificationChannel string
+
+const (
+	ChannelEmail    NotificationChannel = "email"
+	ChannelSMS      NotificationChannel = "sms"
+	ChannelWhatsApp NotificationChannel = "whatsapp"
+)
+
+// IsValid checks if channel is one of the known channels
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case ChannelEmail, ChannelSMS, ChannelWhatsApp:
+		return true
+	}
+	return false
+}
+
+// NotificationPreferences are the channels over which a user wants to receive
+// reservation and payment notifications. SMS and WhatsApp are sent to the user's phone.
+type NotificationPreferences struct {
+	NotifyEmail    bool `json:"notify_email" gorm:"default:true"`
+	NotifySMS      bool `json:"notify_sms" gorm:"default:false"`
+	NotifyWhatsApp bool `json:"notify_whatsapp" gorm:"default:false"`
+}
+
+// Channels returns the enabled channels
+func (p NotificationPreferences) Channels() []NotificationChannel {
+	var channels []NotificationChannel
+	if p.NotifyEmail {
+		channels = append(channels, ChannelEmail)
+	}
+	if p.NotifySMS {
+		channels = append(channels, ChannelSMS)
+	}
+	if p.NotifyWhatsApp {
+		channels = append(channels, ChannelWhatsApp)
+	}
+	return channels
+}
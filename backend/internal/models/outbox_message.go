@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxStatus defines the delivery status of an outgoing message
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending" // Waiting for (another) delivery attempt
+	OutboxSent    OutboxStatus = "sent"
+	OutboxFailed  OutboxStatus = "failed" // Gave up after the maximum number of attempts
+)
+
+// OutboxMessage is a notification queued for delivery over a channel. Messages are
+// stored before they are sent so that failed sends are retried and none are lost on
+// restart.
+type OutboxMessage struct {
+	gorm.Model
+	UserID        *uint               `json:"user_id,omitempty" gorm:"index"`
+	Event         string              `json:"event" gorm:"not null"` // Template the message was rendered from
+	Channel       NotificationChannel `json:"channel" gorm:"default:'email';not null;index"`
+	Recipient     string              `json:"recipient" gorm:"not null"` // Email address or phone number
+	Subject       string              `json:"subject"`                   // Email only
+	Body          string              `json:"body" gorm:"type:text"`
+	DedupeKey     *string             `json:"dedupe_key,omitempty" gorm:"uniqueIndex"` // Prevents queueing the same message twice
+	Status        OutboxStatus        `json:"status" gorm:"default:'pending';index"`
+	Attempts      int                 `json:"attempts" gorm:"default:0"`
+	NextAttemptAt time.Time           `json:"next_attempt_at" gorm:"index"`
+	LastError     string              `json:"last_error,omitempty"`
+	SentAt        *time.Time          `json:"sent_at,omitempty"`
+}
+
+// TableName specifies the table name for OutboxMessage model
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+// IsFailed checks if delivery was given up
+func (m *OutboxMessage) IsFailed() bool {
+	return m.Status == OutboxFailed
+}
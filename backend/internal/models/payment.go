@@ -10,20 +10,27 @@ import (
 type PaymentStatus string
 
 const (
-	PaymentPending PaymentStatus = "pending"
-	PaymentPaid    PaymentStatus = "paid"
-	PaymentFailed  PaymentStatus = "failed"
-	PaymentExpired PaymentStatus = "expired"
+	PaymentPending           PaymentStatus = "pending"
+	PaymentPaid              PaymentStatus = "paid"
+	PaymentFailed            PaymentStatus = "failed"
+	PaymentExpired           PaymentStatus = "expired"
+	PaymentRefunded          PaymentStatus = "refunded"
+	PaymentPartiallyRefunded PaymentStatus = "partially_refunded"
 )
 
-// Payment represents a payment transaction
+// Payment represents a payment transaction for a reservation, a credit package purchase
+// or a month of membership
 type Payment struct {
 	gorm.Model
-	ReservationID uint          `json:"reservation_id" gorm:"not null;uniqueIndex"`
-	Amount        float64       `json:"amount" gorm:"not null"`
-	Status        PaymentStatus `json:"status" gorm:"default:'pending'"`
-	PaymentMethod string        `json:"payment_method,omitempty"`
-	TransactionID string        `json:"transaction_id" gorm:"uniqueIndex"`
+	UserID           uint           `json:"user_id" gorm:"index"`
+	ReservationID    *uint          `json:"reservation_id,omitempty" gorm:"uniqueIndex"`
+	CreditPurchaseID *uint          `json:"credit_purchase_id,omitempty" gorm:"uniqueIndex"`
+	MembershipID     *uint          `json:"membership_id,omitempty" gorm:"index"` // One payment per billed month
+	Amount           float64        `json:"amount" gorm:"not null"`
+	Breakdown        PriceBreakdown `json:"price_breakdown,omitempty" gorm:"type:text"` // Pricing rules applied to Amount
+	Status           PaymentStatus  `json:"status" gorm:"default:'pending'"`
+	PaymentMethod    string         `json:"payment_method,omitempty"`
+	TransactionID    string         `json:"transaction_id" gorm:"uniqueIndex"`
 
 	// Midtrans specific fields
 	MidtransToken string `json:"midtrans_token,omitempty"`
@@ -33,8 +40,11 @@ type Payment struct {
 	PaidAt    *time.Time `json:"paid_at,omitempty"`
 	ExpiredAt *time.Time `json:"expired_at,omitempty"`
 
-	// Relation
-	Reservation Reservation `json:"reservation,omitempty" gorm:"foreignKey:ReservationID"`
+	// Relations
+	Reservation    *Reservation    `json:"reservation,omitempty" gorm:"foreignKey:ReservationID"`
+	CreditPurchase *CreditPurchase `json:"credit_purchase,omitempty" gorm:"foreignKey:CreditPurchaseID"`
+	Membership     *Membership     `json:"membership,omitempty" gorm:"foreignKey:MembershipID"`
+	Refunds        []Refund        `json:"refunds,omitempty" gorm:"foreignKey:PaymentID"`
 }
 
 // TableName specifies the table name for Payment model
@@ -42,11 +52,25 @@ func (Payment) TableName() string {
 	return "payments"
 }
 
+// BelongsTo checks if payment was made by the given user
+func (p *Payment) BelongsTo(userID uint) bool {
+	if p.UserID != 0 {
+		return p.UserID == userID
+	}
+	// Payments created before UserID was recorded are owned through their reservation
+	return p.Reservation != nil && p.Reservation.UserID == userID
+}
+
 // IsPaid checks if payment is completed
 func (p *Payment) IsPaid() bool {
 	return p.Status == PaymentPaid
 }
 
+// IsRefunded checks if payment has been fully or partially refunded
+func (p *Payment) IsRefunded() bool {
+	return p.Status == PaymentRefunded || p.Status == PaymentPartiallyRefunded
+}
+
 // IsPending checks if payment is pending
 func (p *Payment) IsPending() bool {
 	return p.Status == PaymentPending
@@ -66,4 +90,4 @@ func (p *Payment) IsExpired() bool {
 		return true
 	}
 	return false
-}
\ No newline at end of file
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// NotificationOutcome describes what happened to a payment notification
+type NotificationOutcome string
+
+const (
+	NotificationApplied          NotificationOutcome = "applied"
+	NotificationIgnored          NotificationOutcome = "ignored"
+	NotificationInvalidSignature NotificationOutcome = "invalid_signature"
+	NotificationAmountMismatch   NotificationOutcome = "amount_mismatch"
+	NotificationUnknownOrder     NotificationOutcome = "unknown_order"
+	NotificationRefunded         NotificationOutcome = "refunded" // Paid after the order was given up, refunded
+)
+
+// PaymentNotification is an audit record of a raw notification received from the payment gateway
+type PaymentNotification struct {
+	gorm.Model
+	PaymentID         *uint               `json:"payment_id,omitempty" gorm:"index"`
+	OrderID           string              `json:"order_id" gorm:"index"`
+	TransactionStatus string              `json:"transaction_status"`
+	StatusCode        string              `json:"status_code"`
+	GrossAmount       string              `json:"gross_amount"`
+	SignatureValid    bool                `json:"signature_valid"`
+	Outcome           NotificationOutcome `json:"outcome"`
+	RawBody           string              `json:"raw_body" gorm:"type:text"`
+}
+
+// TableName specifies the table name for PaymentNotification model
+func (PaymentNotification) TableName() string {
+	return "payment_notifications"
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PricingAction defines how a pricing rule changes the price
+type PricingAction string
+
+const (
+	PricingSet     PricingAction = "set"     // Replace the price with Value
+	PricingPercent PricingAction = "percent" // Add Value percent of the current price (negative for discounts)
+	PricingAmount  PricingAction = "amount"  // Add Value to the current price (negative for discounts)
+)
+
+// IsValid checks if action is one of the known actions
+func (a PricingAction) IsValid() bool {
+	switch a {
+	case PricingSet, PricingPercent, PricingAmount:
+		return true
+	}
+	return false
+}
+
+// PricingRule adjusts the session price for classes matching all of its conditions.
+// Empty conditions match every class. Matching rules are applied in ascending priority.
+type PricingRule struct {
+	gorm.Model
+	Name       string        `json:"name" gorm:"not null"`
+	CourtID    *uint         `json:"court_id,omitempty" gorm:"index"`
+	TimeslotID *uint         `json:"timeslot_id,omitempty" gorm:"index"`
+	Weekday    *int          `json:"weekday,omitempty"`    // 0 = Sunday ... 6 = Saturday
+	StartDate  *time.Time    `json:"start_date,omitempty"` // Inclusive
+	EndDate    *time.Time    `json:"end_date,omitempty"`   // Inclusive
+	Action     PricingAction `json:"action" gorm:"not null"`
+	Value      float64       `json:"value" gorm:"not null"`
+	Priority   int           `json:"priority" gorm:"default:0"`
+	IsActive   bool          `json:"is_active" gorm:"default:true"`
+}
+
+// TableName specifies the table name for PricingRule model
+func (PricingRule) TableName() string {
+	return "pricing_rules"
+}
+
+// Matches checks if the rule applies to a class of the given court and timeslot on a date
+func (r *PricingRule) Matches(courtID, timeslotID uint, date time.Time) bool {
+	if r.CourtID != nil && *r.CourtID != courtID {
+		return false
+	}
+	if r.TimeslotID != nil && *r.TimeslotID != timeslotID {
+		return false
+	}
+	if r.Weekday != nil && *r.Weekday != int(date.Weekday()) {
+		return false
+	}
+
+	day := date.Format("2006-01-02")
+	if r.StartDate != nil && day < r.StartDate.Format("2006-01-02") {
+		return false
+	}
+	if r.EndDate != nil && day > r.EndDate.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// Apply returns the price after applying the rule to the given price
+func (r *PricingRule) Apply(price float64) float64 {
+	switch r.Action {
+	case PricingSet:
+		return r.Value
+	case PricingPercent:
+		return price + price*r.Value/100
+	case PricingAmount:
+		return price + r.Value
+	}
+	return price
+}
+
+// PriceComponent is one step of a price calculation
+type PriceComponent struct {
+	RuleID uint          `json:"rule_id,omitempty"`
+	Name   string        `json:"name"`
+	Action PricingAction `json:"action,omitempty"`
+	Value  float64       `json:"value"`
+	Price  float64       `json:"price"` // Price after this step
+}
+
+// PriceBreakdown lists the steps of a price calculation, stored as JSON
+type PriceBreakdown []PriceComponent
+
+// Value implements driver.Valuer
+func (b PriceBreakdown) Value() (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (b *PriceBreakdown) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*b = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	case []byte:
+		return json.Unmarshal(v, b)
+	}
+	return errors.New("invalid price breakdown value")
+}
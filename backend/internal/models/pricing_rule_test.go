@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPricingRuleMatches(t *testing.T) {
+	id := func(v uint) *uint { return &v }
+	weekday := func(d time.Weekday) *int { v := int(d); return &v }
+	day := func(d int) *time.Time { v := time.Date(2030, 1, d, 0, 0, 0, 0, time.UTC); return &v }
+
+	// Monday 7 January 2030, stored as midnight UTC
+	date := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		rule PricingRule
+		want bool
+	}{
+		{"no conditions", PricingRule{}, true},
+		{"same court", PricingRule{CourtID: id(1)}, true},
+		{"other court", PricingRule{CourtID: id(2)}, false},
+		{"same timeslot", PricingRule{TimeslotID: id(6)}, true},
+		{"other timeslot", PricingRule{TimeslotID: id(7)}, false},
+		{"same weekday", PricingRule{Weekday: weekday(time.Monday)}, true},
+		{"other weekday", PricingRule{Weekday: weekday(time.Sunday)}, false},
+		{"starts that day", PricingRule{StartDate: day(7)}, true},
+		{"starts the day after", PricingRule{StartDate: day(8)}, false},
+		{"ends that day", PricingRule{EndDate: day(7)}, true},
+		{"ended the day before", PricingRule{EndDate: day(6)}, false},
+		{"within range", PricingRule{StartDate: day(1), EndDate: day(31)}, true},
+		{"all conditions", PricingRule{CourtID: id(1), TimeslotID: id(6), Weekday: weekday(time.Monday), StartDate: day(7), EndDate: day(7)}, true},
+		{"all but court", PricingRule{CourtID: id(2), TimeslotID: id(6), Weekday: weekday(time.Monday), StartDate: day(7), EndDate: day(7)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(1, 6, date); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPricingRuleMatchesEndDateLateInDay(t *testing.T) {
+	// An end date saved with a time of day still covers the whole day
+	end := time.Date(2030, 1, 7, 23, 0, 0, 0, time.UTC)
+	rule := PricingRule{EndDate: &end}
+	if !rule.Matches(1, 6, time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Error("rule ending later that day does not match")
+	}
+}
+
+func TestPricingRuleApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		action PricingAction
+		value  float64
+		price  float64
+		want   float64
+	}{
+		{"set", PricingSet, 80000, 100000, 80000},
+		{"percent surcharge", PricingPercent, 20, 100000, 120000},
+		{"percent discount", PricingPercent, -15, 100000, 85000},
+		{"percent of an odd price", PricingPercent, -10, 99999, 89999.1},
+		{"amount surcharge", PricingAmount, 25000, 100000, 125000},
+		{"amount discount", PricingAmount, -25000, 100000, 75000},
+		{"discount below zero", PricingAmount, -150000, 100000, -50000},
+		{"unknown action", PricingAction("double"), 2, 100000, 100000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := PricingRule{Action: tt.action, Value: tt.value}
+			if got := rule.Apply(tt.price); got != tt.want {
+				t.Errorf("Apply(%v) = %v, want %v", tt.price, got, tt.want)
+			}
+		})
+	}
+}
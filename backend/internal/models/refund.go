@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefundStatus defines the status of a refund
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "pending"
+	RefundSucceeded RefundStatus = "succeeded"
+	RefundFailed    RefundStatus = "failed"
+)
+
+// Refund represents money returned for a paid reservation
+type Refund struct {
+	gorm.Model
+	PaymentID     uint         `json:"payment_id" gorm:"not null;index"`
+	Amount        float64      `json:"amount" gorm:"not null"`
+	Percent       int          `json:"percent"` // Share of the payment amount refunded by the policy
+	Reason        string       `json:"reason"`
+	Status        RefundStatus `json:"status" gorm:"default:'pending'"`
+	RefundKey     string       `json:"refund_key" gorm:"uniqueIndex"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	RefundedAt    *time.Time   `json:"refunded_at,omitempty"`
+
+	// Relation
+	Payment *Payment `json:"payment,omitempty" gorm:"foreignKey:PaymentID"`
+}
+
+// TableName specifies the table name for Refund model
+func (Refund) TableName() string {
+	return "refunds"
+}
+
+// IsFailed checks if refund has failed
+func (r *Refund) IsFailed() bool {
+	return r.Status == RefundFailed
+}
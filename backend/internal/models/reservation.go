@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,24 +14,43 @@ const (
 	StatusPending   ReservationStatus = "pending"
 	StatusConfirmed ReservationStatus = "confirmed"
 	StatusCancelled ReservationStatus = "cancelled"
-	StatusCompleted ReservationStatus = "completed"
+	StatusCompleted ReservationStatus = "completed" // Attended: checked in and the class ended
+	StatusNoShow    ReservationStatus = "no_show"   // The class ended without a check-in
 )
 
 // Reservation represents a booking made by a user
 type Reservation struct {
 	gorm.Model
 	UserID     uint              `json:"user_id" gorm:"not null"`
+	SessionID  *uint             `json:"session_id,omitempty" gorm:"index"`
 	CourtID    uint              `json:"court_id" gorm:"not null"`
 	TimeslotID uint              `json:"timeslot_id" gorm:"not null"`
 	Date       time.Time         `json:"date" gorm:"not null;index"`
 	Status     ReservationStatus `json:"status" gorm:"default:'pending'"`
 	Notes      string            `json:"notes,omitempty"`
 
+	// PaidWithCredit is set when the booking was paid with a package credit instead of a payment
+	PaidWithCredit bool `json:"paid_with_credit" gorm:"default:false"`
+
+	// MembershipID is set when the booking is covered by a membership instead of a payment
+	MembershipID *uint `json:"membership_id,omitempty" gorm:"index"`
+
+	// HoldUntil overrides the default hold window of a pending reservation, e.g. the
+	// claim window of a seat offered from the waitlist
+	HoldUntil *time.Time `json:"hold_until,omitempty"`
+
+	// LateCancel is set when the booking was cancelled after the cancellation deadline
+	LateCancel bool `json:"late_cancel" gorm:"default:false"`
+
+	// CheckedInAt is set when the attendee checked in at the desk
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+
 	// Relations
-	User     User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Court    Court    `json:"court,omitempty" gorm:"foreignKey:CourtID"`
-	Timeslot Timeslot `json:"timeslot,omitempty" gorm:"foreignKey:TimeslotID"`
-	Payment  *Payment `json:"payment,omitempty" gorm:"foreignKey:ReservationID"`
+	User     User          `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Court    Court         `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Timeslot Timeslot      `json:"timeslot,omitempty" gorm:"foreignKey:TimeslotID"`
+	Session  *ClassSession `json:"session,omitempty" gorm:"foreignKey:SessionID"`
+	Payment  *Payment      `json:"payment,omitempty" gorm:"foreignKey:ReservationID"`
 }
 
 // TableName specifies the table name for Reservation model
@@ -55,6 +75,59 @@ func (r *Reservation) IsCancelled() bool {
 
 // CanBeCancelled checks if reservation can be cancelled
 func (r *Reservation) CanBeCancelled() bool {
-	// Can only cancel if not already cancelled or completed
-	return r.Status != StatusCancelled && r.Status != StatusCompleted
-}
\ No newline at end of file
+	// Can only cancel if not already cancelled, attended or missed
+	return r.Status != StatusCancelled && r.Status != StatusCompleted &&
+		r.Status != StatusNoShow && r.CheckedInAt == nil
+}
+
+// HasTicket checks if the reservation has a ticket to show at the desk
+func (r *Reservation) HasTicket() bool {
+	return r.Status == StatusConfirmed || r.Status == StatusCompleted
+}
+
+// IsCheckedIn checks if the attendee checked in for the class
+func (r *Reservation) IsCheckedIn() bool {
+	return r.CheckedInAt != nil
+}
+
+// BookingPolicy returns the defaults overridden by the booking rules of the court and
+// of the session. Court and Session must be loaded.
+func (r *Reservation) BookingPolicy(defaults BookingPolicy) BookingPolicy {
+	defaults = defaults.With(r.Court.BookingRules)
+	if r.Session != nil {
+		defaults = defaults.With(r.Session.BookingRules)
+	}
+	return defaults
+}
+
+// HoldDeadline returns when a pending reservation releases its seat unless paid
+func (r *Reservation) HoldDeadline(holdExpiry time.Duration) time.Time {
+	if r.HoldUntil != nil {
+		return *r.HoldUntil
+	}
+	return r.CreatedAt.Add(holdExpiry)
+}
+
+// StartTime returns the class start, combining the reservation date with the
+// timeslot time in the given location. Timeslot must be loaded.
+func (r *Reservation) StartTime(loc *time.Location) (time.Time, error) {
+	return classStart(r.Date, &r.Timeslot, loc)
+}
+
+// EndTime returns the class end in the given location. Timeslot must be loaded.
+func (r *Reservation) EndTime(loc *time.Location) (time.Time, error) {
+	start, err := r.StartTime(loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.Add(time.Duration(r.Timeslot.Duration) * time.Minute), nil
+}
+
+// classStart combines a class date with the time of its timeslot
+func classStart(date time.Time, timeslot *Timeslot, loc *time.Location) (time.Time, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(timeslot.Time, "%d:%d", &hour, &minute); err != nil {
+		return time.Time{}, fmt.Errorf("invalid timeslot time %q", timeslot.Time)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc), nil
+}
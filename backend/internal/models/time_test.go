@@ -0,0 +1,142 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestStartTime(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name    string
+		date    time.Time // Midnight UTC, as stored
+		at      string
+		loc     *time.Location
+		want    time.Time
+		wantErr bool
+	}{
+		{"Jakarta morning", time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), "07:00", jakarta, time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), false},
+		{"Jakarta just after midnight", time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), "00:30", jakarta, time.Date(2030, 1, 6, 17, 30, 0, 0, time.UTC), false},
+		{"Jakarta late evening", time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), "23:45", jakarta, time.Date(2030, 1, 7, 16, 45, 0, 0, time.UTC), false},
+		{"New York before DST", time.Date(2030, 3, 9, 0, 0, 0, 0, time.UTC), "07:00", newYork, time.Date(2030, 3, 9, 12, 0, 0, 0, time.UTC), false},
+		{"New York on spring forward day", time.Date(2030, 3, 10, 0, 0, 0, 0, time.UTC), "07:00", newYork, time.Date(2030, 3, 10, 11, 0, 0, 0, time.UTC), false},
+		{"New York on fall back day", time.Date(2030, 11, 3, 0, 0, 0, 0, time.UTC), "07:00", newYork, time.Date(2030, 11, 3, 12, 0, 0, 0, time.UTC), false},
+		{"invalid time", time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC), "7am", jakarta, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeslot := Timeslot{Time: tt.at, Duration: 60}
+			reservation := &Reservation{Date: tt.date, Timeslot: timeslot}
+			session := &ClassSession{Date: tt.date, Timeslot: &timeslot}
+
+			for kind, startTime := range map[string]func(*time.Location) (time.Time, error){
+				"reservation": reservation.StartTime,
+				"session":     session.StartTime,
+			} {
+				got, err := startTime(tt.loc)
+				if tt.wantErr {
+					if err == nil {
+						t.Errorf("%s start %s, want an error", kind, got)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", kind, err)
+				}
+				if !got.Equal(tt.want) {
+					t.Errorf("%s starts at %s, want %s", kind, got.UTC(), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionStartTimeNeedsTimeslot(t *testing.T) {
+	session := &ClassSession{Date: time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)}
+	if _, err := session.StartTime(time.UTC); err == nil {
+		t.Error("start time without a timeslot")
+	}
+}
+
+func TestBookingPolicyCutoffs(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	policy := BookingPolicy{MinLead: 2 * time.Hour, MaxAdvanceDays: 7, CancelDeadline: 24 * time.Hour}
+
+	tests := []struct {
+		name        string
+		classStart  time.Time
+		opensAt     time.Time
+		closesAt    time.Time
+		cancelUntil time.Time
+	}{
+		{
+			name:        "no DST change",
+			classStart:  time.Date(2030, 1, 14, 7, 0, 0, 0, newYork),
+			opensAt:     time.Date(2030, 1, 7, 7, 0, 0, 0, newYork),
+			closesAt:    time.Date(2030, 1, 14, 5, 0, 0, 0, newYork),
+			cancelUntil: time.Date(2030, 1, 13, 7, 0, 0, 0, newYork),
+		},
+		{
+			// Clocks go forward at 02:00 on the class day. Days are calendar days, so
+			// booking opens at the same wall clock time, 167 hours before. The cancel
+			// deadline is 24 hours, so an hour earlier on the clock.
+			name:        "across spring forward",
+			classStart:  time.Date(2030, 3, 10, 7, 0, 0, 0, newYork),
+			opensAt:     time.Date(2030, 3, 3, 7, 0, 0, 0, newYork),
+			closesAt:    time.Date(2030, 3, 10, 5, 0, 0, 0, newYork),
+			cancelUntil: time.Date(2030, 3, 9, 6, 0, 0, 0, newYork),
+		},
+		{
+			name:        "across fall back",
+			classStart:  time.Date(2030, 11, 3, 7, 0, 0, 0, newYork),
+			opensAt:     time.Date(2030, 10, 27, 7, 0, 0, 0, newYork),
+			closesAt:    time.Date(2030, 11, 3, 5, 0, 0, 0, newYork),
+			cancelUntil: time.Date(2030, 11, 2, 8, 0, 0, 0, newYork),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.BookingOpensAt(tt.classStart); !got.Equal(tt.opensAt) {
+				t.Errorf("booking opens at %s, want %s", got, tt.opensAt)
+			}
+			if got := policy.BookingClosesAt(tt.classStart); !got.Equal(tt.closesAt) {
+				t.Errorf("booking closes at %s, want %s", got, tt.closesAt)
+			}
+			if got := policy.CancelDeadlineAt(tt.classStart); !got.Equal(tt.cancelUntil) {
+				t.Errorf("cancel deadline at %s, want %s", got, tt.cancelUntil)
+			}
+		})
+	}
+}
+
+func TestBookingPolicyOverrides(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	defaults := BookingPolicy{MinLead: time.Hour, MaxAdvanceDays: 30, CancelDeadline: 24 * time.Hour, AllowLateCancel: true}
+
+	reservation := &Reservation{
+		Court:   Court{BookingRules: BookingRules{MinLeadMinutes: intPtr(0), CancelDeadlineHours: intPtr(12)}},
+		Session: &ClassSession{BookingRules: BookingRules{CancelDeadlineHours: intPtr(6), AllowLateCancel: boolPtr(false)}},
+	}
+	want := BookingPolicy{MinLead: 0, MaxAdvanceDays: 30, CancelDeadline: 6 * time.Hour, AllowLateCancel: false}
+	if got := reservation.BookingPolicy(defaults); got != want {
+		t.Errorf("reservation policy = %+v, want %+v", got, want)
+	}
+
+	session := &ClassSession{Court: &reservation.Court}
+	want = BookingPolicy{MinLead: 0, MaxAdvanceDays: 30, CancelDeadline: 12 * time.Hour, AllowLateCancel: true}
+	if got := session.BookingPolicy(defaults); got != want {
+		t.Errorf("session policy = %+v, want %+v", got, want)
+	}
+}
@@ -7,7 +7,7 @@ import (
 // Timeslot represents a time slot for reservations
 type Timeslot struct {
 	gorm.Model
-	Time         string        `json:"time" gorm:"not null"` // Format: "HH:MM"
+	Time         string        `json:"time" gorm:"not null"`     // Format: "HH:MM"
 	Duration     int           `json:"duration" gorm:"not null"` // Duration in minutes
 	IsActive     bool          `json:"is_active" gorm:"default:true"`
 	Reservations []Reservation `json:"reservations,omitempty" gorm:"foreignKey:TimeslotID"`
@@ -16,4 +16,4 @@ type Timeslot struct {
 // TableName specifies the table name for Timeslot model
 func (Timeslot) TableName() string {
 	return "timeslots"
-}
\ No newline at end of file
+}
@@ -1,9 +1,30 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// UserRole defines the role of a user
+type UserRole string
+
+const (
+	RoleMember     UserRole = "member"
+	RoleInstructor UserRole = "instructor"
+	RoleFrontDesk  UserRole = "front_desk"
+	RoleAdmin      UserRole = "admin"
+)
+
+// IsValid checks if role is one of the known roles
+func (r UserRole) IsValid() bool {
+	switch r {
+	case RoleMember, RoleInstructor, RoleFrontDesk, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 // User represents a user in the system
 type User struct {
 	gorm.Model
@@ -11,8 +32,19 @@ type User struct {
 	Email        string        `json:"email" gorm:"uniqueIndex;not null"`
 	Password     string        `json:"-" gorm:"not null"` // Never expose password in JSON
 	Phone        string        `json:"phone"`
+	Role         UserRole      `json:"role" gorm:"default:'member';not null"`
 	IsActive     bool          `json:"is_active" gorm:"default:true"`
 	Reservations []Reservation `json:"reservations,omitempty" gorm:"foreignKey:UserID"`
+
+	// EmailVerifiedAt is set when the user confirmed their email address
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+
+	// AnonymizedAt is set when the user deleted their account. Their personal data is
+	// erased, but the account stays for the reservations and payments referring to it.
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
+
+	// Channels for reservation and payment notifications
+	NotificationPreferences `gorm:"embedded"`
 }
 
 // TableName specifies the table name for User model
@@ -22,7 +54,34 @@ func (User) TableName() string {
 
 // BeforeCreate is a GORM hook that runs before creating a user
 func (u *User) BeforeCreate(tx *gorm.DB) error {
-	// Any pre-creation logic can go here
-	// For example, email validation, default values, etc.
+	// New accounts are members unless a role was set explicitly
+	if u.Role == "" {
+		u.Role = RoleMember
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// IsEmailVerified checks if the user confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsAnonymized checks if the user deleted their account
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedAt != nil
+}
+
+// IsAdmin checks if user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// HasRole checks if user has any of the given roles
+func (u *User) HasRole(roles ...UserRole) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
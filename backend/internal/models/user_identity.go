@@ -0,0 +1,21 @@
+package models
+
+import "gorm.io/gorm"
+
+// UserIdentity links a user to their account at an OpenID Connect provider, so they
+// can sign in with it
+type UserIdentity struct {
+	gorm.Model
+	UserID   uint   `json:"user_id" gorm:"not null;index"`
+	Provider string `json:"provider" gorm:"not null;uniqueIndex:idx_user_identities_subject"`
+	Subject  string `json:"-" gorm:"not null;uniqueIndex:idx_user_identities_subject"` // User ID at the provider
+	Email    string `json:"email"`                                                     // Email at the provider when linked
+
+	// Relation
+	User *User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for UserIdentity model
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
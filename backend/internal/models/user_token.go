@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserTokenPurpose defines what a user token is for
+type UserTokenPurpose string
+
+const (
+	TokenPasswordReset     UserTokenPurpose = "password_reset"
+	TokenEmailVerification UserTokenPurpose = "email_verification"
+)
+
+// UserToken is a single-use, expiring token emailed to a user to prove they own the
+// email address, e.g. to reset their password. Only a hash of the token is stored.
+type UserToken struct {
+	gorm.Model
+	UserID    uint             `gorm:"not null;index"`
+	Purpose   UserTokenPurpose `gorm:"not null;index"`
+	Email     string           `gorm:"not null"` // Address the token was sent to
+	TokenHash string           `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time        `gorm:"not null"`
+	UsedAt    *time.Time       // Set when used, or when replaced by a newer token
+
+	// Relation
+	User *User `gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for UserToken model
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// IsUsable checks if the token is unused and not expired
+func (t *UserToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistStatus defines the status of a waitlist entry
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting WaitlistStatus = "waiting" // In line for a seat
+	WaitlistOffered WaitlistStatus = "offered" // Seat held in a pending reservation until the claim window ends
+	WaitlistClaimed WaitlistStatus = "claimed" // Offered reservation was paid
+	WaitlistExpired WaitlistStatus = "expired" // Offer not claimed in time, or class already started
+	WaitlistLeft    WaitlistStatus = "left"    // User left the waitlist
+)
+
+// WaitlistEntry is a user waiting for a seat in a full class. Entries of a class
+// are served first come, first served.
+type WaitlistEntry struct {
+	gorm.Model
+	UserID         uint           `json:"user_id" gorm:"not null;index"`
+	CourtID        uint           `json:"court_id" gorm:"not null;index:idx_waitlist_class"`
+	TimeslotID     uint           `json:"timeslot_id" gorm:"not null;index:idx_waitlist_class"`
+	Date           time.Time      `json:"date" gorm:"not null;index:idx_waitlist_class"`
+	Status         WaitlistStatus `json:"status" gorm:"default:'waiting';index"`
+	ReservationID  *uint          `json:"reservation_id,omitempty" gorm:"index"` // Pending reservation created on promotion
+	OfferedAt      *time.Time     `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time     `json:"offer_expires_at,omitempty"`
+
+	// Position in line among waiting entries of the class, filled in when listing
+	Position int `json:"position,omitempty" gorm:"-"`
+
+	// Relations
+	Court       *Court       `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Timeslot    *Timeslot    `json:"timeslot,omitempty" gorm:"foreignKey:TimeslotID"`
+	Reservation *Reservation `json:"reservation,omitempty" gorm:"foreignKey:ReservationID"`
+}
+
+// TableName specifies the table name for WaitlistEntry model
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
+
+// IsOpen checks if the entry is still waiting or holding an offer
+func (e *WaitlistEntry) IsOpen() bool {
+	return e.Status == WaitlistWaiting || e.Status == WaitlistOffered
+}
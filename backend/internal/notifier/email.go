@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"reservation-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailNotifier sends plain text emails through an SMTP server. STARTTLS is used
+// when the server offers it; authentication only when a username is configured.
+type EmailNotifier struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// NewEmailNotifier creates a new SMTP email notifier
+func NewEmailNotifier(host string, port int, username, password, from string) *EmailNotifier {
+	return &EmailNotifier{
+		addr:     fmt.Sprintf("%s:%d", host, port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Name returns the notifier identifier
+func (m *EmailNotifier) Name() string {
+	return "smtp"
+}
+
+// Channel returns the channel the notifier delivers over
+func (m *EmailNotifier) Channel() models.NotificationChannel {
+	return models.ChannelEmail
+}
+
+// Send delivers a message to the SMTP server
+func (m *EmailNotifier) Send(msg Message) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %v", m.from, err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %v", msg.To, err)
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	return smtp.SendMail(m.addr, auth, from.Address, []string{to.Address}, m.build(from, to, msg))
+}
+
+// build formats a message with its headers
+func (m *EmailNotifier) build(from, to *mail.Address, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.New().String(), m.host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes()
+}
@@ -0,0 +1,167 @@
+package notifier
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpServer is a minimal in-process SMTP server recording what it receives
+type smtpServer struct {
+	listener net.Listener
+	rejectTo string // Recipient refused with 550
+
+	mu   sync.Mutex
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve(c net.Conn) {
+	defer c.Close()
+	conn := textproto.NewConn(c)
+	conn.PrintfLine("220 localhost ESMTP test")
+
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		s.mu.Lock()
+		switch {
+		case verb == "EHLO":
+			conn.PrintfLine("250-localhost")
+			conn.PrintfLine("250-8BITMIME")
+			conn.PrintfLine("250 AUTH PLAIN")
+		case verb == "AUTH":
+			s.auth = strings.TrimPrefix(line, "AUTH PLAIN ")
+			conn.PrintfLine("235 2.7.0 Authentication successful")
+		case verb == "MAIL":
+			s.from = line
+			conn.PrintfLine("250 OK")
+		case verb == "RCPT":
+			if s.rejectTo != "" && strings.Contains(line, s.rejectTo) {
+				conn.PrintfLine("550 5.1.1 No such user")
+			} else {
+				s.to = append(s.to, line)
+				conn.PrintfLine("250 OK")
+			}
+		case verb == "DATA":
+			conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				s.mu.Unlock()
+				return
+			}
+			s.data = string(data)
+			conn.PrintfLine("250 OK")
+		case verb == "QUIT":
+			conn.PrintfLine("221 Bye")
+			s.mu.Unlock()
+			return
+		default:
+			conn.PrintfLine("250 OK")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestEmailNotifierSend(t *testing.T) {
+	server := newSMTPServer(t)
+	n := NewEmailNotifier("127.0.0.1", server.port(), "studio", "secret", "Pilates Studio <noreply@example.com>")
+
+	err := n.Send(Message{To: "member@example.com", Subject: "Booking confirmed ✓", Body: "Hi Member,\n\nSee you in class!\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if want := base64.StdEncoding.EncodeToString([]byte("\x00studio\x00secret")); server.auth != want {
+		t.Errorf("AUTH PLAIN %s, want %s", server.auth, want)
+	}
+	if !strings.HasPrefix(server.from, "MAIL FROM:<noreply@example.com>") {
+		t.Errorf("sender %q", server.from)
+	}
+	if len(server.to) != 1 || server.to[0] != "RCPT TO:<member@example.com>" {
+		t.Errorf("recipients %q", server.to)
+	}
+
+	headers, err := textproto.NewReader(bufio.NewReader(strings.NewReader(server.data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := headers.Get("From"); got != `"Pilates Studio" <noreply@example.com>` {
+		t.Errorf("From: %s", got)
+	}
+	if got := headers.Get("To"); got != "<member@example.com>" {
+		t.Errorf("To: %s", got)
+	}
+	if got := headers.Get("Subject"); got != "=?utf-8?q?Booking_confirmed_=E2=9C=93?=" {
+		t.Errorf("Subject: %s", got)
+	}
+	if got := headers.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type: %s", got)
+	}
+	if !strings.HasSuffix(server.data, "\n\nHi Member,\n\nSee you in class!\n") {
+		t.Errorf("message:\n%s", server.data)
+	}
+}
+
+func TestEmailNotifierSendFails(t *testing.T) {
+	server := newSMTPServer(t)
+	server.rejectTo = "gone@example.com"
+	n := NewEmailNotifier("127.0.0.1", server.port(), "", "", "noreply@example.com")
+
+	if err := n.Send(Message{To: "gone@example.com", Subject: "Hi", Body: "Hi"}); err == nil {
+		t.Error("rejected recipient reported as sent")
+	}
+	if err := n.Send(Message{To: "not an address", Subject: "Hi", Body: "Hi"}); err == nil {
+		t.Error("invalid recipient reported as sent")
+	}
+
+	// Nothing listens once the server is gone
+	server.listener.Close()
+	if err := n.Send(Message{To: "member@example.com", Subject: "Hi", Body: "Hi"}); err == nil {
+		t.Error("message reported as sent without a server")
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.auth != "" {
+		t.Error("authenticated without a username")
+	}
+}
@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"log"
+	"reservation-api/internal/models"
+	"sync"
+)
+
+// FakeNotifier is a notifier for development and tests. Messages never leave the
+// process: they are logged and recorded, and can be inspected with Sent.
+type FakeNotifier struct {
+	channel models.NotificationChannel
+
+	mu   sync.Mutex
+	sent []Message
+
+	// Err, when set, is returned by Send to simulate a failing provider
+	Err error
+}
+
+// NewFakeNotifier creates a new fake notifier for a channel
+func NewFakeNotifier(channel models.NotificationChannel) *FakeNotifier {
+	return &FakeNotifier{channel: channel}
+}
+
+// Name returns the notifier identifier
+func (n *FakeNotifier) Name() string {
+	return "fake"
+}
+
+// Channel returns the channel the notifier delivers over
+func (n *FakeNotifier) Channel() models.NotificationChannel {
+	return n.channel
+}
+
+// Send records and logs a message
+func (n *FakeNotifier) Send(msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.Err != nil {
+		return n.Err
+	}
+
+	n.sent = append(n.sent, msg)
+
+	summary := msg.Subject
+	if summary == "" {
+		summary = msg.Body
+	}
+	log.Printf("📨 [%s] to %s: %s", n.channel, msg.To, summary)
+	return nil
+}
+
+// Sent returns the messages sent so far
+func (n *FakeNotifier) Sent() []Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Message(nil), n.sent...)
+}
+
+// Reset forgets the messages sent so far
+func (n *FakeNotifier) Reset() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = nil
+}
@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+)
+
+// Message is a notification to one recipient: an email address for email, a phone
+// number in international format (+62...) for SMS and WhatsApp
+type Message struct {
+	To      string
+	Subject string // Email only
+	Body    string
+}
+
+// Notifier delivers messages over one channel
+type Notifier interface {
+	// Name returns the notifier identifier
+	Name() string
+
+	// Channel returns the channel the notifier delivers over
+	Channel() models.NotificationChannel
+
+	// Send delivers a message. An error means the message may be retried.
+	Send(msg Message) error
+}
+
+// Notifiers are the notifiers of each channel
+type Notifiers map[models.NotificationChannel]Notifier
+
+// New creates a notifier for every channel: the provider configured for it, or a
+// fake notifier that only logs messages when the channel is not configured
+func New(cfg *config.Config) Notifiers {
+	notifiers := Notifiers{}
+
+	if cfg.SMTPHost != "" {
+		notifiers.add(NewEmailNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom))
+	} else {
+		notifiers.add(NewFakeNotifier(models.ChannelEmail))
+	}
+
+	if cfg.TwilioAccountSID != "" && cfg.TwilioAuthToken != "" {
+		notifiers.add(NewSMSNotifier(cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.SMSFrom))
+	} else {
+		notifiers.add(NewFakeNotifier(models.ChannelSMS))
+	}
+
+	if cfg.WhatsAppToken != "" && cfg.WhatsAppPhoneNumberID != "" {
+		notifiers.add(NewWhatsAppNotifier(cfg.WhatsAppToken, cfg.WhatsAppPhoneNumberID,
+			cfg.WhatsAppTemplate, cfg.WhatsAppTemplateLanguage))
+	} else {
+		notifiers.add(NewFakeNotifier(models.ChannelWhatsApp))
+	}
+
+	return notifiers
+}
+
+// add registers a notifier for its channel
+func (n Notifiers) add(notifier Notifier) {
+	n[notifier.Channel()] = notifier
+}
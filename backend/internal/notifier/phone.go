@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone is returned for phone numbers that cannot be normalized
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// NormalizePhone converts a phone number to international format, e.g. +6281234567890.
+// Spaces, dashes, dots and parentheses are ignored. National numbers starting with 0
+// get the default country calling code (e.g. 62 for Indonesia).
+func NormalizePhone(phone, defaultCountryCode string) (string, error) {
+	phone = strings.TrimSpace(phone)
+
+	var digits strings.Builder
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = strings.TrimPrefix(number, "00")
+	case strings.HasPrefix(number, "0"):
+		number = defaultCountryCode + strings.TrimPrefix(number, "0")
+	}
+
+	// E.164 numbers have at most 15 digits
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	return "+" + number, nil
+}
@@ -0,0 +1,48 @@
+package notifier
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone   string
+		want    string
+		wantErr bool
+	}{
+		{"081234567890", "+6281234567890", false},
+		{"0812-3456-7890", "+6281234567890", false},
+		{"(0812) 3456.7890", "+6281234567890", false},
+		{"  081234567890  ", "+6281234567890", false},
+		{"+6281234567890", "+6281234567890", false},
+		{"+62 812 3456 7890", "+6281234567890", false},
+		{"006581234567", "+6581234567", false},
+		{"+1 (415) 555-0100", "+14155550100", false},
+		{"6281234567890", "+6281234567890", false}, // Already has a country code
+		{"01234", "", true},                        // Too short
+		{"+1234567890123456", "", true},            // More than 15 digits
+		{"+0812345678", "", true},                  // No country code starts with 0
+		{"0812 3456 789x", "", true},               // Letters
+		{"62+81234567890", "", true},               // Plus in the middle
+		{"+62/81234567890", "", true},              // Unknown separator
+		{"", "", true},
+		{"   ", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePhone(tt.phone, "62")
+		if tt.wantErr {
+			if err != ErrInvalidPhone {
+				t.Errorf("NormalizePhone(%q) = %q, %v; want ErrInvalidPhone", tt.phone, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, %v; want %q", tt.phone, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizePhoneDefaultCountryCode(t *testing.T) {
+	got, err := NormalizePhone("020 7946 0958", "44")
+	if err != nil || got != "+442079460958" {
+		t.Errorf("NormalizePhone = %q, %v; want +442079460958", got, err)
+	}
+}
@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reservation-api/internal/models"
+	"strings"
+	"time"
+)
+
+// twilioBaseURL is the base URL of the Twilio REST API
+const twilioBaseURL = "https://api.twilio.com/2010-04-01"
+
+// SMSNotifier sends text messages through the Twilio Messages API
+type SMSNotifier struct {
+	accountSID string
+	authToken  string
+	from       string // Sender phone number or alphanumeric sender ID
+	httpClient *http.Client
+}
+
+// NewSMSNotifier creates a new Twilio SMS notifier
+func NewSMSNotifier(accountSID, authToken, from string) *SMSNotifier {
+	return &SMSNotifier{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the notifier identifier
+func (n *SMSNotifier) Name() string {
+	return "twilio"
+}
+
+// Channel returns the channel the notifier delivers over
+func (n *SMSNotifier) Channel() models.NotificationChannel {
+	return models.ChannelSMS
+}
+
+// Send sends a text message
+func (n *SMSNotifier) Send(msg Message) error {
+	form := url.Values{}
+	form.Set("To", msg.To)
+	form.Set("From", n.from)
+	form.Set("Body", msg.Body)
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioBaseURL, n.accountSID)
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(n.accountSID, n.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio API returned status %d: %s (code %d)", resp.StatusCode, apiErr.Message, apiErr.Code)
+		}
+		return fmt.Errorf("twilio API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"reservation-api/internal/models"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// templates are the message templates by name. Each defines a "subject" and a "body"
+// for email, and a short single line "text" for SMS and WhatsApp.
+var templates = parseTemplates()
+
+// parseTemplates parses every embedded template file into its own template set, as
+// they all define the same block names
+func parseTemplates() map[string]*template.Template {
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+
+	result := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".tmpl")
+		result[name] = template.Must(template.ParseFS(templateFS, path.Join("templates", file.Name())))
+	}
+	return result
+}
+
+// Render renders a named template for a channel. The subject is empty for channels
+// other than email.
+func Render(name string, channel models.NotificationChannel, data interface{}) (subject, body string, err error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown message template %q", name)
+	}
+
+	if channel != models.ChannelEmail {
+		text, err := execute(tmpl, "text", data)
+		return "", text, err
+	}
+
+	if subject, err = execute(tmpl, "subject", data); err != nil {
+		return "", "", err
+	}
+	if body, err = execute(tmpl, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, body + "\n", nil
+}
+
+// execute executes a block of a template, trimming surrounding whitespace
+func execute(tmpl *template.Template, block string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, block, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
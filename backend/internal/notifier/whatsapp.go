@@ -0,0 +1,106 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reservation-api/internal/models"
+	"strings"
+	"time"
+)
+
+// whatsAppBaseURL is the base URL of the WhatsApp Business Cloud API
+const whatsAppBaseURL = "https://graph.facebook.com/v21.0"
+
+// WhatsAppNotifier sends messages through the WhatsApp Business Cloud API.
+//
+// WhatsApp only delivers free-form text within 24 hours of the user's last message
+// to the business. When a template is configured, messages are sent as that
+// approved template instead, with the message text as its single body parameter.
+type WhatsAppNotifier struct {
+	token            string
+	phoneNumberID    string
+	template         string
+	templateLanguage string
+	httpClient       *http.Client
+}
+
+// NewWhatsAppNotifier creates a new WhatsApp notifier sending from a business phone
+// number. template may be empty to send free-form text.
+func NewWhatsAppNotifier(token, phoneNumberID, template, templateLanguage string) *WhatsAppNotifier {
+	return &WhatsAppNotifier{
+		token:            token,
+		phoneNumberID:    phoneNumberID,
+		template:         template,
+		templateLanguage: templateLanguage,
+		httpClient:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the notifier identifier
+func (n *WhatsAppNotifier) Name() string {
+	return "whatsapp-cloud"
+}
+
+// Channel returns the channel the notifier delivers over
+func (n *WhatsAppNotifier) Channel() models.NotificationChannel {
+	return models.ChannelWhatsApp
+}
+
+// Send sends a WhatsApp message
+func (n *WhatsAppNotifier) Send(msg Message) error {
+	body := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                strings.TrimPrefix(msg.To, "+"),
+	}
+	if n.template != "" {
+		body["type"] = "template"
+		body["template"] = map[string]interface{}{
+			"name":     n.template,
+			"language": map[string]string{"code": n.templateLanguage},
+			"components": []map[string]interface{}{{
+				"type":       "body",
+				"parameters": []map[string]string{{"type": "text", "text": msg.Body}},
+			}},
+		}
+	} else {
+		body["type"] = "text"
+		body["text"] = map[string]interface{}{"body": msg.Body}
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/messages", whatsAppBaseURL, n.phoneNumberID)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+n.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("whatsapp API returned status %d: %s (code %d)", resp.StatusCode, apiErr.Error.Message, apiErr.Error.Code)
+		}
+		return fmt.Errorf("whatsapp API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reservation-api/internal/utils"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeKeyID is the ID of the fake issuer's signing key
+const fakeKeyID = "fake-key"
+
+// FakeIdentity is the user the fake issuer signs in
+type FakeIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// fakeGrant is an authorization code handed out by the fake issuer
+type fakeGrant struct {
+	clientID  string
+	nonce     string
+	challenge string
+	identity  FakeIdentity
+}
+
+// FakeIssuer is a local OpenID Connect issuer for development and tests. Serve it
+// over HTTP, e.g. with httptest.NewServer, and set URL to its address. Its sign-in
+// page approves right away as Identity and redirects back with a code. Like real
+// providers it checks the PKCE code verifier of codes requested with a challenge.
+type FakeIssuer struct {
+	// URL is the issuer URL the fake is served at
+	URL string
+
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]fakeGrant
+
+	// Identity is the user signed in by the next authorization request
+	Identity FakeIdentity
+}
+
+// NewFakeIssuer creates a new fake issuer with a fresh signing key
+func NewFakeIssuer() (*FakeIssuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FakeIssuer{
+		key:    key,
+		grants: make(map[string]fakeGrant),
+		Identity: FakeIdentity{
+			Subject:       "fake-user",
+			Email:         "fake.user@example.com",
+			EmailVerified: true,
+			Name:          "Fake User",
+		},
+	}, nil
+}
+
+// ServeHTTP serves the discovery document, signing keys, sign-in page and token endpoint
+func (f *FakeIssuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 f.URL,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"jwks_uri":               f.URL + "/jwks",
+		})
+	case "/jwks":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": fakeKeyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(f.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.E)).Bytes()),
+			}},
+		})
+	case "/authorize":
+		f.authorize(w, r)
+	case "/token":
+		f.token(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authorize signs in as Identity and redirects back with a code
+func (f *FakeIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirect.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code, err := utils.NewOpaqueToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	f.mu.Lock()
+	f.grants[code] = fakeGrant{
+		clientID:  query.Get("client_id"),
+		nonce:     query.Get("nonce"),
+		challenge: query.Get("code_challenge"),
+		identity:  f.Identity,
+	}
+	f.mu.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token exchanges a code for an ID token. Codes can be used once.
+func (f *FakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	f.mu.Lock()
+	grant, ok := f.grants[code]
+	delete(f.grants, code)
+	f.mu.Unlock()
+
+	clientID, _, _ := r.BasicAuth()
+	if clientID == "" {
+		clientID = r.PostForm.Get("client_id")
+	}
+	if clientID, _ = url.QueryUnescape(clientID); !ok || clientID != grant.clientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if grant.challenge != "" && codeChallenge(r.PostForm.Get("code_verifier")) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := f.IDToken(grant.identity, grant.clientID, grant.nonce)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "fake-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// IDToken signs an ID token for an identity, as a mobile SDK would obtain it
+func (f *FakeIssuer) IDToken(identity FakeIdentity, audience, nonce string) (string, error) {
+	now := time.Now()
+	claims := idTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    f.URL,
+			Subject:   identity.Subject,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Nonce:         nonce,
+		Email:         identity.Email,
+		EmailVerified: flexBool(identity.EmailVerified),
+		Name:          identity.Name,
+	}
+	return f.sign(claims)
+}
+
+// sign signs ID token claims with the issuer's key
+func (f *FakeIssuer) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = fakeKeyID
+	return token.SignedString(f.key)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,159 @@
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyRefreshInterval is the least time between fetches of the signing keys, so
+// tokens with unknown key IDs cannot make us hammer the provider
+const keyRefreshInterval = time.Minute
+
+// discoveryDocument holds the provider metadata we use
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// keySet fetches and caches the discovery document and signing keys of an issuer
+type keySet struct {
+	issuer     string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	doc       *discoveryDocument
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func newKeySet(issuer string, httpClient *http.Client) *keySet {
+	return &keySet{issuer: issuer, httpClient: httpClient}
+}
+
+// discovery returns the discovery document, fetching it the first time
+func (s *keySet) discovery() (*discoveryDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.discoveryLocked()
+}
+
+func (s *keySet) discoveryLocked() (*discoveryDocument, error) {
+	if s.doc != nil {
+		return s.doc, nil
+	}
+
+	var doc discoveryDocument
+	if err := s.getJSON(s.issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("failed to discover %s: %w", s.issuer, err)
+	}
+	if doc.Issuer != s.issuer {
+		return nil, fmt.Errorf("discovery document of %s names issuer %s", s.issuer, doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document of %s is incomplete", s.issuer)
+	}
+
+	s.doc = &doc
+	return s.doc, nil
+}
+
+// key returns the public key that signed a token. Keys are fetched again when the
+// token names a key we do not know, as providers rotate their keys.
+func (s *keySet) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	if time.Since(s.fetchedAt) < keyRefreshInterval {
+		return nil, errors.New("unknown signing key")
+	}
+	if err := s.fetchKeys(); err != nil {
+		return nil, err
+	}
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// lookup finds a key by ID. A token without a key ID may use the only key there is.
+func (s *keySet) lookup(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// fetchKeys fetches the RSA signing keys of the issuer
+func (s *keySet) fetchKeys() error {
+	doc, err := s.discoveryLocked()
+	if err != nil {
+		return err
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := s.getJSON(doc.JWKSURI, &jwks); err != nil {
+		return fmt.Errorf("failed to fetch signing keys of %s: %w", s.issuer, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// getJSON fetches a JSON document
+func (s *keySet) getJSON(url string, v interface{}) error {
+	resp, err := s.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
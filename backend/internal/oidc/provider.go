@@ -0,0 +1,242 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reservation-api/internal/config"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrUnknownProvider is returned for a provider that is not configured
+var ErrUnknownProvider = errors.New("unknown sign-in provider")
+
+// Google issues ID tokens with its issuer host alone as well as with the full URL
+const (
+	googleIssuer     = "https://accounts.google.com"
+	googleIssuerHost = "accounts.google.com"
+)
+
+// Identity is a user as identified by a provider through a verified ID token
+type Identity struct {
+	Provider      string
+	Subject       string // Stable user ID at the provider
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Providers are the configured providers by name
+type Providers map[string]*Provider
+
+// New creates the providers set up in configuration
+func New(cfg *config.Config) Providers {
+	providers := make(Providers, len(cfg.OIDCProviders))
+	for _, provider := range cfg.OIDCProviders {
+		providers[provider.Name] = NewProvider(provider)
+	}
+	return providers
+}
+
+// Get returns a provider by name
+func (p Providers) Get(name string) (*Provider, error) {
+	provider, ok := p[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// Names returns the names of the providers, sorted
+func (p Providers) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Provider signs users in with an OpenID Connect issuer using the authorization code
+// flow, or verifies ID tokens obtained by the mobile apps with the provider's SDK
+type Provider struct {
+	config     config.OIDCProvider
+	httpClient *http.Client
+	keys       *keySet
+}
+
+// NewProvider creates a new provider. Its endpoints and keys are discovered on first use.
+func NewProvider(cfg config.OIDCProvider) *Provider {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	return &Provider{
+		config:     cfg,
+		httpClient: httpClient,
+		keys:       newKeySet(cfg.Issuer, httpClient),
+	}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL returns the provider page where the user signs in. The provider sends
+// the user back to the redirect URL with the state and a code to exchange.
+func (p *Provider) AuthCodeURL(signIn *SignIn) (string, error) {
+	doc, err := p.keys.discovery()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", signIn.State)
+	query.Set("nonce", signIn.Nonce)
+	query.Set("code_challenge", codeChallenge(signIn.CodeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return doc.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange exchanges an authorization code for the identity of the user. The nonce
+// must match the one sent with the authorization request, and the code verifier the
+// challenge sent with it.
+func (p *Provider) Exchange(code, nonce, verifier string) (*Identity, error) {
+	doc, err := p.keys.discovery()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest(http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s token endpoint returned status %d", p.config.Name, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s token endpoint returned status %d: %s %s", p.config.Name, resp.StatusCode, result.Error, result.ErrorDescription)
+	}
+	if result.IDToken == "" {
+		return nil, fmt.Errorf("%s token endpoint returned no ID token", p.config.Name)
+	}
+
+	return p.Verify(result.IDToken, nonce)
+}
+
+// Verify verifies an ID token issued to one of our clients and returns the identity
+// in it. A nonce, when given, must match the one in the token.
+func (p *Provider) Verify(rawIDToken, nonce string) (*Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, p.keys.key,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	if !p.acceptsIssuer(claims.Issuer) {
+		return nil, errors.New("invalid ID token: wrong issuer")
+	}
+	if !p.acceptsAudience(claims.Audience) {
+		return nil, errors.New("invalid ID token: wrong audience")
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, errors.New("invalid ID token: wrong nonce")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid ID token: no subject")
+	}
+
+	return &Identity{
+		Provider:      p.config.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// acceptsIssuer checks that a token was issued by the provider
+func (p *Provider) acceptsIssuer(issuer string) bool {
+	if issuer == p.config.Issuer {
+		return true
+	}
+	return p.config.Issuer == googleIssuer && issuer == googleIssuerHost
+}
+
+// acceptsAudience checks that a token was issued to one of our clients
+func (p *Provider) acceptsAudience(audience jwt.ClaimStrings) bool {
+	for _, aud := range audience {
+		if aud == p.config.ClientID || slices.Contains(p.config.Audiences, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+// idTokenClaims are the ID token claims we use
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+// flexBool is a boolean claim that some providers send as a string
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = flexBool(v == "true")
+	default:
+		*b = false
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package oidc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reservation-api/internal/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newTestProvider serves a fake issuer and returns a provider signing in with it
+func newTestProvider(t *testing.T) (*Provider, *FakeIssuer) {
+	t.Helper()
+	issuer, err := NewFakeIssuer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(issuer)
+	t.Cleanup(server.Close)
+	issuer.URL = server.URL
+
+	provider := NewProvider(config.OIDCProvider{
+		Name:         "fake",
+		Issuer:       server.URL,
+		ClientID:     "web-client",
+		ClientSecret: "web-secret",
+		RedirectURL:  "http://localhost:3000/auth/fake/callback",
+		Audiences:    []string{"mobile-client"},
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+	return provider, issuer
+}
+
+// authorize follows a sign-in at the fake issuer and returns the code and state it
+// sends back
+func authorize(t *testing.T, provider *Provider, signIn *SignIn) (string, string) {
+	t.Helper()
+	authURL, err := provider.AuthCodeURL(signIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize returned status %d", resp.StatusCode)
+	}
+
+	redirect, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return redirect.Query().Get("code"), redirect.Query().Get("state")
+}
+
+func TestExchange(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+	issuer.Identity = FakeIdentity{Subject: "user-1", Email: "user@example.com", EmailVerified: true, Name: "User"}
+
+	signIn, err := NewState(provider.Name(), "secret", 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := authorize(t, provider, signIn)
+	if state != signIn.State {
+		t.Fatalf("state = %q, want the one sent", state)
+	}
+
+	identity, err := provider.Exchange(code, signIn.Nonce, signIn.CodeVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Identity{Provider: "fake", Subject: "user-1", Email: "user@example.com", EmailVerified: true, Name: "User"}
+	if *identity != want {
+		t.Errorf("identity = %+v, want %+v", *identity, want)
+	}
+
+	if _, err := provider.Exchange(code, signIn.Nonce, signIn.CodeVerifier); err == nil {
+		t.Error("code exchanged twice")
+	}
+}
+
+func TestExchangeRejectsOtherVerifier(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	signIn, err := NewState(provider.Name(), "secret", 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewState(provider.Name(), "secret", 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := authorize(t, provider, signIn)
+
+	if _, err := provider.Exchange(code, signIn.Nonce, other.CodeVerifier); err == nil {
+		t.Error("code exchanged with the verifier of another sign-in")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+	stranger, err := NewFakeIssuer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger.URL = issuer.URL
+
+	now := time.Now()
+	valid := func() idTokenClaims {
+		return idTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    issuer.URL,
+				Subject:   "user-1",
+				Audience:  jwt.ClaimStrings{"web-client"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+			Nonce:         "nonce-1",
+			Email:         "user@example.com",
+			EmailVerified: true,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		change  func(*idTokenClaims)
+		signer  *FakeIssuer
+		nonce   string
+		wantErr bool
+	}{
+		{"valid", func(*idTokenClaims) {}, issuer, "nonce-1", false},
+		{"mobile client audience", func(c *idTokenClaims) { c.Audience = jwt.ClaimStrings{"mobile-client"} }, issuer, "nonce-1", false},
+		{"no nonce expected", func(*idTokenClaims) {}, issuer, "", true},
+		{"no nonce in token", func(c *idTokenClaims) { c.Nonce = "" }, issuer, "nonce-1", true},
+		{"wrong audience", func(c *idTokenClaims) { c.Audience = jwt.ClaimStrings{"other-client"} }, issuer, "nonce-1", true},
+		{"wrong issuer", func(c *idTokenClaims) { c.Issuer = "https://evil.example.com" }, issuer, "nonce-1", true},
+		{"issuer without scheme", func(c *idTokenClaims) { c.Issuer = strings.TrimPrefix(issuer.URL, "http://") }, issuer, "nonce-1", true},
+		{"wrong nonce", func(c *idTokenClaims) { c.Nonce = "nonce-2" }, issuer, "nonce-1", true},
+		{"expired", func(c *idTokenClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Hour)) }, issuer, "nonce-1", true},
+		{"no expiry", func(c *idTokenClaims) { c.ExpiresAt = nil }, issuer, "nonce-1", true},
+		{"no subject", func(c *idTokenClaims) { c.Subject = "" }, issuer, "nonce-1", true},
+		{"signed by another key", func(*idTokenClaims) {}, stranger, "nonce-1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.change(&claims)
+			token, err := tt.signer.sign(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			identity, err := provider.Verify(token, tt.nonce)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("token accepted: %+v", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identity.Subject != "user-1" || !identity.EmailVerified {
+				t.Errorf("identity = %+v", identity)
+			}
+		})
+	}
+}
+
+func TestAcceptsIssuer(t *testing.T) {
+	google := NewProvider(config.OIDCProvider{Name: "google", Issuer: "https://accounts.google.com"})
+	other := NewProvider(config.OIDCProvider{Name: "other", Issuer: "https://login.example.com"})
+
+	tests := []struct {
+		name     string
+		provider *Provider
+		issuer   string
+		want     bool
+	}{
+		{"google", google, "https://accounts.google.com", true},
+		{"google without scheme", google, "accounts.google.com", true},
+		{"google over http", google, "http://accounts.google.com", false},
+		{"other", other, "https://login.example.com", true},
+		{"other without scheme", other, "login.example.com", false},
+		{"google at other", other, "accounts.google.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.provider.acceptsIssuer(tt.issuer); got != tt.want {
+				t.Errorf("acceptsIssuer(%q) = %v, want %v", tt.issuer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlexBool(t *testing.T) {
+	tests := []struct {
+		json string
+		want bool
+	}{
+		{`true`, true},
+		{`false`, false},
+		{`"true"`, true},
+		{`"false"`, false},
+		{`1`, false},
+	}
+	for _, tt := range tests {
+		var b flexBool
+		if err := b.UnmarshalJSON([]byte(tt.json)); err != nil {
+			t.Fatal(err)
+		}
+		if bool(b) != tt.want {
+			t.Errorf("%s = %v, want %v", tt.json, b, tt.want)
+		}
+	}
+}
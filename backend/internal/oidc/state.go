@@ -0,0 +1,95 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"reservation-api/internal/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// stateAudience keeps state tokens from being mistaken for any other token signed
+// with the same secret
+const stateAudience = "oidc-state"
+
+// errInvalidState is returned for a state that was not issued to this browser or
+// has expired
+var errInvalidState = errors.New("invalid or expired sign-in state")
+
+// stateClaims are carried through the provider in the state parameter, so the
+// callback needs no server-side storage. The challenge ties the state to the PKCE
+// code verifier the browser keeps, so a state and code from someone else's sign-in
+// cannot be replayed in another browser.
+type stateClaims struct {
+	Provider  string `json:"provider"`
+	Nonce     string `json:"nonce"`
+	Challenge string `json:"challenge"`
+	jwt.RegisteredClaims
+}
+
+// SignIn is a sign-in started with a provider
+type SignIn struct {
+	State        string // Sent to the provider and back
+	Nonce        string // Sent to the provider, which puts it in the ID token
+	CodeVerifier string // Kept by the browser and sent with the callback
+}
+
+// NewState starts a sign-in with a provider, valid for ttl. The state is signed and
+// bound to a new PKCE code verifier.
+func NewState(provider, secret string, ttl time.Duration) (*SignIn, error) {
+	nonce, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := stateClaims{
+		Provider:  provider,
+		Nonce:     nonce,
+		Challenge: codeChallenge(verifier),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{stateAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	state, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	return &SignIn{State: state, Nonce: nonce, CodeVerifier: verifier}, nil
+}
+
+// ParseState verifies a state returned by a provider together with the code verifier
+// the browser kept, and returns its nonce
+func ParseState(state, verifier, provider, secret string) (string, error) {
+	claims := &stateClaims{}
+	_, err := jwt.ParseWithClaims(state, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithAudience(stateAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", errInvalidState
+	}
+
+	if claims.Provider != provider {
+		return "", errInvalidState
+	}
+	if verifier == "" || subtle.ConstantTimeCompare([]byte(claims.Challenge), []byte(codeChallenge(verifier))) != 1 {
+		return "", errInvalidState
+	}
+	return claims.Nonce, nil
+}
+
+// codeChallenge returns the S256 PKCE challenge of a code verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
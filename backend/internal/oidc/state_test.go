@@ -0,0 +1,63 @@
+package oidc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseState(t *testing.T) {
+	signIn, err := NewState("google", "secret", 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := NewState("google", "secret", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewState("google", "secret", 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		state    string
+		verifier string
+		provider string
+		secret   string
+		wantErr  bool
+	}{
+		{"valid", signIn.State, signIn.CodeVerifier, "google", "secret", false},
+		{"verifier of another sign-in", signIn.State, other.CodeVerifier, "google", "secret", true},
+		{"no verifier", signIn.State, "", "google", "secret", true},
+		{"other provider", signIn.State, signIn.CodeVerifier, "apple", "secret", true},
+		{"other secret", signIn.State, signIn.CodeVerifier, "google", "other", true},
+		{"expired", expired.State, expired.CodeVerifier, "google", "secret", true},
+		{"garbage", "not-a-state", signIn.CodeVerifier, "google", "secret", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce, err := ParseState(tt.state, tt.verifier, tt.provider, tt.secret)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("state accepted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if nonce != signIn.Nonce {
+				t.Errorf("nonce = %q, want %q", nonce, signIn.Nonce)
+			}
+		})
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// Example from RFC 7636, appendix B
+	got := codeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("codeChallenge = %s, want %s", got, want)
+	}
+}
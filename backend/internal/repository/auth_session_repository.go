@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"time"
+
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuthSessionRepository handles sign-in sessions and their refresh tokens
+type AuthSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthSessionRepository creates a new auth session repository
+func NewAuthSessionRepository(db *gorm.DB) *AuthSessionRepository {
+	return &AuthSessionRepository{db: db}
+}
+
+// Create creates a session with its first refresh token
+func (r *AuthSessionRepository) Create(session *models.AuthSession, token *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Create(token).Error
+	})
+}
+
+// FindByID finds a session by ID with its user
+func (r *AuthSessionRepository) FindByID(id uint) (*models.AuthSession, error) {
+	var session models.AuthSession
+	err := r.db.Preload("User").First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindRefreshToken finds a refresh token by its hash with its session and user
+func (r *AuthSessionRepository) FindRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Preload("Session").
+		Preload("Session.User").
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks a refresh token used and creates its successor in the same session.
+// Returns false if the token was already used, e.g. by a concurrent request.
+func (r *AuthSessionRepository) Rotate(used *models.RefreshToken, next *models.RefreshToken, now time.Time) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", used.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		next.SessionID = used.SessionID
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.AuthSession{}).
+			Where("id = ?", used.SessionID).
+			Update("last_used_at", now).Error; err != nil {
+			return err
+		}
+
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// Revoke revokes a session. Revoking a revoked session keeps the first reason.
+func (r *AuthSessionRepository) Revoke(id uint, reason string, now time.Time) error {
+	return r.db.Model(&models.AuthSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"revoked_reason": reason,
+		}).Error
+}
+
+// RevokeAllForUser revokes every active session of a user. Returns the number revoked.
+func (r *AuthSessionRepository) RevokeAllForUser(userID uint, reason string, now time.Time) (int64, error) {
+	result := r.db.Model(&models.AuthSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"revoked_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// RevokeOthersForUser revokes every active session of a user except one. Returns the
+// number revoked.
+func (r *AuthSessionRepository) RevokeOthersForUser(userID, keepID uint, reason string, now time.Time) (int64, error) {
+	result := r.db.Model(&models.AuthSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"revoked_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExpiredTokens deletes refresh tokens that expired before a time. Returns the
+// number deleted.
+func (r *AuthSessionRepository) DeleteExpiredTokens(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("expires_at < ?", before).
+		Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
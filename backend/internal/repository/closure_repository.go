@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClosureRepository handles studio closure data operations
+type ClosureRepository struct {
+	db *gorm.DB
+}
+
+// NewClosureRepository creates a new closure repository
+func NewClosureRepository(db *gorm.DB) *ClosureRepository {
+	return &ClosureRepository{db: db}
+}
+
+// Create creates a new closure
+func (r *ClosureRepository) Create(closure *models.Closure) error {
+	return r.db.Create(closure).Error
+}
+
+// FindAll retrieves all closures, latest first
+func (r *ClosureRepository) FindAll() ([]models.Closure, error) {
+	var closures []models.Closure
+	err := r.db.Preload("Court").
+		Preload("Timeslot").
+		Order("start_date DESC").
+		Find(&closures).Error
+	return closures, err
+}
+
+// FindByID finds a closure by ID
+func (r *ClosureRepository) FindByID(id uint) (*models.Closure, error) {
+	var closure models.Closure
+	err := r.db.First(&closure, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &closure, nil
+}
+
+// FindFrom finds the closures that can apply on or after a date: recurring closures
+// and closures that have not ended yet
+func (r *ClosureRepository) FindFrom(date time.Time) ([]models.Closure, error) {
+	var closures []models.Closure
+	err := r.db.Where("recurring = ? OR end_date >= ?", true, date).
+		Find(&closures).Error
+	return closures, err
+}
+
+// FindCovering finds a closure covering a court and timeslot on a date. Returns
+// gorm.ErrRecordNotFound if the class is not closed.
+func (r *ClosureRepository) FindCovering(courtID, timeslotID uint, date time.Time) (*models.Closure, error) {
+	var closures []models.Closure
+	err := r.db.
+		Where("(court_id IS NULL OR court_id = ?) AND (timeslot_id IS NULL OR timeslot_id = ?)", courtID, timeslotID).
+		Where("recurring = ? OR (start_date <= ? AND end_date >= ?)", true, date, date).
+		Order("id ASC").
+		Find(&closures).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range closures {
+		if closures[i].Covers(courtID, timeslotID, date) {
+			return &closures[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Delete deletes a closure
+func (r *ClosureRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Closure{}, id).Error
+}
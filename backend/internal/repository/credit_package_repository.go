@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CreditPackageRepository handles credit package data operations
+type CreditPackageRepository struct {
+	db *gorm.DB
+}
+
+// NewCreditPackageRepository creates a new credit package repository
+func NewCreditPackageRepository(db *gorm.DB) *CreditPackageRepository {
+	return &CreditPackageRepository{db: db}
+}
+
+// Create creates a new credit package
+func (r *CreditPackageRepository) Create(pkg *models.CreditPackage) error {
+	return r.db.Create(pkg).Error
+}
+
+// FindAll retrieves all active credit packages
+func (r *CreditPackageRepository) FindAll() ([]models.CreditPackage, error) {
+	var packages []models.CreditPackage
+	err := r.db.Where("is_active = ?", true).Order("price ASC").Find(&packages).Error
+	return packages, err
+}
+
+// FindByID finds a credit package by ID
+func (r *CreditPackageRepository) FindByID(id uint) (*models.CreditPackage, error) {
+	var pkg models.CreditPackage
+	err := r.db.First(&pkg, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// Update updates a credit package
+func (r *CreditPackageRepository) Update(pkg *models.CreditPackage) error {
+	return r.db.Save(pkg).Error
+}
+
+// Delete soft deletes a credit package
+func (r *CreditPackageRepository) Delete(id uint) error {
+	return r.db.Delete(&models.CreditPackage{}, id).Error
+}
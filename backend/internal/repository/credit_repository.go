@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"errors"
+	"reservation-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoCredits is returned when a user has no usable credit to redeem
+var ErrNoCredits = errors.New("no usable credits")
+
+// CreditRepository handles credit purchases and the credit ledger
+type CreditRepository struct {
+	db *gorm.DB
+}
+
+// NewCreditRepository creates a new credit repository
+func NewCreditRepository(db *gorm.DB) *CreditRepository {
+	return &CreditRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries in the given transaction
+func (r *CreditRepository) WithTx(tx *gorm.DB) *CreditRepository {
+	return &CreditRepository{db: tx}
+}
+
+// CreatePurchase creates a new credit purchase
+func (r *CreditRepository) CreatePurchase(purchase *models.CreditPurchase) error {
+	return r.db.Create(purchase).Error
+}
+
+// FindPurchaseByID finds a credit purchase by ID with its package
+func (r *CreditRepository) FindPurchaseByID(id uint) (*models.CreditPurchase, error) {
+	var purchase models.CreditPurchase
+	err := r.db.Preload("Package").First(&purchase, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+// FindPurchasesByUserID finds all credit purchases of a user, newest first
+func (r *CreditRepository) FindPurchasesByUserID(userID uint) ([]models.CreditPurchase, error) {
+	var purchases []models.CreditPurchase
+	err := r.db.Preload("Package").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&purchases).Error
+	return purchases, err
+}
+
+// FindTransactionsByUserID finds the credit ledger of a user, newest first
+func (r *CreditRepository) FindTransactionsByUserID(userID uint) ([]models.CreditTransaction, error) {
+	var transactions []models.CreditTransaction
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// Balance sums the usable credits of a user at the given time
+func (r *CreditRepository) Balance(userID uint, now time.Time) (int, error) {
+	var balance int64
+	err := r.db.Model(&models.CreditPurchase{}).
+		Select("COALESCE(SUM(credits_remaining), 0)").
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, models.PurchasePaid, now).
+		Scan(&balance).Error
+	return int(balance), err
+}
+
+// ActivatePurchase marks a pending purchase as paid and credits the user. Returns
+// false if the purchase was no longer pending.
+func (r *CreditRepository) ActivatePurchase(purchaseID uint, paidAt time.Time) (bool, error) {
+	activated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var purchase models.CreditPurchase
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Package").
+			First(&purchase, purchaseID).Error; err != nil {
+			return err
+		}
+		if purchase.Status != models.PurchasePending {
+			return nil
+		}
+
+		expiresAt := paidAt.AddDate(0, 0, purchase.Package.ValidityDays)
+		purchase.Status = models.PurchasePaid
+		purchase.CreditsRemaining = purchase.Credits
+		purchase.PaidAt = &paidAt
+		purchase.ExpiresAt = &expiresAt
+		if err := tx.Omit(clause.Associations).Save(&purchase).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.CreditTransaction{
+			UserID:     purchase.UserID,
+			PurchaseID: purchase.ID,
+			Type:       models.CreditPurchased,
+			Amount:     purchase.Credits,
+			Note:       purchase.Package.Name,
+		}).Error; err != nil {
+			return err
+		}
+
+		activated = true
+		return nil
+	})
+	return activated, err
+}
+
+// CancelPurchase cancels a purchase that is still pending
+func (r *CreditRepository) CancelPurchase(purchaseID uint) error {
+	return r.db.Model(&models.CreditPurchase{}).
+		Where("id = ? AND status = ?", purchaseID, models.PurchasePending).
+		Update("status", models.PurchaseCancelled).Error
+}
+
+// Redeem spends one credit of a user on a reservation, taking it from the usable
+// purchase that expires first
+func (r *CreditRepository) Redeem(userID, reservationID uint, now time.Time) error {
+	var purchase models.CreditPurchase
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND status = ? AND credits_remaining > 0 AND expires_at > ?",
+			userID, models.PurchasePaid, now).
+		Order("expires_at ASC").
+		First(&purchase).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoCredits
+		}
+		return err
+	}
+
+	if err := r.db.Model(&purchase).
+		Update("credits_remaining", gorm.Expr("credits_remaining - 1")).Error; err != nil {
+		return err
+	}
+
+	return r.db.Create(&models.CreditTransaction{
+		UserID:        userID,
+		PurchaseID:    purchase.ID,
+		ReservationID: &reservationID,
+		Type:          models.CreditRedeemed,
+		Amount:        -1,
+	}).Error
+}
+
+// ReturnCredit gives back the credit redeemed for a reservation to the purchase it
+// came from. Returns false if no credit was redeemed or it was already returned.
+func (r *CreditRepository) ReturnCredit(reservationID uint, note string) (bool, error) {
+	returned := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var redeemed models.CreditTransaction
+		if err := tx.Where("reservation_id = ? AND type = ?", reservationID, models.CreditRedeemed).
+			Order("id DESC").
+			First(&redeemed).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		// Lock the purchase before reading the ledger, so concurrent returns for the
+		// reservation wait for each other and only the first one gives the credit back
+		var purchase models.CreditPurchase
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&purchase, redeemed.PurchaseID).Error; err != nil {
+			return err
+		}
+
+		var balance int64
+		if err := tx.Model(&models.CreditTransaction{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("reservation_id = ?", reservationID).
+			Scan(&balance).Error; err != nil {
+			return err
+		}
+		if balance >= 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.CreditPurchase{}).
+			Where("id = ?", redeemed.PurchaseID).
+			Update("credits_remaining", gorm.Expr("credits_remaining + 1")).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.CreditTransaction{
+			UserID:        redeemed.UserID,
+			PurchaseID:    redeemed.PurchaseID,
+			ReservationID: &reservationID,
+			Type:          models.CreditReturned,
+			Amount:        1,
+			Note:          note,
+		}).Error; err != nil {
+			return err
+		}
+
+		returned = true
+		return nil
+	})
+	return returned, err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+	"reservation-api/internal/testdb"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReturnCreditConcurrent(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewCreditRepository(db)
+
+	user := models.User{Name: "Member", Email: "member@example.com", Password: "x"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	pack := models.CreditPackage{Name: "5 classes", Credits: 5, Price: 500000, ValidityDays: 30, IsActive: true}
+	if err := db.Create(&pack).Error; err != nil {
+		t.Fatal(err)
+	}
+	purchase := models.CreditPurchase{UserID: user.ID, PackageID: pack.ID, Credits: 5, Price: 500000, Status: models.PurchasePending}
+	if err := db.Create(&purchase).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if _, err := repo.ActivatePurchase(purchase.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	const reservationID = 42
+	if err := repo.Redeem(user.ID, reservationID, now); err != nil {
+		t.Fatal(err)
+	}
+
+	const attempts = 10
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		returned int
+		failures []error
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.ReturnCredit(reservationID, "Reservation cancelled by customer")
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, err)
+			} else if ok {
+				returned++
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range failures {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if returned != 1 {
+		t.Errorf("credit returned %d times, want once", returned)
+	}
+
+	balance, err := repo.Balance(user.ID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 5 {
+		t.Errorf("balance = %d, want 5", balance)
+	}
+}
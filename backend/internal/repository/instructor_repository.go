@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InstructorRepository handles instructor data operations
+type InstructorRepository struct {
+	db *gorm.DB
+}
+
+// NewInstructorRepository creates a new instructor repository
+func NewInstructorRepository(db *gorm.DB) *InstructorRepository {
+	return &InstructorRepository{db: db}
+}
+
+// Create creates a new instructor
+func (r *InstructorRepository) Create(instructor *models.Instructor) error {
+	return r.db.Create(instructor).Error
+}
+
+// FindAll retrieves all instructors, optionally only active ones
+func (r *InstructorRepository) FindAll(activeOnly bool) ([]models.Instructor, error) {
+	var instructors []models.Instructor
+	query := r.db.Order("name ASC")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&instructors).Error
+	return instructors, err
+}
+
+// FindByID finds an instructor by ID
+func (r *InstructorRepository) FindByID(id uint) (*models.Instructor, error) {
+	var instructor models.Instructor
+	err := r.db.First(&instructor, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &instructor, nil
+}
+
+// FindByUserID finds the instructor profile of a user
+func (r *InstructorRepository) FindByUserID(userID uint) (*models.Instructor, error) {
+	var instructor models.Instructor
+	err := r.db.Where("user_id = ?", userID).First(&instructor).Error
+	if err != nil {
+		return nil, err
+	}
+	return &instructor, nil
+}
+
+// Update updates an instructor
+func (r *InstructorRepository) Update(instructor *models.Instructor) error {
+	return r.db.Omit(clause.Associations).Save(instructor).Error
+}
+
+// Delete soft deletes an instructor and removes them from their class templates
+// and sessions
+func (r *InstructorRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ClassTemplate{}).
+			Where("instructor_id = ?", id).
+			Update("instructor_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ClassSession{}).
+			Where("instructor_id = ?", id).
+			Update("instructor_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Instructor{}, id).Error
+	})
+}
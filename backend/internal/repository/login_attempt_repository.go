@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"reservation-api/internal/loginlimit"
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptRepository keeps failed login counts in the database, shared by every
+// instance of the API. It implements loginlimit.Store.
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Get returns the attempts of a key, zero if there are none
+func (r *LoginAttemptRepository) Get(key string) (loginlimit.Attempts, error) {
+	var attempt models.LoginAttempt
+	err := r.db.Where("key = ?", key).First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return loginlimit.Attempts{}, nil
+	}
+	if err != nil {
+		return loginlimit.Attempts{}, err
+	}
+
+	attempts := loginlimit.Attempts{
+		Failures:      attempt.Failures,
+		LastFailureAt: attempt.LastFailureAt,
+	}
+	if attempt.LockedUntil != nil {
+		attempts.LockedUntil = *attempt.LockedUntil
+	}
+	return attempts, nil
+}
+
+// Set stores the attempts of a key until they expire
+func (r *LoginAttemptRepository) Set(key string, attempts loginlimit.Attempts, expiresAt time.Time) error {
+	attempt := models.LoginAttempt{
+		Key:           key,
+		Failures:      attempts.Failures,
+		LastFailureAt: attempts.LastFailureAt,
+		ExpiresAt:     expiresAt,
+	}
+	if !attempts.LockedUntil.IsZero() {
+		attempt.LockedUntil = &attempts.LockedUntil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"failures", "last_failure_at", "locked_until", "expires_at", "updated_at"}),
+	}).Create(&attempt).Error
+}
+
+// Delete forgets the attempts of a key
+func (r *LoginAttemptRepository) Delete(key string) error {
+	return r.db.Where("key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
+
+// DeleteExpired drops records that expired before a time. Returns the number dropped.
+func (r *LoginAttemptRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"time"
+
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// LoginFailureRepository handles the audit log of failed logins
+type LoginFailureRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginFailureRepository creates a new login failure repository
+func NewLoginFailureRepository(db *gorm.DB) *LoginFailureRepository {
+	return &LoginFailureRepository{db: db}
+}
+
+// Create records a failed login
+func (r *LoginFailureRepository) Create(failure *models.LoginFailure) error {
+	return r.db.Create(failure).Error
+}
+
+// FindAll retrieves failed logins, newest first, optionally filtered by email and IP address
+func (r *LoginFailureRepository) FindAll(email, ipAddress string, limit int) ([]models.LoginFailure, error) {
+	var failures []models.LoginFailure
+	query := r.db.Order("created_at DESC").Limit(limit)
+	if email != "" {
+		query = query.Where("LOWER(email) = LOWER(?)", email)
+	}
+	if ipAddress != "" {
+		query = query.Where("ip_address = ?", ipAddress)
+	}
+	err := query.Find(&failures).Error
+	return failures, err
+}
+
+// DeleteBefore permanently deletes failed logins recorded before a time. Returns the
+// number deleted.
+func (r *LoginFailureRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("created_at < ?", before).
+		Delete(&models.LoginFailure{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MembershipPlanRepository handles membership plan data operations
+type MembershipPlanRepository struct {
+	db *gorm.DB
+}
+
+// NewMembershipPlanRepository creates a new membership plan repository
+func NewMembershipPlanRepository(db *gorm.DB) *MembershipPlanRepository {
+	return &MembershipPlanRepository{db: db}
+}
+
+// Create creates a new membership plan
+func (r *MembershipPlanRepository) Create(plan *models.MembershipPlan) error {
+	return r.db.Create(plan).Error
+}
+
+// FindAll retrieves all active membership plans
+func (r *MembershipPlanRepository) FindAll() ([]models.MembershipPlan, error) {
+	var plans []models.MembershipPlan
+	err := r.db.Where("is_active = ?", true).Order("price ASC").Find(&plans).Error
+	return plans, err
+}
+
+// FindByID finds a membership plan by ID
+func (r *MembershipPlanRepository) FindByID(id uint) (*models.MembershipPlan, error) {
+	var plan models.MembershipPlan
+	err := r.db.First(&plan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Update updates a membership plan
+func (r *MembershipPlanRepository) Update(plan *models.MembershipPlan) error {
+	return r.db.Save(plan).Error
+}
+
+// Delete soft deletes a membership plan
+func (r *MembershipPlanRepository) Delete(id uint) error {
+	return r.db.Delete(&models.MembershipPlan{}, id).Error
+}
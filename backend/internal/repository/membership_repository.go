@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"errors"
+	"reservation-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrWeeklyLimitReached is returned when a membership has used up its bookings for the week
+var ErrWeeklyLimitReached = errors.New("weekly booking limit reached")
+
+// MembershipRepository handles membership data operations
+type MembershipRepository struct {
+	db *gorm.DB
+}
+
+// NewMembershipRepository creates a new membership repository
+func NewMembershipRepository(db *gorm.DB) *MembershipRepository {
+	return &MembershipRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries in the given transaction
+func (r *MembershipRepository) WithTx(tx *gorm.DB) *MembershipRepository {
+	return &MembershipRepository{db: tx}
+}
+
+// Create creates a new membership
+func (r *MembershipRepository) Create(membership *models.Membership) error {
+	return r.db.Create(membership).Error
+}
+
+// FindByID finds a membership by ID with its plan and user
+func (r *MembershipRepository) FindByID(id uint) (*models.Membership, error) {
+	var membership models.Membership
+	err := r.db.Preload("Plan").
+		Preload("User").
+		First(&membership, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// FindByUserID finds all memberships of a user with their payments, newest first
+func (r *MembershipRepository) FindByUserID(userID uint) ([]models.Membership, error) {
+	var memberships []models.Membership
+	err := r.db.Preload("Plan").
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// FindCovering finds the membership of a user giving booking rights at the given time
+func (r *MembershipRepository) FindCovering(userID uint, at time.Time) (*models.Membership, error) {
+	var membership models.Membership
+	err := r.db.Preload("Plan").
+		Where("user_id = ? AND status = ? AND start_date <= ? AND end_date > ?",
+			userID, models.MembershipActive, at, at).
+		Order("end_date DESC").
+		First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// HasOpenMembership checks if a user has a membership that is pending, active or suspended
+func (r *MembershipRepository) HasOpenMembership(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Membership{}).
+		Where("user_id = ? AND status IN ?", userID, []models.MembershipStatus{
+			models.MembershipPending, models.MembershipActive, models.MembershipSuspended,
+		}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateAutoRenew turns auto-renewal of a membership on or off
+func (r *MembershipRepository) UpdateAutoRenew(id uint, autoRenew bool) error {
+	return r.db.Model(&models.Membership{}).
+		Where("id = ?", id).
+		Update("auto_renew", autoRenew).Error
+}
+
+// StopForUser stops all memberships of a user from renewing: auto-renewal is turned
+// off, memberships that are not paid up are cancelled and their pending payments
+// expired. A paid period is not taken back.
+func (r *MembershipRepository) StopForUser(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Membership{}).
+			Where("user_id = ?", userID).
+			Update("auto_renew", false).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Membership{}).
+			Where("user_id = ? AND status IN ?", userID, []models.MembershipStatus{
+				models.MembershipPending, models.MembershipSuspended,
+			}).
+			Update("status", models.MembershipCancelled).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Payment{}).
+			Where("status = ? AND membership_id IN (?)", models.PaymentPending,
+				tx.Model(&models.Membership{}).Select("id").Where("user_id = ?", userID)).
+			Update("status", models.PaymentExpired).Error
+	})
+}
+
+// FindDueForRenewal finds active auto-renewing memberships of active users whose
+// period ends before the cutoff and that have no renewal payment in progress
+func (r *MembershipRepository) FindDueForRenewal(cutoff time.Time) ([]models.Membership, error) {
+	var memberships []models.Membership
+	err := r.db.Preload("Plan").
+		Preload("User").
+		Joins("JOIN users ON users.id = memberships.user_id AND users.is_active = ? AND users.anonymized_at IS NULL", true).
+		Where("memberships.status = ? AND memberships.auto_renew = ? AND memberships.end_date <= ?",
+			models.MembershipActive, true, cutoff).
+		Where("NOT EXISTS (?)", r.db.Model(&models.Payment{}).
+			Select("1").
+			Where("payments.membership_id = memberships.id AND payments.status = ?", models.PaymentPending)).
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// FindLapsed finds active memberships whose paid period has ended
+func (r *MembershipRepository) FindLapsed(now time.Time) ([]models.Membership, error) {
+	var memberships []models.Membership
+	err := r.db.Where("status = ? AND end_date <= ?", models.MembershipActive, now).
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// Lapse ends a membership whose paid period is over: it is suspended when it was
+// meant to renew and expired otherwise. Returns false if it was renewed meanwhile.
+func (r *MembershipRepository) Lapse(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Membership{}).
+		Where("id = ? AND status = ? AND end_date <= ?", id, models.MembershipActive, now).
+		Update("status", gorm.Expr("CASE WHEN auto_renew THEN ? ELSE ? END",
+			models.MembershipSuspended, models.MembershipExpired))
+	return result.RowsAffected > 0, result.Error
+}
+
+// Extend applies a paid month to a membership and (re)activates it. The month
+// follows the current period when it has not ended yet and starts at paidAt
+// otherwise. Returns false if the membership was cancelled.
+func (r *MembershipRepository) Extend(id uint, paidAt time.Time) (bool, error) {
+	extended := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var membership models.Membership
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&membership, id).Error; err != nil {
+			return err
+		}
+		if membership.Status == models.MembershipCancelled {
+			return nil
+		}
+
+		// Continue from the paid period if it has not ended yet, otherwise restart
+		periodStart := paidAt
+		if membership.EndDate != nil && membership.EndDate.After(paidAt) {
+			periodStart = *membership.EndDate
+		} else {
+			membership.StartDate = &paidAt
+		}
+		endDate := periodStart.AddDate(0, 1, 0)
+
+		membership.EndDate = &endDate
+		membership.Status = models.MembershipActive
+		if err := tx.Omit(clause.Associations).Save(&membership).Error; err != nil {
+			return err
+		}
+
+		extended = true
+		return nil
+	})
+	return extended, err
+}
+
+// FailPayment handles an unpaid month: a membership still waiting for its first
+// payment is cancelled, and an active one is suspended. Failures of payments that
+// were superseded by a newer payment are ignored.
+func (r *MembershipRepository) FailPayment(id, paymentID uint) error {
+	return r.db.Model(&models.Membership{}).
+		Where("id = ? AND status IN ?", id, []models.MembershipStatus{
+			models.MembershipPending, models.MembershipActive,
+		}).
+		Where("NOT EXISTS (?)", r.db.Model(&models.Payment{}).
+			Select("1").
+			Where("payments.membership_id = ? AND payments.id > ?", id, paymentID)).
+		Update("status", gorm.Expr("CASE WHEN status = ? THEN ? ELSE ? END",
+			models.MembershipPending, models.MembershipCancelled, models.MembershipSuspended)).Error
+}
+
+// CheckWeeklyLimit locks a membership and checks that its bookings for classes in
+// [weekStart, weekEnd) do not exceed the limit. Meant to run in the booking
+// transaction after the new reservation is created, so it counts that booking too.
+func (r *MembershipRepository) CheckWeeklyLimit(id uint, limit int, weekStart, weekEnd time.Time) error {
+	var membership models.Membership
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&membership, id).Error; err != nil {
+		return err
+	}
+
+	if limit <= 0 {
+		return nil
+	}
+
+	var count int64
+	if err := r.db.Model(&models.Reservation{}).
+		Where("membership_id = ? AND status <> ? AND date >= ? AND date < ?",
+			id, models.StatusCancelled, weekStart, weekEnd).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if int(count) > limit {
+		return ErrWeeklyLimitReached
+	}
+	return nil
+}
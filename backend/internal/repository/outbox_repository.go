@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"time"
+
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository handles outgoing message data operations
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Enqueue stores a message for delivery. A message with the dedupe key of an already
+// queued message is dropped; returns false in that case.
+func (r *OutboxRepository) Enqueue(message *models.OutboxMessage) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(message)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByID finds a message by ID
+func (r *OutboxRepository) FindByID(id uint) (*models.OutboxMessage, error) {
+	var message models.OutboxMessage
+	err := r.db.First(&message, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// FindAll retrieves messages, newest first, optionally filtered by status and channel
+func (r *OutboxRepository) FindAll(status, channel string, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	query := r.db.Order("created_at DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	err := query.Find(&messages).Error
+	return messages, err
+}
+
+// ClaimDue claims up to limit pending messages that are due for delivery by pushing
+// their next attempt back by the lease. Claimed messages are not claimed again by
+// another worker until the lease runs out, e.g. if the worker stopped mid-send.
+func (r *OutboxRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := r.db.Raw(`
+		UPDATE outbox_messages SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_messages
+			WHERE status = ? AND next_attempt_at <= ? AND deleted_at IS NULL
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, models.OutboxPending, now, limit).
+		Scan(&messages).Error
+	return messages, err
+}
+
+// MarkSent records a successful delivery
+func (r *OutboxRepository) MarkSent(id uint, sentAt time.Time) error {
+	return r.db.Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.OutboxSent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"sent_at":    sentAt,
+			"last_error": "",
+		}).Error
+}
+
+// MarkAttemptFailed records a failed delivery. The message stays pending until
+// nextAttemptAt, or is marked failed when giveUp is set.
+func (r *OutboxRepository) MarkAttemptFailed(id uint, lastError string, nextAttemptAt time.Time, giveUp bool) error {
+	status := models.OutboxPending
+	if giveUp {
+		status = models.OutboxFailed
+	}
+	return r.db.Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error
+}
+
+// Requeue makes a failed message pending again with a fresh set of attempts.
+// Returns false if the message was not failed.
+func (r *OutboxRepository) Requeue(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.OutboxMessage{}).
+		Where("id = ? AND status = ?", id, models.OutboxFailed).
+		Updates(map[string]interface{}{
+			"status":          models.OutboxPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
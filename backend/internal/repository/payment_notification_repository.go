@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PaymentNotificationRepository handles payment notification audit records
+type PaymentNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentNotificationRepository creates a new payment notification repository
+func NewPaymentNotificationRepository(db *gorm.DB) *PaymentNotificationRepository {
+	return &PaymentNotificationRepository{db: db}
+}
+
+// Create creates a new payment notification record
+func (r *PaymentNotificationRepository) Create(notification *models.PaymentNotification) error {
+	return r.db.Create(notification).Error
+}
+
+// FindByOrderID finds all notifications received for an order
+func (r *PaymentNotificationRepository) FindByOrderID(orderID string) ([]models.PaymentNotification, error) {
+	var notifications []models.PaymentNotification
+	err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&notifications).Error
+	return notifications, err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"reservation-api/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -28,6 +29,8 @@ func (r *PaymentRepository) FindByID(id uint) (*models.Payment, error) {
 		Preload("Reservation.User").
 		Preload("Reservation.Court").
 		Preload("Reservation.Timeslot").
+		Preload("CreditPurchase.Package").
+		Preload("Membership.Plan").
 		First(&payment, id).Error
 	if err != nil {
 		return nil, err
@@ -35,10 +38,11 @@ func (r *PaymentRepository) FindByID(id uint) (*models.Payment, error) {
 	return &payment, nil
 }
 
-// FindByReservationID finds a payment by reservation ID
-func (r *PaymentRepository) FindByReservationID(reservationID uint) (*models.Payment, error) {
+// FindPaidByReservationID finds the paid payment of a reservation. A reservation can
+// have expired or failed payments before the one that was paid.
+func (r *PaymentRepository) FindPaidByReservationID(reservationID uint) (*models.Payment, error) {
 	var payment models.Payment
-	err := r.db.Where("reservation_id = ?", reservationID).First(&payment).Error
+	err := r.db.Where("reservation_id = ? AND status = ?", reservationID, models.PaymentPaid).First(&payment).Error
 	if err != nil {
 		return nil, err
 	}
@@ -67,4 +71,56 @@ func (r *PaymentRepository) CheckPaidByReservationID(reservationID uint) (bool,
 		Where("reservation_id = ? AND status = ?", reservationID, models.PaymentPaid).
 		Count(&count).Error
 	return count > 0, err
-}
\ No newline at end of file
+}
+
+// RecordLateSettlement marks a payment that had expired or failed as paid, when the
+// gateway settles it after all. Returns false if it was not expired or failed, so a
+// late payment is only recorded once.
+func (r *PaymentRepository) RecordLateSettlement(id uint, paidAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Payment{}).
+		Where("id = ? AND status IN ?", id, []models.PaymentStatus{models.PaymentExpired, models.PaymentFailed}).
+		Updates(map[string]interface{}{
+			"status":  models.PaymentPaid,
+			"paid_at": paidAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SettlePending moves a pending payment to a final status and updates its pending
+// reservation (if any) accordingly, in one transaction. Returns false if the payment was no
+// longer pending, so replayed or late notifications are not applied twice.
+func (r *PaymentRepository) SettlePending(
+	payment *models.Payment,
+	status models.PaymentStatus,
+	paidAt *time.Time,
+	reservationStatus models.ReservationStatus,
+) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Payment{}).
+			Where("id = ? AND status = ?", payment.ID, models.PaymentPending).
+			Updates(map[string]interface{}{
+				"status":         status,
+				"paid_at":        paidAt,
+				"payment_method": payment.PaymentMethod,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if payment.ReservationID != nil {
+			if err := tx.Model(&models.Reservation{}).
+				Where("id = ? AND status = ?", *payment.ReservationID, models.StatusPending).
+				Update("status", reservationStatus).Error; err != nil {
+				return err
+			}
+		}
+
+		applied = true
+		return nil
+	})
+	return applied, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PricingRuleRepository handles pricing rule data operations
+type PricingRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewPricingRuleRepository creates a new pricing rule repository
+func NewPricingRuleRepository(db *gorm.DB) *PricingRuleRepository {
+	return &PricingRuleRepository{db: db}
+}
+
+// Create creates a new pricing rule
+func (r *PricingRuleRepository) Create(rule *models.PricingRule) error {
+	return r.db.Create(rule).Error
+}
+
+// FindAll retrieves all pricing rules ordered by priority
+func (r *PricingRuleRepository) FindAll() ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	err := r.db.Order("priority ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindActive retrieves active pricing rules ordered by priority
+func (r *PricingRuleRepository) FindActive() ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	err := r.db.Where("is_active = ?", true).Order("priority ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindByID finds a pricing rule by ID
+func (r *PricingRuleRepository) FindByID(id uint) (*models.PricingRule, error) {
+	var rule models.PricingRule
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update updates a pricing rule
+func (r *PricingRuleRepository) Update(rule *models.PricingRule) error {
+	return r.db.Save(rule).Error
+}
+
+// Delete soft deletes a pricing rule
+func (r *PricingRuleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.PricingRule{}, id).Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RefundRepository handles refund data operations
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository creates a new refund repository
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+// Create creates a new refund. A refund with the refund key of an existing refund is
+// dropped; returns false in that case.
+func (r *RefundRepository) Create(refund *models.Refund) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(refund)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByID finds a refund by ID with its payment
+func (r *RefundRepository) FindByID(id uint) (*models.Refund, error) {
+	var refund models.Refund
+	err := r.db.Preload("Payment").First(&refund, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// FindAll retrieves all refunds, newest first
+func (r *RefundRepository) FindAll() ([]models.Refund, error) {
+	var refunds []models.Refund
+	err := r.db.Preload("Payment").Order("created_at DESC").Find(&refunds).Error
+	return refunds, err
+}
+
+// Update updates a refund
+func (r *RefundRepository) Update(refund *models.Refund) error {
+	return r.db.Save(refund).Error
+}
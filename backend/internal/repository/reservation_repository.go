@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"errors"
 	"reservation-api/internal/models"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrClassFull is returned when a class has no seats left
+var ErrClassFull = errors.New("class is full")
+
 // ReservationRepository handles reservation data operations
 type ReservationRepository struct {
 	db *gorm.DB
@@ -22,13 +27,145 @@ func (r *ReservationRepository) Create(reservation *models.Reservation) error {
 	return r.db.Create(reservation).Error
 }
 
+// SeatCount holds the number of seats taken in a class, split by reservation status
+type SeatCount struct {
+	CourtID    uint
+	TimeslotID uint
+	Booked     int
+	Held       int
+}
+
+// CreateWithinCapacity creates a reservation in its session only if the session is
+// scheduled and still has a free seat. The session row is locked for the duration
+// of the transaction so concurrent bookings for the same class are serialized and
+// the capacity cannot be exceeded. If onCreate is given it runs in the same
+// transaction after the reservation is created, and an error from it rolls the
+// booking back.
+func (r *ReservationRepository) CreateWithinCapacity(reservation *models.Reservation, onCreate func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		session, free, err := lockSession(tx, *reservation.SessionID)
+		if err != nil {
+			return err
+		}
+		if session.IsCancelled() {
+			return ErrSessionCancelled
+		}
+		if free <= 0 {
+			return ErrClassFull
+		}
+
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+
+		if onCreate != nil {
+			return onCreate(tx)
+		}
+		return nil
+	})
+}
+
+// lockSession locks a session row and counts its free seats. Bookings and waitlist
+// promotions of a class lock the same row, so they see each other's seats.
+func lockSession(tx *gorm.DB, conds ...interface{}) (*models.ClassSession, int, error) {
+	var session models.ClassSession
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&session, conds...).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if session.Capacity == nil {
+		var court models.Court
+		if err := tx.First(&court, session.CourtID).Error; err != nil {
+			return nil, 0, err
+		}
+		session.Court = &court
+	}
+
+	counts, err := countSeats(tx.Where("court_id = ? AND timeslot_id = ?",
+		session.CourtID, session.TimeslotID), session.Date)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	free := session.SeatCapacity()
+	for _, count := range counts {
+		free -= count.Booked + count.Held
+	}
+	return &session, free, nil
+}
+
+// CountSeatsByDate counts taken seats for every court and timeslot on a date
+func (r *ReservationRepository) CountSeatsByDate(date time.Time) ([]SeatCount, error) {
+	return countSeats(r.db, date)
+}
+
+// CountSeats counts taken seats for a single class
+func (r *ReservationRepository) CountSeats(courtID, timeslotID uint, date time.Time) (SeatCount, error) {
+	counts, err := countSeats(r.db.Where("court_id = ? AND timeslot_id = ?", courtID, timeslotID), date)
+	if err != nil {
+		return SeatCount{}, err
+	}
+
+	result := SeatCount{CourtID: courtID, TimeslotID: timeslotID}
+	for _, count := range counts {
+		result.Booked += count.Booked
+		result.Held += count.Held
+	}
+	return result, nil
+}
+
+// countSeats groups active reservations on a date by class. Confirmed reservations
+// are booked seats, pending reservations are seats held while awaiting payment.
+func countSeats(db *gorm.DB, date time.Time) ([]SeatCount, error) {
+	var rows []struct {
+		CourtID    uint
+		TimeslotID uint
+		Status     models.ReservationStatus
+		Count      int
+	}
+
+	err := db.Model(&models.Reservation{}).
+		Select("court_id, timeslot_id, status, COUNT(*) AS count").
+		Where("date = ? AND status IN ?", date,
+			[]models.ReservationStatus{models.StatusPending, models.StatusConfirmed}).
+		Group("court_id, timeslot_id, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[[2]uint]int)
+	var counts []SeatCount
+	for _, row := range rows {
+		key := [2]uint{row.CourtID, row.TimeslotID}
+		i, ok := index[key]
+		if !ok {
+			i = len(counts)
+			index[key] = i
+			counts = append(counts, SeatCount{CourtID: row.CourtID, TimeslotID: row.TimeslotID})
+		}
+
+		if row.Status == models.StatusConfirmed {
+			counts[i].Booked += row.Count
+		} else {
+			counts[i].Held += row.Count
+		}
+	}
+
+	return counts, nil
+}
+
 // FindByID finds a reservation by ID with relations
 func (r *ReservationRepository) FindByID(id uint) (*models.Reservation, error) {
 	var reservation models.Reservation
 	err := r.db.Preload("User").
 		Preload("Court").
 		Preload("Timeslot").
+		Preload("Session").
+		Preload("Session.Instructor").
 		Preload("Payment").
+		Preload("Payment.Refunds").
 		First(&reservation, id).Error
 	if err != nil {
 		return nil, err
@@ -48,51 +185,168 @@ func (r *ReservationRepository) FindByUserID(userID uint) ([]models.Reservation,
 	return reservations, err
 }
 
-// Update updates a reservation
-func (r *ReservationRepository) Update(reservation *models.Reservation) error {
-	return r.db.Save(reservation).Error
+// FindByClass finds the reservations of a class that hold or held a seat with their
+// users: pending, confirmed, completed and no-show
+func (r *ReservationRepository) FindByClass(courtID, timeslotID uint, date time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Preload("User").
+		Where("court_id = ? AND timeslot_id = ? AND date = ? AND status IN ?",
+			courtID, timeslotID, date,
+			[]models.ReservationStatus{models.StatusPending, models.StatusConfirmed,
+				models.StatusCompleted, models.StatusNoShow}).
+		Order("created_at ASC").
+		Find(&reservations).Error
+	return reservations, err
 }
 
-// CheckAvailability checks if a court has available capacity for a specific date and timeslot
-func (r *ReservationRepository) CheckAvailability(courtID, timeslotID uint, date time.Time) (bool, int, error) {
-	// Count ONLY CONFIRMED bookings (exclude pending & cancelled)
-	var bookedCount int64
-	err := r.db.Model(&models.Reservation{}).
-		Where("court_id = ? AND timeslot_id = ? AND date = ? AND status = ?",
-			courtID, timeslotID, date, models.StatusConfirmed).
-		Count(&bookedCount).Error
+// FindUnsettled finds confirmed reservations on or before a date with their timeslots.
+// Their attendance is settled once their class ends.
+func (r *ReservationRepository) FindUnsettled(date time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Preload("Timeslot").
+		Where("status = ? AND date <= ?", models.StatusConfirmed, date).
+		Find(&reservations).Error
+	return reservations, err
+}
 
-	if err != nil {
-		return false, 0, err
-	}
+// FindConfirmedBetween finds confirmed reservations dated from one date through another
+// with their users, courts, timeslots and sessions
+func (r *ReservationRepository) FindConfirmedBetween(from, to time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Preload("User").
+		Preload("Court").
+		Preload("Timeslot").
+		Preload("Session").
+		Preload("Session.Instructor").
+		Where("status = ? AND date >= ? AND date <= ?", models.StatusConfirmed, from, to).
+		Find(&reservations).Error
+	return reservations, err
+}
 
-	return true, int(bookedCount), nil
+// SettleAttendance marks confirmed reservations completed when checked in and no-show
+// otherwise. Returns the number of each.
+func (r *ReservationRepository) SettleAttendance(ids []uint) (completed, noShows int64, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("id IN ? AND status = ? AND checked_in_at IS NOT NULL", ids, models.StatusConfirmed).
+			Update("status", models.StatusCompleted)
+		if result.Error != nil {
+			return result.Error
+		}
+		completed = result.RowsAffected
+
+		result = tx.Model(&models.Reservation{}).
+			Where("id IN ? AND status = ? AND checked_in_at IS NULL", ids, models.StatusConfirmed).
+			Update("status", models.StatusNoShow)
+		if result.Error != nil {
+			return result.Error
+		}
+		noShows = result.RowsAffected
+		return nil
+	})
+	return completed, noShows, err
 }
 
-// CountBookedByDateAndTimeslot counts booked reservations for a date and timeslot
-func (r *ReservationRepository) CountBookedByDateAndTimeslot(date time.Time, timeslotID uint) (int64, error) {
+// CountNoShows counts the no-shows of a user in classes on or after a date
+func (r *ReservationRepository) CountNoShows(userID uint, since time.Time) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.Reservation{}).
-		Where("date = ? AND timeslot_id = ? AND status != ?",
-			date, timeslotID, models.StatusCancelled).
+		Where("user_id = ? AND status = ? AND date >= ?", userID, models.StatusNoShow, since).
 		Count(&count).Error
 	return count, err
 }
 
-// GetBookedCourtIDs gets list of booked court IDs for a specific date and timeslot
-func (r *ReservationRepository) GetBookedCourtIDs(date time.Time, timeslotID uint) ([]uint, error) {
-	var courtIDs []uint
+// HasActiveReservation checks if a user holds a pending or confirmed seat in a class
+func (r *ReservationRepository) HasActiveReservation(userID, courtID, timeslotID uint, date time.Time) (bool, error) {
+	var count int64
 	err := r.db.Model(&models.Reservation{}).
-		Where("date = ? AND timeslot_id = ? AND status != ?",
-			date, timeslotID, models.StatusCancelled).
-		Pluck("court_id", &courtIDs).Error
-	return courtIDs, err
+		Where("user_id = ? AND court_id = ? AND timeslot_id = ? AND date = ? AND status IN ?",
+			userID, courtID, timeslotID, date,
+			[]models.ReservationStatus{models.StatusPending, models.StatusConfirmed}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update updates a reservation
+func (r *ReservationRepository) Update(reservation *models.Reservation) error {
+	return r.db.Save(reservation).Error
 }
 
-// GetUpcomingReservations gets upcoming reservations
-func (r *ReservationRepository) GetUpcomingReservations(userID uint) ([]models.Reservation, error) {
+// Cancel cancels a pending or confirmed reservation that is not checked in, and
+// expires its pending payment in one transaction, so a payment completed afterwards
+// is not applied to the booking. Returns false if the reservation could no longer be
+// cancelled, e.g. because a concurrent request cancelled it first.
+func (r *ReservationRepository) Cancel(reservation *models.Reservation) (bool, error) {
+	cancelled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status IN ? AND checked_in_at IS NULL", reservation.ID,
+				[]models.ReservationStatus{models.StatusPending, models.StatusConfirmed}).
+			Updates(map[string]interface{}{
+				"status":      models.StatusCancelled,
+				"late_cancel": reservation.LateCancel,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		cancelled = true
+		return tx.Model(&models.Payment{}).
+			Where("reservation_id = ? AND status = ?", reservation.ID, models.PaymentPending).
+			Update("status", models.PaymentExpired).Error
+	})
+	return cancelled, err
+}
+
+// FindExpiredHolds finds pending reservations whose seat hold has run out: either the
+// pending payment has passed its expiry, or no payment has an expiry and the
+// reservation's own hold has passed (its HoldUntil, or creation before holdCutoff)
+func (r *ReservationRepository) FindExpiredHolds(now, holdCutoff time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.
+		Joins("LEFT JOIN payments ON payments.reservation_id = reservations.id AND payments.deleted_at IS NULL").
+		Where("reservations.status = ?", models.StatusPending).
+		Where("payments.id IS NULL OR payments.status = ?", models.PaymentPending).
+		Where("(payments.expired_at IS NOT NULL AND payments.expired_at <= ?) OR "+
+			"(payments.expired_at IS NULL AND ((reservations.hold_until IS NULL AND reservations.created_at <= ?) OR reservations.hold_until <= ?))",
+			now, holdCutoff, now).
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// ExpireHold cancels a pending reservation and expires its pending payment.
+// Returns false if the reservation was no longer pending (e.g. paid meanwhile).
+func (r *ReservationRepository) ExpireHold(reservationID uint) (bool, error) {
+	expired := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ?", reservationID, models.StatusPending).
+			Update("status", models.StatusCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Payment{}).
+			Where("reservation_id = ? AND status = ?", reservationID, models.PaymentPending).
+			Update("status", models.PaymentExpired).Error; err != nil {
+			return err
+		}
+
+		expired = true
+		return nil
+	})
+	return expired, err
+}
+
+// GetUpcomingReservations gets reservations on or after today, given as a calendar date
+func (r *ReservationRepository) GetUpcomingReservations(userID uint, today time.Time) ([]models.Reservation, error) {
 	var reservations []models.Reservation
-	today := time.Now().Truncate(24 * time.Hour)
 
 	err := r.db.Preload("Court").
 		Preload("Timeslot").
@@ -104,10 +358,9 @@ func (r *ReservationRepository) GetUpcomingReservations(userID uint) ([]models.R
 	return reservations, err
 }
 
-// GetPastReservations gets past reservations
-func (r *ReservationRepository) GetPastReservations(userID uint) ([]models.Reservation, error) {
+// GetPastReservations gets reservations before today, given as a calendar date
+func (r *ReservationRepository) GetPastReservations(userID uint, today time.Time) ([]models.Reservation, error) {
 	var reservations []models.Reservation
-	today := time.Now().Truncate(24 * time.Hour)
 
 	err := r.db.Preload("Court").
 		Preload("Timeslot").
@@ -117,4 +370,4 @@ func (r *ReservationRepository) GetPastReservations(userID uint) ([]models.Reser
 		Find(&reservations).Error
 
 	return reservations, err
-}
\ No newline at end of file
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"reservation-api/internal/models"
+	"reservation-api/internal/testdb"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreateWithinCapacityConcurrent(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewReservationRepository(db)
+
+	court := models.Court{Name: "Reformer", Capacity: 5, IsActive: true}
+	timeslot := models.Timeslot{Time: "07:00", Duration: 60, IsActive: true}
+	if err := db.Create(&court).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&timeslot).Error; err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	session := models.ClassSession{CourtID: court.ID, TimeslotID: timeslot.ID, Date: date, Status: models.SessionScheduled}
+	if err := db.Create(&session).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	const attempts = 20
+	users := make([]models.User, attempts)
+	for i := range users {
+		users[i] = models.User{Name: "Member", Email: fmt.Sprintf("member%d@example.com", i), Password: "x"}
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		created  int
+		full     int
+		failures []error
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(user models.User) {
+			defer wg.Done()
+			reservation := &models.Reservation{
+				UserID:     user.ID,
+				SessionID:  &session.ID,
+				CourtID:    court.ID,
+				TimeslotID: timeslot.ID,
+				Date:       date,
+				Status:     models.StatusPending,
+			}
+			err := repo.CreateWithinCapacity(reservation, nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, ErrClassFull):
+				full++
+			default:
+				failures = append(failures, err)
+			}
+		}(users[i])
+	}
+	wg.Wait()
+
+	for _, err := range failures {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	session.Court = &court
+	var active int64
+	if err := db.Model(&models.Reservation{}).
+		Where("session_id = ? AND status IN ?", session.ID,
+			[]models.ReservationStatus{models.StatusPending, models.StatusConfirmed}).
+		Count(&active).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if int(active) != session.SeatCapacity() {
+		t.Errorf("active reservations = %d, want %d", active, session.SeatCapacity())
+	}
+	if created != session.SeatCapacity() || full != attempts-session.SeatCapacity() {
+		t.Errorf("created %d and refused %d, want %d and %d",
+			created, full, session.SeatCapacity(), attempts-session.SeatCapacity())
+	}
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"errors"
+	"reservation-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSessionCancelled is returned when booking a seat in a cancelled session
+var ErrSessionCancelled = errors.New("class session is cancelled")
+
+// SessionRepository handles class template and class session data operations
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// CreateTemplate creates a new class template
+func (r *SessionRepository) CreateTemplate(template *models.ClassTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// FindTemplates retrieves all class templates ordered by weekday, timeslot and court
+func (r *SessionRepository) FindTemplates() ([]models.ClassTemplate, error) {
+	var templates []models.ClassTemplate
+	err := r.db.Preload("Court").
+		Preload("Timeslot").
+		Preload("Instructor").
+		Joins("JOIN timeslots ON timeslots.id = class_templates.timeslot_id").
+		Order("class_templates.weekday ASC, timeslots.time ASC, class_templates.court_id ASC").
+		Find(&templates).Error
+	return templates, err
+}
+
+// FindTemplateByID finds a class template by ID
+func (r *SessionRepository) FindTemplateByID(id uint) (*models.ClassTemplate, error) {
+	var template models.ClassTemplate
+	err := r.db.First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// TemplateExists checks if another template already schedules a court and timeslot on a weekday
+func (r *SessionRepository) TemplateExists(courtID, timeslotID uint, weekday int, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ClassTemplate{}).
+		Where("court_id = ? AND timeslot_id = ? AND weekday = ? AND id <> ?", courtID, timeslotID, weekday, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateTemplate updates a class template
+func (r *SessionRepository) UpdateTemplate(template *models.ClassTemplate) error {
+	return r.db.Omit(clause.Associations).Save(template).Error
+}
+
+// DeleteTemplate deletes a class template. Sessions already generated from it are kept.
+func (r *SessionRepository) DeleteTemplate(id uint) error {
+	return r.db.Unscoped().Delete(&models.ClassTemplate{}, id).Error
+}
+
+// GenerateFromTemplates creates the sessions of active templates for every date in
+// [from, to) whose court and timeslot are active, skipping classes for which closed
+// returns true. Sessions that already exist, including cancelled ones, are left
+// untouched. Returns the number created.
+func (r *SessionRepository) GenerateFromTemplates(from, to time.Time, closed func(courtID, timeslotID uint, date time.Time) bool) (int64, error) {
+	var templates []models.ClassTemplate
+	err := r.db.
+		Joins("JOIN courts ON courts.id = class_templates.court_id AND courts.deleted_at IS NULL").
+		Joins("JOIN timeslots ON timeslots.id = class_templates.timeslot_id AND timeslots.deleted_at IS NULL").
+		Where("class_templates.is_active = ? AND courts.is_active = ? AND timeslots.is_active = ?", true, true, true).
+		Find(&templates).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var sessions []models.ClassSession
+	for date := from; date.Before(to); date = date.AddDate(0, 0, 1) {
+		for i := range templates {
+			template := &templates[i]
+			if template.Weekday != int(date.Weekday()) || closed(template.CourtID, template.TimeslotID, date) {
+				continue
+			}
+			sessions = append(sessions, models.ClassSession{
+				CourtID:      template.CourtID,
+				TimeslotID:   template.TimeslotID,
+				Date:         date,
+				TemplateID:   &template.ID,
+				Status:       models.SessionScheduled,
+				InstructorID: template.InstructorID,
+				Capacity:     template.Capacity,
+				Price:        template.Price,
+			})
+		}
+	}
+
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&sessions, 100)
+	return result.RowsAffected, result.Error
+}
+
+// Create creates a new class session
+func (r *SessionRepository) Create(session *models.ClassSession) error {
+	return r.db.Create(session).Error
+}
+
+// Update updates a class session
+func (r *SessionRepository) Update(session *models.ClassSession) error {
+	return r.db.Omit(clause.Associations).Save(session).Error
+}
+
+// FindByID finds a class session by ID with relations
+func (r *SessionRepository) FindByID(id uint) (*models.ClassSession, error) {
+	var session models.ClassSession
+	err := r.withRelations().First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByClass finds the session of a court and timeslot on a date
+func (r *SessionRepository) FindByClass(courtID, timeslotID uint, date time.Time) (*models.ClassSession, error) {
+	var session models.ClassSession
+	err := r.withRelations().
+		Where("court_id = ? AND timeslot_id = ? AND date = ?", courtID, timeslotID, date).
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByDate finds all sessions on a date, including cancelled ones
+func (r *SessionRepository) FindByDate(date time.Time) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := r.withRelations().
+		Joins("JOIN timeslots ON timeslots.id = class_sessions.timeslot_id").
+		Where("class_sessions.date = ?", date).
+		Order("timeslots.time ASC, class_sessions.court_id ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// FindByInstructor finds the sessions an instructor teaches on a date
+func (r *SessionRepository) FindByInstructor(instructorID uint, date time.Time) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := r.withRelations().
+		Joins("JOIN timeslots ON timeslots.id = class_sessions.timeslot_id").
+		Where("class_sessions.instructor_id = ? AND class_sessions.date = ?", instructorID, date).
+		Order("timeslots.time ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// FindScheduledFrom finds the scheduled sessions on or after a date with their timeslot
+func (r *SessionRepository) FindScheduledFrom(date time.Time) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := r.db.Preload("Timeslot").
+		Where("date >= ? AND status = ?", date, models.SessionScheduled).
+		Order("date ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// FindUpcoming finds the scheduled sessions on or after a date with relations,
+// ordered by date and time
+func (r *SessionRepository) FindUpcoming(date time.Time) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := r.withRelations().
+		Joins("JOIN timeslots ON timeslots.id = class_sessions.timeslot_id").
+		Where("class_sessions.date >= ? AND class_sessions.status = ?", date, models.SessionScheduled).
+		Order("class_sessions.date ASC, timeslots.time ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// FindByClosure finds the sessions cancelled by a closure
+func (r *SessionRepository) FindByClosure(closureID uint) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := r.db.Where("closure_id = ? AND status = ?", closureID, models.SessionCancelled).
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Reopen schedules a session cancelled by a closure again. Its cancelled
+// reservations stay cancelled.
+func (r *SessionRepository) Reopen(id uint) error {
+	return r.db.Model(&models.ClassSession{}).
+		Where("id = ? AND status = ?", id, models.SessionCancelled).
+		Updates(map[string]interface{}{
+			"status":        models.SessionScheduled,
+			"cancel_reason": "",
+			"closure_id":    nil,
+		}).Error
+}
+
+// Cancel cancels a scheduled session together with its pending and confirmed
+// reservations. Pending payments of those reservations are expired and open
+// waitlist entries are closed. closureID is recorded when a closure cancels the
+// session. Returns the reservations that were cancelled, with the status they had
+// before, or nil if the session was not scheduled.
+func (r *SessionRepository) Cancel(id uint, reason string, closureID *uint) ([]models.Reservation, error) {
+	var cancelled []models.Reservation
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var session models.ClassSession
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&session, id).Error; err != nil {
+			return err
+		}
+		if session.IsCancelled() {
+			return nil
+		}
+
+		if err := tx.Model(&session).Updates(map[string]interface{}{
+			"status":        models.SessionCancelled,
+			"cancel_reason": reason,
+			"closure_id":    closureID,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Preload("Timeslot").
+			Where("session_id = ? AND status IN ?", id,
+				[]models.ReservationStatus{models.StatusPending, models.StatusConfirmed}).
+			Find(&cancelled).Error; err != nil {
+			return err
+		}
+
+		if len(cancelled) > 0 {
+			ids := make([]uint, len(cancelled))
+			for i := range cancelled {
+				ids[i] = cancelled[i].ID
+			}
+
+			if err := tx.Model(&models.Reservation{}).
+				Where("id IN ?", ids).
+				Update("status", models.StatusCancelled).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&models.Payment{}).
+				Where("reservation_id IN ? AND status = ?", ids, models.PaymentPending).
+				Update("status", models.PaymentExpired).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.WaitlistEntry{}).
+			Where("court_id = ? AND timeslot_id = ? AND date = ? AND status IN ?",
+				session.CourtID, session.TimeslotID, session.Date,
+				[]models.WaitlistStatus{models.WaitlistWaiting, models.WaitlistOffered}).
+			Update("status", models.WaitlistExpired).Error
+	})
+	return cancelled, err
+}
+
+// withRelations preloads the relations shown with a session
+func (r *SessionRepository) withRelations() *gorm.DB {
+	return r.db.Preload("Court").
+		Preload("Timeslot").
+		Preload("Instructor")
+}
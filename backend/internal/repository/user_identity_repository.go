@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository handles the provider identities linked to users
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository creates a new user identity repository
+func NewUserIdentityRepository(db *gorm.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+// Create links an identity to a user
+func (r *UserIdentityRepository) Create(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// FindBySubject finds the identity of a provider user with the linked user
+func (r *UserIdentityRepository) FindBySubject(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.Preload("User").
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// DeleteByUserID permanently unlinks every identity of a user
+func (r *UserIdentityRepository) DeleteByUserID(userID uint) error {
+	return r.db.Unscoped().Where("user_id = ?", userID).Delete(&models.UserIdentity{}).Error
+}
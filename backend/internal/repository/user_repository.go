@@ -31,6 +31,13 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// FindAll retrieves all users
+func (r *UserRepository) FindAll() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Order("created_at DESC").Find(&users).Error
+	return users, err
+}
+
 // FindByID finds a user by ID
 func (r *UserRepository) FindByID(id uint) (*models.User, error) {
 	var user models.User
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"reservation-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// UserTokenRepository handles password reset and email verification tokens
+type UserTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository creates a new user token repository
+func NewUserTokenRepository(db *gorm.DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// Replace creates a token, invalidating the unused tokens of the user with the same
+// purpose so only the latest one works
+func (r *UserTokenRepository) Replace(token *models.UserToken, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// InvalidateAll marks every unused token of a user used, e.g. when the email address
+// they were sent to no longer belongs to the account
+func (r *UserTokenRepository) InvalidateAll(userID uint, now time.Time) error {
+	return r.db.Model(&models.UserToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error
+}
+
+// FindByHash finds a token of a purpose by its hash with its user
+func (r *UserTokenRepository) FindByHash(tokenHash string, purpose models.UserTokenPurpose) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.Preload("User").
+		Where("token_hash = ? AND purpose = ?", tokenHash, purpose).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindLatest finds the most recently created token of a user with a purpose
+func (r *UserTokenRepository) FindLatest(userID uint, purpose models.UserTokenPurpose) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.Where("user_id = ? AND purpose = ?", userID, purpose).
+		Order("created_at DESC").
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume marks a token used. Returns false if it was already used, so a token can
+// only be redeemed once even by concurrent requests.
+func (r *UserTokenRepository) Consume(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	return result.RowsAffected > 0, result.Error
+}
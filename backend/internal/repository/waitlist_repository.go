@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"reservation-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WaitlistRepository handles waitlist data operations
+type WaitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+// Create creates a new waitlist entry
+func (r *WaitlistRepository) Create(entry *models.WaitlistEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// FindByID finds a waitlist entry by ID with relations
+func (r *WaitlistRepository) FindByID(id uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := r.db.Preload("Court").
+		Preload("Timeslot").
+		Preload("Reservation").
+		First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindByUserID finds all waitlist entries of a user, soonest class first
+func (r *WaitlistRepository) FindByUserID(userID uint) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.Preload("Court").
+		Preload("Timeslot").
+		Preload("Reservation").
+		Where("user_id = ?", userID).
+		Order("date ASC, created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// HasOpenEntry checks if a user is already waiting for, or holding an offer in, a class
+func (r *WaitlistRepository) HasOpenEntry(userID, courtID, timeslotID uint, date time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.WaitlistEntry{}).
+		Where("user_id = ? AND court_id = ? AND timeslot_id = ? AND date = ? AND status IN ?",
+			userID, courtID, timeslotID, date,
+			[]models.WaitlistStatus{models.WaitlistWaiting, models.WaitlistOffered}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Position gets the 1-based place in line of a waiting entry
+func (r *WaitlistRepository) Position(entry *models.WaitlistEntry) (int, error) {
+	var ahead int64
+	err := r.db.Model(&models.WaitlistEntry{}).
+		Where("court_id = ? AND timeslot_id = ? AND date = ? AND status = ? AND id < ?",
+			entry.CourtID, entry.TimeslotID, entry.Date, models.WaitlistWaiting, entry.ID).
+		Count(&ahead).Error
+	return int(ahead) + 1, err
+}
+
+// PromoteNext fills the free seats of a class from its waitlist in FIFO order. Each
+// promoted entry gets a pending reservation held until claimUntil. Capacity is
+// checked under the same session lock as new bookings. Cancelled sessions promote
+// nobody.
+func (r *WaitlistRepository) PromoteNext(courtID, timeslotID uint, date, now, claimUntil time.Time) ([]models.WaitlistEntry, error) {
+	var promoted []models.WaitlistEntry
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		session, free, err := lockSession(tx, "court_id = ? AND timeslot_id = ? AND date = ?", courtID, timeslotID, date)
+		if err != nil {
+			return err
+		}
+		if session.IsCancelled() {
+			return nil
+		}
+
+		for ; free > 0; free-- {
+			var entry models.WaitlistEntry
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("court_id = ? AND timeslot_id = ? AND date = ? AND status = ?",
+					courtID, timeslotID, date, models.WaitlistWaiting).
+				Order("id ASC").
+				Limit(1).
+				Find(&entry).Error
+			if err != nil {
+				return err
+			}
+			if entry.ID == 0 {
+				break
+			}
+
+			reservation := &models.Reservation{
+				UserID:     entry.UserID,
+				SessionID:  &session.ID,
+				CourtID:    courtID,
+				TimeslotID: timeslotID,
+				Date:       date,
+				Status:     models.StatusPending,
+				Notes:      "Promoted from waitlist",
+				HoldUntil:  &claimUntil,
+			}
+			if err := tx.Create(reservation).Error; err != nil {
+				return err
+			}
+
+			entry.Status = models.WaitlistOffered
+			entry.ReservationID = &reservation.ID
+			entry.OfferedAt = &now
+			entry.OfferExpiresAt = &claimUntil
+			if err := tx.Omit(clause.Associations).Save(&entry).Error; err != nil {
+				return err
+			}
+
+			promoted = append(promoted, entry)
+		}
+
+		return nil
+	})
+	return promoted, err
+}
+
+// CloseOffer moves the offered entry holding a reservation to the given status
+func (r *WaitlistRepository) CloseOffer(reservationID uint, status models.WaitlistStatus) error {
+	return r.db.Model(&models.WaitlistEntry{}).
+		Where("reservation_id = ? AND status = ?", reservationID, models.WaitlistOffered).
+		Update("status", status).Error
+}
+
+// Leave takes an open entry off the waitlist. An offered seat is given up by
+// cancelling its pending reservation. Returns true if a seat was released.
+func (r *WaitlistRepository) Leave(id uint) (bool, error) {
+	released := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var entry models.WaitlistEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&entry, id).Error; err != nil {
+			return err
+		}
+		if !entry.IsOpen() {
+			return nil
+		}
+
+		if entry.Status == models.WaitlistOffered && entry.ReservationID != nil {
+			result := tx.Model(&models.Reservation{}).
+				Where("id = ? AND status = ?", *entry.ReservationID, models.StatusPending).
+				Update("status", models.StatusCancelled)
+			if result.Error != nil {
+				return result.Error
+			}
+			if err := tx.Model(&models.Payment{}).
+				Where("reservation_id = ? AND status = ?", *entry.ReservationID, models.PaymentPending).
+				Update("status", models.PaymentExpired).Error; err != nil {
+				return err
+			}
+			released = result.RowsAffected > 0
+		}
+
+		return tx.Model(&entry).Update("status", models.WaitlistLeft).Error
+	})
+	return released, err
+}
+
+// ExpireWaiting expires entries still waiting for classes before the given date
+func (r *WaitlistRepository) ExpireWaiting(before time.Time) (int64, error) {
+	result := r.db.Model(&models.WaitlistEntry{}).
+		Where("status = ? AND date < ?", models.WaitlistWaiting, before).
+		Update("status", models.WaitlistExpired)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Task is a unit of background work run periodically by the scheduler
+type Task func(ctx context.Context) error
+
+// job is a task registered with its name and run interval
+type job struct {
+	name     string
+	interval time.Duration
+	task     Task
+}
+
+// Scheduler runs registered tasks at fixed intervals until stopped
+type Scheduler struct {
+	jobs   []job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new scheduler
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a task to run at the given interval. Must be called before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, task Task) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, task: task})
+}
+
+// Start runs every registered task in its own goroutine until ctx is cancelled or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.run(ctx, j)
+	}
+
+	log.Printf("⏱️  Scheduler started with %d job(s)", len(s.jobs))
+}
+
+// Stop cancels all tasks and waits for running ones to finish
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	log.Println("✅ Scheduler stopped")
+}
+
+// run executes a job immediately and then on every tick
+func (s *Scheduler) run(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.task(ctx); err != nil {
+			log.Printf("⚠️  Job %s failed: %v", j.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
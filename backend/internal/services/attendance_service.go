@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceService handles check-ins, settling attendance after classes and
+// no-show booking restrictions
+type AttendanceService struct {
+	reservationRepo *repository.ReservationRepository
+	sessionRepo     *repository.SessionRepository
+	ticketService   *TicketService
+	config          *config.Config
+	clock           utils.Clock
+}
+
+// NewAttendanceService creates a new attendance service
+func NewAttendanceService(
+	reservationRepo *repository.ReservationRepository,
+	sessionRepo *repository.SessionRepository,
+	ticketService *TicketService,
+	cfg *config.Config,
+	clock utils.Clock,
+) *AttendanceService {
+	return &AttendanceService{
+		reservationRepo: reservationRepo,
+		sessionRepo:     sessionRepo,
+		ticketService:   ticketService,
+		config:          cfg,
+		clock:           clock,
+	}
+}
+
+// GetSessionRoster gets the attendees of a session with their check-in status
+func (s *AttendanceService) GetSessionRoster(sessionID uint) (*dto.RosterClass, error) {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+
+	reservations, err := s.reservationRepo.FindByClass(session.CourtID, session.TimeslotID, session.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	roster := buildRosterClass(session, reservations)
+	return &roster, nil
+}
+
+// CheckIn marks the attendee of a reservation as present
+func (s *AttendanceService) CheckIn(reservationID uint) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.FindByID(reservationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("reservation not found")
+		}
+		return nil, err
+	}
+	return s.checkIn(reservation)
+}
+
+// CheckInByTicket marks the attendee of the reservation with a scanned ticket as present
+func (s *AttendanceService) CheckInByTicket(token string) (*models.Reservation, error) {
+	reservation, err := s.ticketService.VerifyTicket(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.checkIn(reservation)
+}
+
+// checkIn records the check-in of a confirmed reservation once check-in has opened.
+// A reservation marked no-show is corrected to completed; so is one whose class
+// already ended, as its attendance was settled or is about to be.
+func (s *AttendanceService) checkIn(reservation *models.Reservation) (*models.Reservation, error) {
+	if reservation.IsCheckedIn() {
+		return nil, errors.New("reservation is already checked in")
+	}
+
+	if reservation.Status != models.StatusConfirmed && reservation.Status != models.StatusNoShow {
+		return nil, errors.New("only confirmed reservations can be checked in")
+	}
+
+	classStart, err := reservation.StartTime(s.config.Location)
+	if err != nil {
+		return nil, err
+	}
+	classEnd, _ := reservation.EndTime(s.config.Location)
+
+	now := s.clock.Now()
+	if opensAt := classStart.Add(-s.config.CheckInOpens); now.Before(opensAt) {
+		return nil, fmt.Errorf("check-in opens at %s", opensAt.Format("2006-01-02 15:04"))
+	}
+
+	reservation.CheckedInAt = &now
+	if reservation.Status == models.StatusNoShow || !now.Before(classEnd) {
+		reservation.Status = models.StatusCompleted
+	}
+
+	if err := s.reservationRepo.Update(reservation); err != nil {
+		return nil, errors.New("failed to check in")
+	}
+
+	return reservation, nil
+}
+
+// SettleAttendance marks confirmed reservations of classes that ended as completed
+// when the attendee checked in, and as no-shows otherwise
+func (s *AttendanceService) SettleAttendance(ctx context.Context) error {
+	now := s.clock.Now()
+	reservations, err := s.reservationRepo.FindUnsettled(utils.DateOf(now, s.config.Location))
+	if err != nil {
+		return err
+	}
+
+	var ended []uint
+	for i := range reservations {
+		classEnd, err := reservations[i].EndTime(s.config.Location)
+		if err != nil {
+			log.Printf("⚠️  Cannot settle attendance of reservation %d: %v", reservations[i].ID, err)
+			continue
+		}
+		if !now.Before(classEnd) {
+			ended = append(ended, reservations[i].ID)
+		}
+	}
+
+	if len(ended) == 0 {
+		return nil
+	}
+
+	completed, noShows, err := s.reservationRepo.SettleAttendance(ended)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✅ Settled attendance: %d completed, %d no-show(s)", completed, noShows)
+	return nil
+}
+
+// GetSummary gets the recent no-shows of a user and whether they restrict booking
+func (s *AttendanceService) GetSummary(userID uint) (*dto.AttendanceSummary, error) {
+	today := utils.DateOf(s.clock.Now(), s.config.Location)
+	count, err := s.reservationRepo.CountNoShows(userID, today.AddDate(0, 0, -s.config.NoShowWindowDays))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AttendanceSummary{
+		NoShowCount: count,
+		WindowDays:  s.config.NoShowWindowDays,
+		Limit:       s.config.NoShowLimit,
+		Restricted:  s.config.NoShowLimit > 0 && count >= int64(s.config.NoShowLimit),
+	}, nil
+}
+
+// CheckBookingAllowed checks that a user is not restricted from booking by no-shows
+func (s *AttendanceService) CheckBookingAllowed(userID uint) error {
+	if s.config.NoShowLimit <= 0 {
+		return nil
+	}
+
+	summary, err := s.GetSummary(userID)
+	if err != nil {
+		return err
+	}
+
+	if summary.Restricted {
+		return fmt.Errorf("booking restricted after %d no-shows in the last %d days", summary.NoShowCount, summary.WindowDays)
+	}
+	return nil
+}
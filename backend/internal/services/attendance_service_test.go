@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"reservation-api/api/dto"
+	"reservation-api/internal/models"
+	"testing"
+	"time"
+)
+
+// reservationStatus reloads the status of a reservation
+func reservationStatus(t *testing.T, s *testServices, id uint) models.ReservationStatus {
+	t.Helper()
+	reservation, err := s.reservationRepo.FindByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reservation.Status
+}
+
+func TestCheckInWindow(t *testing.T) {
+	now := time.Date(2030, 1, 7, 5, 59, 0, 0, jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	session := seedSession(t, s.db, 10, now, "07:00")
+	confirmed := seedReservation(t, s.db, seedUser(t, s.db, "confirmed@example.com"), session, models.StatusConfirmed, now)
+	pending := seedReservation(t, s.db, seedUser(t, s.db, "pending@example.com"), session, models.StatusPending, now)
+	late := seedReservation(t, s.db, seedUser(t, s.db, "late@example.com"), session, models.StatusConfirmed, now)
+
+	// Check-in opens an hour before class start
+	if _, err := s.attendance.CheckIn(confirmed.ID); err == nil || err.Error() != "check-in opens at 2030-01-07 06:00" {
+		t.Fatalf("checking in early: err = %v, want check-in opens at 2030-01-07 06:00", err)
+	}
+
+	s.clock.Advance(time.Minute)
+	checkedIn, err := s.attendance.CheckIn(confirmed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkedIn.CheckedInAt == nil || !checkedIn.CheckedInAt.Equal(s.clock.Now()) || checkedIn.Status != models.StatusConfirmed {
+		t.Errorf("checked in at %v with status %s, want at %v and still confirmed", checkedIn.CheckedInAt, checkedIn.Status, s.clock.Now())
+	}
+	if _, err := s.attendance.CheckIn(confirmed.ID); err == nil || err.Error() != "reservation is already checked in" {
+		t.Errorf("checking in twice: err = %v, want reservation is already checked in", err)
+	}
+	if _, err := s.attendance.CheckIn(pending.ID); err == nil || err.Error() != "only confirmed reservations can be checked in" {
+		t.Errorf("checking in an unpaid reservation: err = %v, want only confirmed reservations can be checked in", err)
+	}
+
+	// Checked in after the class ended: attendance is recorded as completed
+	s.clock.Advance(2 * time.Hour)
+	checkedIn, err = s.attendance.CheckIn(late.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkedIn.Status != models.StatusCompleted {
+		t.Errorf("checked in after the class is %s, want %s", checkedIn.Status, models.StatusCompleted)
+	}
+}
+
+func TestSettleAttendance(t *testing.T) {
+	now := time.Date(2030, 1, 7, 6, 30, 0, 0, jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	morning := seedSession(t, s.db, 10, now, "07:00")
+	evening := seedSession(t, s.db, 10, now, "18:00")
+	attended := seedReservation(t, s.db, seedUser(t, s.db, "attended@example.com"), morning, models.StatusConfirmed, now)
+	absent := seedReservation(t, s.db, seedUser(t, s.db, "absent@example.com"), morning, models.StatusConfirmed, now)
+	cancelled := seedReservation(t, s.db, seedUser(t, s.db, "cancelled@example.com"), morning, models.StatusCancelled, now)
+	later := seedReservation(t, s.db, seedUser(t, s.db, "later@example.com"), evening, models.StatusConfirmed, now)
+
+	if _, err := s.attendance.CheckIn(attended.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The 60 minute class has not ended yet
+	s.clock.Advance(89 * time.Minute)
+	if err := s.attendance.SettleAttendance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if status := reservationStatus(t, s, absent.ID); status != models.StatusConfirmed {
+		t.Fatalf("absent attendee before the class ended is %s, want %s", status, models.StatusConfirmed)
+	}
+
+	s.clock.Advance(time.Minute)
+	if err := s.attendance.SettleAttendance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint]models.ReservationStatus{
+		attended.ID:  models.StatusCompleted,
+		absent.ID:    models.StatusNoShow,
+		cancelled.ID: models.StatusCancelled,
+		later.ID:     models.StatusConfirmed,
+	}
+	for id, status := range want {
+		if got := reservationStatus(t, s, id); got != status {
+			t.Errorf("reservation %d is %s, want %s", id, got, status)
+		}
+	}
+
+	// A no-show who did attend is corrected at the desk
+	corrected, err := s.attendance.CheckIn(absent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrected.Status != models.StatusCompleted {
+		t.Errorf("corrected no-show is %s, want %s", corrected.Status, models.StatusCompleted)
+	}
+}
+
+func TestNoShowsRestrictBooking(t *testing.T) {
+	now := time.Date(2030, 1, 31, 10, 0, 0, 0, jakarta)
+	cfg := testConfig(jakarta)
+	cfg.NoShowLimit = 2
+	cfg.NoShowWindowDays = 30
+	s := newTestServices(t, cfg, now)
+
+	member := seedUser(t, s.db, "member@example.com")
+	noShow := func(date time.Time) {
+		session := seedSession(t, s.db, 10, date, "07:00")
+		seedReservation(t, s.db, member, session, models.StatusNoShow, date)
+	}
+	noShow(time.Date(2029, 12, 31, 0, 0, 0, 0, jakarta)) // Outside the window
+	noShow(time.Date(2030, 1, 1, 0, 0, 0, 0, jakarta))
+
+	next := seedSession(t, s.db, 10, now.AddDate(0, 0, 2), "07:00")
+	book := func() error {
+		_, err := s.reservations.CreateReservation(member.ID, dto.CreateReservationRequest{SessionID: next.ID})
+		return err
+	}
+
+	summary, err := s.attendance.GetSummary(member.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.NoShowCount != 1 || summary.Restricted {
+		t.Errorf("summary = %+v, want 1 no-show and no restriction", summary)
+	}
+
+	noShow(time.Date(2030, 1, 20, 0, 0, 0, 0, jakarta))
+	if err := book(); err == nil || err.Error() != "booking restricted after 2 no-shows in the last 30 days" {
+		t.Fatalf("booking after 2 no-shows: err = %v, want booking restricted after 2 no-shows in the last 30 days", err)
+	}
+
+	// The oldest no-show in the window drops out of it
+	s.clock.Advance(24 * time.Hour)
+	if err := book(); err != nil {
+		t.Errorf("booking once a no-show left the window: %v", err)
+	}
+}
@@ -1,46 +1,99 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
 	"reservation-api/api/dto"
 	"reservation-api/internal/config"
+	"reservation-api/internal/loginlimit"
 	"reservation-api/internal/models"
+	"reservation-api/internal/notifier"
+	"reservation-api/internal/oidc"
 	"reservation-api/internal/repository"
 	"reservation-api/internal/utils"
 
+	"strings"
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-// AuthService handles authentication business logic
+const (
+	tokenThrottle       = time.Minute         // Wait before another reset or verification email
+	loginAuditRetention = 90 * 24 * time.Hour // How long failed logins are kept for audit
+	oidcStateTTL        = 10 * time.Minute    // Time to finish signing in at a provider
+)
+
+// AuthService handles authentication business logic. Signing in starts a session
+// with a short-lived access token and a refresh token that is rotated on every use.
 type AuthService struct {
-	userRepo *repository.UserRepository
-	config   *config.Config
+	userRepo            *repository.UserRepository
+	sessionRepo         *repository.AuthSessionRepository
+	tokenRepo           *repository.UserTokenRepository
+	loginFailureRepo    *repository.LoginFailureRepository
+	identityRepo        *repository.UserIdentityRepository
+	reservationRepo     *repository.ReservationRepository
+	membershipRepo      *repository.MembershipRepository
+	waitlistService     *WaitlistService
+	notificationService *NotificationService
+	loginLimiter        *loginlimit.Limiter
+	oidcProviders       oidc.Providers
+	config              *config.Config
+	clock               utils.Clock
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo *repository.UserRepository, cfg *config.Config) *AuthService {
+func NewAuthService(
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.AuthSessionRepository,
+	tokenRepo *repository.UserTokenRepository,
+	loginFailureRepo *repository.LoginFailureRepository,
+	identityRepo *repository.UserIdentityRepository,
+	reservationRepo *repository.ReservationRepository,
+	membershipRepo *repository.MembershipRepository,
+	waitlistService *WaitlistService,
+	notificationService *NotificationService,
+	loginLimiter *loginlimit.Limiter,
+	oidcProviders oidc.Providers,
+	cfg *config.Config,
+	clock utils.Clock,
+) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		config:   cfg,
+		userRepo:            userRepo,
+		sessionRepo:         sessionRepo,
+		tokenRepo:           tokenRepo,
+		loginFailureRepo:    loginFailureRepo,
+		identityRepo:        identityRepo,
+		reservationRepo:     reservationRepo,
+		membershipRepo:      membershipRepo,
+		waitlistService:     waitlistService,
+		notificationService: notificationService,
+		loginLimiter:        loginLimiter,
+		oidcProviders:       oidcProviders,
+		config:              cfg,
+		clock:               clock,
 	}
 }
 
-// Register registers a new user
-func (s *AuthService) Register(req dto.RegisterRequest) (*models.User, string, error) {
+// Register registers a new user and signs them in
+func (s *AuthService) Register(req dto.RegisterRequest, client dto.ClientInfo) (*models.User, *dto.AuthTokens, error) {
 	// Check if email already exists
 	exists, err := s.userRepo.Exists(req.Email)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 	if exists {
-		return nil, "", errors.New("email already exists")
+		return nil, nil, errors.New("email already exists")
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", errors.New("failed to hash password")
+		return nil, nil, errors.New("failed to hash password")
 	}
 
 	// Create user
@@ -49,56 +102,690 @@ func (s *AuthService) Register(req dto.RegisterRequest) (*models.User, string, e
 		Email:    req.Email,
 		Password: string(hashedPassword),
 		Phone:    req.Phone,
+		Role:     models.RoleMember,
 		IsActive: true,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
-		return nil, "", errors.New("failed to create user")
+		return nil, nil, errors.New("failed to create user")
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email, s.config.JWTSecret)
+	// Ask the user to confirm their email address. Registration goes through even if
+	// the email cannot be queued, as it can be sent again later.
+	if err := s.sendVerification(user); err != nil {
+		log.Printf("⚠️  Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
+	// Start a session
+	tokens, err := s.startSession(user, client)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, nil, err
 	}
 
 	// Don't return password
 	user.Password = ""
 
-	return user, token, nil
+	return user, tokens, nil
 }
 
-// Login authenticates a user
-func (s *AuthService) Login(req dto.LoginRequest) (*models.User, string, error) {
+// Login authenticates a user and starts a session. Repeated failures for an account
+// or from an IP address slow down further attempts and then lock them out for a while.
+func (s *AuthService) Login(req dto.LoginRequest, client dto.ClientInfo) (*models.User, *dto.AuthTokens, error) {
+	// Reject attempts that come too soon without checking the password. If the
+	// counts cannot be read, logins are let through rather than locking everyone out.
+	if err := s.loginLimiter.Check(req.Email, client.IPAddress, s.clock.Now()); err != nil {
+		var blocked *loginlimit.BlockedError
+		if errors.As(err, &blocked) {
+			s.auditLoginFailure(req.Email, nil, client, models.LoginBlocked, false)
+			return nil, nil, err
+		}
+		log.Printf("⚠️  Failed to check login attempts for %s: %v", req.Email, err)
+	}
+
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, "", errors.New("invalid email or password")
+			s.loginFailed(req.Email, nil, client, models.LoginUnknownEmail)
+			return nil, nil, errors.New("invalid email or password")
 		}
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, "", errors.New("user account is inactive")
+		return nil, nil, errors.New("user account is inactive")
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, "", errors.New("invalid email or password")
+		s.loginFailed(req.Email, &user.ID, client, models.LoginWrongPassword)
+		return nil, nil, errors.New("invalid email or password")
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email, s.config.JWTSecret)
+	if err := s.loginLimiter.Reset(req.Email); err != nil {
+		log.Printf("⚠️  Failed to reset login attempts of user %d: %v", user.ID, err)
+	}
+
+	// Start a session
+	tokens, err := s.startSession(user, client)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, nil, err
 	}
 
 	// Don't return password
 	user.Password = ""
 
-	return user, token, nil
+	return user, tokens, nil
+}
+
+// OIDCProviders returns the names of the providers users can sign in with
+func (s *AuthService) OIDCProviders() []string {
+	return s.oidcProviders.Names()
+}
+
+// AuthorizeOIDC starts a sign-in with a provider. Returns the provider page to send
+// the user to, the state it sends back with the code, and the code verifier the
+// browser keeps to finish the sign-in.
+func (s *AuthService) AuthorizeOIDC(providerName string) (*dto.OIDCAuthorization, error) {
+	provider, err := s.oidcProviders.Get(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	signIn, err := oidc.NewState(provider.Name(), s.config.JWTSecret, oidcStateTTL)
+	if err != nil {
+		return nil, errors.New("failed to start sign-in")
+	}
+
+	authURL, err := provider.AuthCodeURL(signIn)
+	if err != nil {
+		log.Printf("⚠️  Failed to start %s sign-in: %v", provider.Name(), err)
+		return nil, errors.New("failed to reach sign-in provider")
+	}
+
+	return &dto.OIDCAuthorization{
+		AuthorizationURL: authURL,
+		State:            signIn.State,
+		CodeVerifier:     signIn.CodeVerifier,
+		Nonce:            signIn.Nonce,
+	}, nil
+}
+
+// OIDCCallback finishes a sign-in with a provider by exchanging the code it sent
+// back, and signs the user in. The code verifier must be the one handed out with the
+// state, so only the browser that started the sign-in can finish it.
+func (s *AuthService) OIDCCallback(providerName string, req dto.OIDCCallbackRequest, client dto.ClientInfo) (*models.User, *dto.AuthTokens, error) {
+	provider, err := s.oidcProviders.Get(providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce, err := oidc.ParseState(req.State, req.CodeVerifier, provider.Name(), s.config.JWTSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	identity, err := provider.Exchange(req.Code, nonce, req.CodeVerifier)
+	if err != nil {
+		log.Printf("⚠️  %s sign-in failed: %v", provider.Name(), err)
+		return nil, nil, errors.New("sign-in with provider failed")
+	}
+
+	return s.signInWithIdentity(identity, client)
+}
+
+// OIDCSignIn signs a user in with an ID token the mobile apps got from the provider's
+// SDK. The app starts the sign-in like the web does and passes the nonce to the SDK,
+// so the token must carry the nonce bound to the state.
+func (s *AuthService) OIDCSignIn(providerName string, req dto.OIDCTokenRequest, client dto.ClientInfo) (*models.User, *dto.AuthTokens, error) {
+	provider, err := s.oidcProviders.Get(providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce, err := oidc.ParseState(req.State, req.CodeVerifier, provider.Name(), s.config.JWTSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	identity, err := provider.Verify(req.IDToken, nonce)
+	if err != nil {
+		log.Printf("⚠️  %s sign-in failed: %v", provider.Name(), err)
+		return nil, nil, errors.New("invalid ID token")
+	}
+
+	return s.signInWithIdentity(identity, client)
+}
+
+// signInWithIdentity starts a session for the user linked to a provider identity,
+// linking or creating one on first sign-in
+func (s *AuthService) signInWithIdentity(identity *oidc.Identity, client dto.ClientInfo) (*models.User, *dto.AuthTokens, error) {
+	var user *models.User
+	linked, err := s.identityRepo.FindBySubject(identity.Provider, identity.Subject)
+	switch {
+	case err == nil:
+		user = linked.User
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if user, err = s.linkIdentity(identity); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, err
+	}
+
+	if user == nil || !user.IsActive {
+		return nil, nil, errors.New("user account is inactive")
+	}
+
+	tokens, err := s.startSession(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user.Password = ""
+	return user, tokens, nil
+}
+
+// linkIdentity links a provider identity to the user with its email address, creating
+// the user if there is none. Only addresses the provider verified are trusted.
+func (s *AuthService) linkIdentity(identity *oidc.Identity) (*models.User, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errors.New("the sign-in provider did not confirm your email address")
+	}
+
+	now := s.clock.Now()
+	user, err := s.userRepo.FindByEmail(identity.Email)
+	switch {
+	case err == nil:
+		if !user.IsActive {
+			return nil, errors.New("user account is inactive")
+		}
+		// Anyone could have registered an address they do not own. The provider proves
+		// who owns it, so an unverified account drops its password and sessions.
+		if !user.IsEmailVerified() {
+			user.Password = ""
+			user.EmailVerifiedAt = &now
+			if err := s.userRepo.Update(user); err != nil {
+				return nil, errors.New("failed to link account")
+			}
+			if _, err := s.sessionRepo.RevokeAllForUser(user.ID, models.RevokeIdentityLinked, now); err != nil {
+				log.Printf("⚠️  Failed to revoke sessions of user %d: %v", user.ID, err)
+			}
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		name := identity.Name
+		if name == "" {
+			name = strings.Split(identity.Email, "@")[0]
+		}
+		// No password is set; the user can set one with a password reset
+		user = &models.User{
+			Name:            name,
+			Email:           identity.Email,
+			Role:            models.RoleMember,
+			IsActive:        true,
+			EmailVerifiedAt: &now,
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			return nil, errors.New("failed to create user")
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.identityRepo.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}); err != nil {
+		return nil, errors.New("failed to link account")
+	}
+
+	log.Printf("🔗 Linked %s identity to user %d", identity.Provider, user.ID)
+	return user, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token. A
+// refresh token can be used once: presenting a used token again means it was
+// stolen or leaked, so the whole session is revoked.
+func (s *AuthService) Refresh(refreshToken string) (*models.User, *dto.AuthTokens, error) {
+	token, err := s.findRefreshToken(refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.clock.Now()
+	session := token.Session
+	if token.IsUsed() {
+		s.revoke(session.ID, models.RevokeTokenReuse)
+		log.Printf("🚨 Refresh token reuse in session %d of user %d, session revoked", session.ID, session.UserID)
+		return nil, nil, errors.New("refresh token reuse detected")
+	}
+	if session.IsRevoked() {
+		return nil, nil, errors.New("session has been revoked")
+	}
+	if !now.Before(token.ExpiresAt) {
+		return nil, nil, errors.New("refresh token has expired")
+	}
+
+	user := session.User
+	if user == nil || !user.IsActive {
+		s.revoke(session.ID, models.RevokeUserInactive)
+		return nil, nil, errors.New("user account is inactive")
+	}
+
+	next, raw, err := s.newRefreshToken(now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rotated, err := s.sessionRepo.Rotate(token, next, now)
+	if err != nil {
+		return nil, nil, errors.New("failed to refresh session")
+	}
+	if !rotated {
+		// Used by a concurrent request in the meantime
+		s.revoke(session.ID, models.RevokeTokenReuse)
+		return nil, nil, errors.New("refresh token reuse detected")
+	}
+
+	tokens, err := s.issueTokens(user, session.ID, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user.Password = ""
+	return user, tokens, nil
+}
+
+// Logout revokes the session of a refresh token, or every session of its user
+func (s *AuthService) Logout(refreshToken string, all bool) error {
+	token, err := s.findRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if all {
+		if _, err := s.sessionRepo.RevokeAllForUser(token.Session.UserID, models.RevokeLogout, s.clock.Now()); err != nil {
+			return errors.New("failed to log out")
+		}
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(token.SessionID, models.RevokeLogout, s.clock.Now()); err != nil {
+		return errors.New("failed to log out")
+	}
+	return nil
+}
+
+// ValidateSession checks that the session of an access token is still active and its
+// user still active. Returns the current user.
+func (s *AuthService) ValidateSession(sessionID, userID uint) (*models.User, error) {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+
+	if session.UserID != userID {
+		return nil, errors.New("session not found")
+	}
+	if session.IsRevoked() {
+		return nil, errors.New("session has been revoked")
+	}
+	if session.User == nil || !session.User.IsActive {
+		return nil, errors.New("user account is inactive")
+	}
+
+	return session.User, nil
+}
+
+// PurgeExpiredTokens deletes refresh tokens that can no longer be used
+func (s *AuthService) PurgeExpiredTokens(ctx context.Context) error {
+	deleted, err := s.sessionRepo.DeleteExpiredTokens(s.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		log.Printf("🧹 Deleted %d expired refresh token(s)", deleted)
+	}
+
+	return nil
+}
+
+// UnlockAccount clears the failed logins of a user so a lockout ends right away
+func (s *AuthService) UnlockAccount(userID uint) (*models.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginLimiter.Reset(user.Email); err != nil {
+		return nil, errors.New("failed to unlock account")
+	}
+
+	log.Printf("🔓 Login lockout of user %d cleared", user.ID)
+	user.Password = ""
+	return user, nil
+}
+
+// GetLoginFailures gets the latest failed logins, optionally filtered by email and IP address
+func (s *AuthService) GetLoginFailures(email, ipAddress string) ([]models.LoginFailure, error) {
+	return s.loginFailureRepo.FindAll(email, ipAddress, 200)
+}
+
+// PurgeLoginAttempts drops failed login counts that no longer apply and audit
+// records past their retention
+func (s *AuthService) PurgeLoginAttempts(ctx context.Context) error {
+	now := s.clock.Now()
+	if _, err := s.loginLimiter.DeleteExpired(now); err != nil {
+		return err
+	}
+
+	deleted, err := s.loginFailureRepo.DeleteBefore(now.Add(-loginAuditRetention))
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		log.Printf("🧹 Deleted %d old failed login record(s)", deleted)
+	}
+
+	return nil
+}
+
+// loginFailed counts a failed login against the account and IP address and audits it
+func (s *AuthService) loginFailed(email string, userID *uint, client dto.ClientInfo, reason models.LoginFailureReason) {
+	locked, err := s.loginLimiter.Fail(email, client.IPAddress, s.clock.Now())
+	if err != nil {
+		log.Printf("⚠️  Failed to count failed login for %s: %v", email, err)
+	}
+	if locked {
+		log.Printf("🔒 Login for %s locked for %s after repeated failures", email, s.config.LoginLockout)
+	}
+
+	s.auditLoginFailure(email, userID, client, reason, locked)
+}
+
+// auditLoginFailure records a failed login, logging failures
+func (s *AuthService) auditLoginFailure(email string, userID *uint, client dto.ClientInfo, reason models.LoginFailureReason, locked bool) {
+	failure := &models.LoginFailure{
+		Email:     email,
+		UserID:    userID,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Reason:    reason,
+		Locked:    locked,
+	}
+	if err := s.loginFailureRepo.Create(failure); err != nil {
+		log.Printf("⚠️  Failed to audit failed login for %s: %v", email, err)
+	}
+}
+
+// RequestPasswordReset emails a password reset link. It succeeds whether or not the
+// email address belongs to an account, so it cannot be used to find out which do.
+func (s *AuthService) RequestPasswordReset(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive || s.recentlySent(user.ID, models.TokenPasswordReset) {
+		return nil
+	}
+
+	raw, err := s.newUserToken(user, user.Email, models.TokenPasswordReset, s.config.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notificationService.SendAccountEmail(user, user.Email, EventPasswordReset,
+		s.frontendLink("/reset-password", raw), s.config.PasswordResetTTL)
+}
+
+// ResetPassword sets a new password with a reset token and signs the user out of
+// every session, as whoever held the old password may still be signed in
+func (s *AuthService) ResetPassword(resetToken, password string) error {
+	token, err := s.findUserToken(resetToken, models.TokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	user := token.User
+	if user == nil || !user.IsActive {
+		return errors.New("user account is inactive")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	if err := s.consumeUserToken(token); err != nil {
+		return err
+	}
+
+	// Receiving the link proves the user owns the address as well
+	user.Password = string(hashedPassword)
+	if user.EmailVerifiedAt == nil && strings.EqualFold(token.Email, user.Email) {
+		now := s.clock.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to reset password")
+	}
+
+	// The owner of the address can log in with the new password right away
+	if err := s.loginLimiter.Reset(user.Email); err != nil {
+		log.Printf("⚠️  Failed to reset login attempts of user %d: %v", user.ID, err)
+	}
+
+	revoked, err := s.sessionRepo.RevokeAllForUser(user.ID, models.RevokePasswordReset, s.clock.Now())
+	if err != nil {
+		log.Printf("⚠️  Failed to revoke sessions of user %d after password reset: %v", user.ID, err)
+	} else if revoked > 0 {
+		log.Printf("🔐 Password of user %d reset, %d session(s) revoked", user.ID, revoked)
+	}
+
+	return nil
+}
+
+// VerifyEmail confirms the email address of a user with a verification token
+func (s *AuthService) VerifyEmail(verificationToken string) (*models.User, error) {
+	token, err := s.findUserToken(verificationToken, models.TokenEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	// The address changed since the link was sent
+	user := token.User
+	if user == nil || !strings.EqualFold(token.Email, user.Email) {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	if err := s.consumeUserToken(token); err != nil {
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		now := s.clock.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, errors.New("failed to verify email")
+		}
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// ResendVerification emails a new verification link to a user
+func (s *AuthService) ResendVerification(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return errors.New("email address is already verified")
+	}
+	if s.recentlySent(user.ID, models.TokenEmailVerification) {
+		return errors.New("please wait a minute before requesting another email")
+	}
+
+	return s.sendVerification(user)
+}
+
+// sendVerification emails a verification link for the current address of a user
+func (s *AuthService) sendVerification(user *models.User) error {
+	raw, err := s.newUserToken(user, user.Email, models.TokenEmailVerification, s.config.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notificationService.SendAccountEmail(user, user.Email, EventEmailVerification,
+		s.frontendLink("/verify-email", raw), s.config.EmailVerificationTTL)
+}
+
+// newUserToken creates a token emailed to a user, replacing their earlier unused
+// tokens with the same purpose. Returns the token itself.
+func (s *AuthService) newUserToken(user *models.User, email string, purpose models.UserTokenPurpose, ttl time.Duration) (string, error) {
+	raw, err := utils.NewOpaqueToken()
+	if err != nil {
+		return "", errors.New("failed to generate token")
+	}
+
+	now := s.clock.Now()
+	token := &models.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     email,
+		TokenHash: utils.HashToken(raw),
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.tokenRepo.Replace(token, now); err != nil {
+		return "", errors.New("failed to create token")
+	}
+
+	return raw, nil
+}
+
+// findUserToken finds a usable token with its user
+func (s *AuthService) findUserToken(raw string, purpose models.UserTokenPurpose) (*models.UserToken, error) {
+	token, err := s.tokenRepo.FindByHash(utils.HashToken(raw), purpose)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired token")
+		}
+		return nil, err
+	}
+
+	if !token.IsUsable(s.clock.Now()) {
+		return nil, errors.New("invalid or expired token")
+	}
+	return token, nil
+}
+
+// consumeUserToken marks a token used, failing if a concurrent request used it first
+func (s *AuthService) consumeUserToken(token *models.UserToken) error {
+	consumed, err := s.tokenRepo.Consume(token.ID, s.clock.Now())
+	if err != nil {
+		return errors.New("failed to use token")
+	}
+	if !consumed {
+		return errors.New("invalid or expired token")
+	}
+	return nil
+}
+
+// recentlySent checks if a token with a purpose was sent to a user moments ago
+func (s *AuthService) recentlySent(userID uint, purpose models.UserTokenPurpose) bool {
+	latest, err := s.tokenRepo.FindLatest(userID, purpose)
+	if err != nil {
+		return false
+	}
+	return s.clock.Now().Sub(latest.CreatedAt) < tokenThrottle
+}
+
+// frontendLink builds a link to a frontend page carrying a token
+func (s *AuthService) frontendLink(page, token string) string {
+	return strings.TrimRight(s.config.FrontendURL, "/") + page + "?token=" + url.QueryEscape(token)
+}
+
+// startSession creates a session for a signed-in user and issues its tokens
+func (s *AuthService) startSession(user *models.User, client dto.ClientInfo) (*dto.AuthTokens, error) {
+	now := s.clock.Now()
+	token, raw, err := s.newRefreshToken(now)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.AuthSession{
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		LastUsedAt: now,
+	}
+	if err := s.sessionRepo.Create(session, token); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	return s.issueTokens(user, session.ID, raw)
+}
+
+// newRefreshToken creates a refresh token, returning the record and the token itself
+func (s *AuthService) newRefreshToken(now time.Time) (*models.RefreshToken, string, error) {
+	raw, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, "", errors.New("failed to generate token")
+	}
+
+	return &models.RefreshToken{
+		TokenHash: utils.HashToken(raw),
+		ExpiresAt: now.Add(s.config.RefreshTokenTTL),
+	}, raw, nil
+}
+
+// issueTokens signs an access token for a session and pairs it with a refresh token
+func (s *AuthService) issueTokens(user *models.User, sessionID uint, refreshToken string) (*dto.AuthTokens, error) {
+	token, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), sessionID, s.config.AccessTokenTTL, s.config.JWTSecret)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &dto.AuthTokens{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.config.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// findRefreshToken finds a refresh token with its session and user
+func (s *AuthService) findRefreshToken(refreshToken string) (*models.RefreshToken, error) {
+	token, err := s.sessionRepo.FindRefreshToken(utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// revoke revokes a session, logging failures
+func (s *AuthService) revoke(sessionID uint, reason string) {
+	if err := s.sessionRepo.Revoke(sessionID, reason, s.clock.Now()); err != nil {
+		log.Printf("⚠️  Failed to revoke session %d: %v", sessionID, err)
+	}
 }
 
 // GetProfile gets user profile
@@ -136,6 +823,24 @@ func (s *AuthService) UpdateProfile(userID uint, req dto.UpdateProfileRequest) (
 		user.Phone = req.Phone
 	}
 
+	// Update notification channels
+	if req.NotifyEmail != nil {
+		user.NotifyEmail = *req.NotifyEmail
+	}
+	if req.NotifySMS != nil {
+		user.NotifySMS = *req.NotifySMS
+	}
+	if req.NotifyWhatsApp != nil {
+		user.NotifyWhatsApp = *req.NotifyWhatsApp
+	}
+
+	// SMS and WhatsApp messages are sent to the phone number
+	if user.NotifySMS || user.NotifyWhatsApp {
+		if _, err := notifier.NormalizePhone(user.Phone, s.config.DefaultCountryCode); err != nil {
+			return nil, errors.New("a valid phone number is required for SMS and WhatsApp notifications")
+		}
+	}
+
 	// Save changes
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, errors.New("failed to update profile")
@@ -145,4 +850,156 @@ func (s *AuthService) UpdateProfile(userID uint, req dto.UpdateProfileRequest) (
 	user.Password = ""
 
 	return user, nil
-}
\ No newline at end of file
+}
+
+// ChangePassword changes the password of a user, who confirms it with their current
+// password. Every other session of the user is signed out.
+func (s *AuthService) ChangePassword(userID, sessionID uint, req dto.ChangePasswordRequest) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return errors.New("current password is incorrect")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	user.Password = string(hashedPassword)
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to change password")
+	}
+
+	now := s.clock.Now()
+	if _, err := s.sessionRepo.RevokeOthersForUser(user.ID, sessionID, models.RevokePasswordChange, now); err != nil {
+		log.Printf("⚠️  Failed to revoke sessions of user %d after password change: %v", user.ID, err)
+	}
+	if err := s.tokenRepo.InvalidateAll(user.ID, now); err != nil {
+		log.Printf("⚠️  Failed to invalidate tokens of user %d after password change: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ChangeEmail changes the email address of a user, who confirms it with their
+// password. The new address is unverified until the user opens the link sent to it.
+func (s *AuthService) ChangeEmail(userID uint, req dto.ChangeEmailRequest) (*models.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return nil, errors.New("password is incorrect")
+	}
+
+	if strings.EqualFold(req.Email, user.Email) {
+		return nil, errors.New("new email is the same as the current one")
+	}
+
+	exists, err := s.userRepo.Exists(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("email already exists")
+	}
+
+	user.Email = req.Email
+	user.EmailVerifiedAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("failed to change email")
+	}
+
+	// Links sent to the old address no longer apply to the account
+	if err := s.tokenRepo.InvalidateAll(user.ID, s.clock.Now()); err != nil {
+		log.Printf("⚠️  Failed to invalidate tokens of user %d after email change: %v", user.ID, err)
+	}
+	if err := s.sendVerification(user); err != nil {
+		log.Printf("⚠️  Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// DeleteAccount deletes the account of a user, who confirms it with their password.
+// The user is anonymized rather than removed: reservations and payments keep
+// referring to the account for accounting, but no personal data is left on it.
+// Memberships stop renewing so the account is never charged again.
+func (s *AuthService) DeleteAccount(userID uint, req dto.DeleteAccountRequest) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return errors.New("password is incorrect")
+	}
+
+	if user.IsAdmin() {
+		return errors.New("admin accounts cannot be deleted")
+	}
+
+	// Bookings still to come have to be cancelled first, so refunds go through the
+	// normal cancellation policy
+	now := s.clock.Now()
+	upcoming, err := s.reservationRepo.GetUpcomingReservations(user.ID, utils.DateOf(now, s.config.Location))
+	if err != nil {
+		return err
+	}
+	for _, reservation := range upcoming {
+		if reservation.CanBeCancelled() {
+			return errors.New("cancel your upcoming reservations before deleting your account")
+		}
+	}
+
+	if err := s.waitlistService.LeaveAll(user.ID); err != nil {
+		return errors.New("failed to leave waitlists")
+	}
+
+	if err := s.membershipRepo.StopForUser(user.ID); err != nil {
+		return errors.New("failed to stop memberships")
+	}
+
+	user.Name = "Deleted user"
+	user.Email = fmt.Sprintf("deleted-%d@users.invalid", user.ID)
+	user.Password = ""
+	user.Phone = ""
+	user.IsActive = false
+	user.EmailVerifiedAt = nil
+	user.AnonymizedAt = &now
+	user.NotificationPreferences = models.NotificationPreferences{}
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to delete account")
+	}
+
+	if _, err := s.sessionRepo.RevokeAllForUser(user.ID, models.RevokeAccountDeleted, now); err != nil {
+		log.Printf("⚠️  Failed to revoke sessions of deleted user %d: %v", user.ID, err)
+	}
+	if err := s.tokenRepo.InvalidateAll(user.ID, now); err != nil {
+		log.Printf("⚠️  Failed to invalidate tokens of deleted user %d: %v", user.ID, err)
+	}
+	if err := s.identityRepo.DeleteByUserID(user.ID); err != nil {
+		log.Printf("⚠️  Failed to unlink identities of deleted user %d: %v", user.ID, err)
+	}
+
+	log.Printf("🗑️  Account of user %d deleted and anonymized", user.ID)
+	return nil
+}
+
+// findUser finds a user by ID
+func (s *AuthService) findUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,569 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/oidc"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestDeleteAccountStopsMemberships(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	now := s.clock.Now()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := seedUser(t, s.db, "leaving@example.com")
+	if err := s.db.Model(user).Update("password", string(hash)).Error; err != nil {
+		t.Fatal(err)
+	}
+	other := seedUser(t, s.db, "staying@example.com")
+
+	plan := &models.MembershipPlan{Name: "Unlimited", Price: 900000, IsActive: true}
+	if err := s.db.Create(plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	start, end := now.AddDate(0, -1, 1), now.AddDate(0, 0, 1)
+	active := &models.Membership{UserID: user.ID, PlanID: plan.ID, Price: plan.Price, Status: models.MembershipActive, AutoRenew: true, StartDate: &start, EndDate: &end}
+	pending := &models.Membership{UserID: user.ID, PlanID: plan.ID, Price: plan.Price, Status: models.MembershipPending, AutoRenew: true}
+	kept := &models.Membership{UserID: other.ID, PlanID: plan.ID, Price: plan.Price, Status: models.MembershipActive, AutoRenew: true, StartDate: &start, EndDate: &end}
+	for _, membership := range []*models.Membership{active, pending, kept} {
+		if err := s.db.Create(membership).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	payment := &models.Payment{UserID: user.ID, MembershipID: &pending.ID, Amount: plan.Price, Status: models.PaymentPending, TransactionID: "MEM-1"}
+	if err := s.db.Create(payment).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.auth.DeleteAccount(user.ID, dto.DeleteAccountRequest{Password: "secret123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := s.membershipRepo.FindDueForRenewal(now.Add(s.config.MembershipRenewalLead))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].ID != kept.ID {
+		t.Errorf("due for renewal: %v, want only membership %d", due, kept.ID)
+	}
+
+	if err := s.db.First(active, active.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if active.AutoRenew || active.Status != models.MembershipActive {
+		t.Errorf("paid membership: auto_renew %v, status %s; want false, %s", active.AutoRenew, active.Status, models.MembershipActive)
+	}
+	if err := s.db.First(pending, pending.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != models.MembershipCancelled {
+		t.Errorf("unpaid membership is %s, want %s", pending.Status, models.MembershipCancelled)
+	}
+	if err := s.db.First(payment, payment.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != models.PaymentExpired {
+		t.Errorf("membership payment is %s, want %s", payment.Status, models.PaymentExpired)
+	}
+}
+
+func TestFindDueForRenewalSkipsInactiveUsers(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	now := s.clock.Now()
+
+	user := seedUser(t, s.db, "disabled@example.com")
+	if err := s.db.Model(user).Update("is_active", false).Error; err != nil {
+		t.Fatal(err)
+	}
+	plan := &models.MembershipPlan{Name: "Unlimited", Price: 900000, IsActive: true}
+	if err := s.db.Create(plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	start, end := now.AddDate(0, -1, 1), now.AddDate(0, 0, 1)
+	membership := &models.Membership{UserID: user.ID, PlanID: plan.ID, Price: plan.Price, Status: models.MembershipActive, AutoRenew: true, StartDate: &start, EndDate: &end}
+	if err := s.db.Create(membership).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := s.membershipRepo.FindDueForRenewal(now.Add(s.config.MembershipRenewalLead))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 0 {
+		t.Errorf("due for renewal: %d membership(s), want none", len(due))
+	}
+}
+
+// newOIDCTestServices sets up services signing in with a fake issuer named "fake"
+func newOIDCTestServices(t *testing.T) (*testServices, *oidc.FakeIssuer) {
+	t.Helper()
+	issuer, err := oidc.NewFakeIssuer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(issuer)
+	t.Cleanup(server.Close)
+	issuer.URL = server.URL
+
+	cfg := testConfig(jakarta)
+	cfg.OIDCProviders = []config.OIDCProvider{{
+		Name:         "fake",
+		Issuer:       server.URL,
+		ClientID:     "web-client",
+		ClientSecret: "web-secret",
+		RedirectURL:  "http://localhost:3000/auth/fake/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	}}
+	return newTestServices(t, cfg, time.Now()), issuer
+}
+
+// signInAtIssuer starts a sign-in and follows the fake issuer's sign-in page. Returns
+// the callback the browser would post and the code verifier it kept.
+func signInAtIssuer(t *testing.T, s *testServices) dto.OIDCCallbackRequest {
+	t.Helper()
+	authorization, err := s.auth.AuthorizeOIDC("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authorization.AuthorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	redirect, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dto.OIDCCallbackRequest{
+		Code:         redirect.Query().Get("code"),
+		State:        redirect.Query().Get("state"),
+		CodeVerifier: authorization.CodeVerifier,
+	}
+}
+
+func TestOIDCCallbackLinksVerifiedEmail(t *testing.T) {
+	s, issuer := newOIDCTestServices(t)
+
+	verifiedAt := time.Now()
+	user := seedUser(t, s.db, "member@example.com")
+	if err := s.db.Model(user).Update("email_verified_at", verifiedAt).Error; err != nil {
+		t.Fatal(err)
+	}
+	issuer.Identity = oidc.FakeIdentity{Subject: "sub-1", Email: "member@example.com", EmailVerified: true, Name: "Member"}
+
+	signedIn, tokens, err := s.auth.OIDCCallback("fake", signInAtIssuer(t, s), dto.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signedIn.ID != user.ID || tokens.Token == "" || tokens.RefreshToken == "" {
+		t.Errorf("signed in as user %d with tokens %+v, want user %d with a session", signedIn.ID, tokens, user.ID)
+	}
+
+	var linked models.User
+	if err := s.db.First(&linked, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if linked.Password != user.Password {
+		t.Error("password of a verified account was dropped")
+	}
+
+	// The identity stays linked when the email at the provider changes
+	issuer.Identity.Email = "renamed@example.com"
+	again, _, err := s.auth.OIDCCallback("fake", signInAtIssuer(t, s), dto.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second sign-in as user %d, want %d", again.ID, user.ID)
+	}
+}
+
+func TestOIDCCallbackRejectsUnverifiedEmail(t *testing.T) {
+	s, issuer := newOIDCTestServices(t)
+
+	user := seedUser(t, s.db, "member@example.com")
+	issuer.Identity = oidc.FakeIdentity{Subject: "sub-1", Email: "member@example.com", EmailVerified: false}
+
+	if _, _, err := s.auth.OIDCCallback("fake", signInAtIssuer(t, s), dto.ClientInfo{}); err == nil ||
+		err.Error() != "the sign-in provider did not confirm your email address" {
+		t.Fatalf("err = %v, want unconfirmed email", err)
+	}
+
+	var identities int64
+	if err := s.db.Model(&models.UserIdentity{}).Where("user_id = ?", user.ID).Count(&identities).Error; err != nil {
+		t.Fatal(err)
+	}
+	if identities != 0 {
+		t.Errorf("%d identities linked by an unverified email", identities)
+	}
+}
+
+func TestOIDCCallbackRejectsOtherBrowser(t *testing.T) {
+	s, _ := newOIDCTestServices(t)
+
+	// An attacker signs in and hands their code and state to someone else's browser,
+	// which holds the verifier of its own sign-in
+	attacker := signInAtIssuer(t, s)
+	victim, err := s.auth.AuthorizeOIDC("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := attacker
+	req.CodeVerifier = victim.CodeVerifier
+	if _, _, err := s.auth.OIDCCallback("fake", req, dto.ClientInfo{}); err == nil ||
+		err.Error() != "invalid or expired sign-in state" {
+		t.Fatalf("err = %v, want invalid state", err)
+	}
+}
+
+func TestOIDCSignInChecksIDToken(t *testing.T) {
+	s, issuer := newOIDCTestServices(t)
+	identity := oidc.FakeIdentity{Subject: "sub-1", Email: "mobile@example.com", EmailVerified: true}
+
+	// A token the app got in another sign-in carries that sign-in's nonce
+	other, err := s.auth.AuthorizeOIDC("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		audience string
+		nonce    func(*dto.OIDCAuthorization) string
+		verifier func(*dto.OIDCAuthorization) string
+		wantErr  string
+	}{
+		{"valid", "web-client", nonceOf, verifierOf, ""},
+		{"wrong audience", "other-client", nonceOf, verifierOf, "invalid ID token"},
+		{"other sign-in", "web-client", func(*dto.OIDCAuthorization) string { return other.Nonce }, verifierOf, "invalid ID token"},
+		{"no nonce", "web-client", func(*dto.OIDCAuthorization) string { return "" }, verifierOf, "invalid ID token"},
+		{"other verifier", "web-client", nonceOf, func(*dto.OIDCAuthorization) string { return other.CodeVerifier }, "invalid or expired sign-in state"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorization, err := s.auth.AuthorizeOIDC("fake")
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := issuer.IDToken(identity, tt.audience, tt.nonce(authorization))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := dto.OIDCTokenRequest{IDToken: token, State: authorization.State, CodeVerifier: tt.verifier(authorization)}
+			_, _, err = s.auth.OIDCSignIn("fake", req, dto.ClientInfo{})
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("err = %v, want %s", err, tt.wantErr)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func nonceOf(authorization *dto.OIDCAuthorization) string    { return authorization.Nonce }
+func verifierOf(authorization *dto.OIDCAuthorization) string { return authorization.CodeVerifier }
+
+// seedMember creates an active member who logs in with the given password
+func seedMember(t *testing.T, s *testServices, email, password string) *models.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := seedUser(t, s.db, email)
+	if err := s.db.Model(user).Update("password", string(hash)).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// login signs a member in, starting a new session
+func login(t *testing.T, s *testServices, email, password string) *dto.AuthTokens {
+	t.Helper()
+	_, tokens, err := s.auth.Login(dto.LoginRequest{Email: email, Password: password}, dto.ClientInfo{IPAddress: "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	seedMember(t, s, "member@example.com", "secret123")
+	first := login(t, s, "member@example.com", "secret123")
+
+	s.clock.Advance(time.Hour)
+	_, second, err := s.auth.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.Token == "" {
+		t.Fatalf("refresh returned %+v, want a new token pair", second)
+	}
+
+	_, third, err := s.auth.Refresh(second.RefreshToken)
+	if err != nil {
+		t.Fatalf("refreshing with the rotated token: %v", err)
+	}
+	if third.RefreshToken == second.RefreshToken {
+		t.Error("refresh token was not rotated again")
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	user := seedMember(t, s, "member@example.com", "secret123")
+	first := login(t, s, "member@example.com", "secret123")
+	other := login(t, s, "member@example.com", "secret123")
+
+	_, second, err := s.auth.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying the rotated token revokes the session, along with its current token
+	if _, _, err := s.auth.Refresh(first.RefreshToken); err == nil || err.Error() != "refresh token reuse detected" {
+		t.Fatalf("replaying a rotated token: err = %v, want refresh token reuse detected", err)
+	}
+	if _, _, err := s.auth.Refresh(second.RefreshToken); err == nil || err.Error() != "session has been revoked" {
+		t.Errorf("refreshing the revoked session: err = %v, want session has been revoked", err)
+	}
+
+	var sessions []models.AuthSession
+	if err := s.db.Where("user_id = ?", user.ID).Order("id").Find(&sessions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("user has %d sessions, want 2", len(sessions))
+	}
+	if !sessions[0].IsRevoked() || sessions[0].RevokedReason != models.RevokeTokenReuse {
+		t.Errorf("reused session revoked at %v for %q, want revoked for %q",
+			sessions[0].RevokedAt, sessions[0].RevokedReason, models.RevokeTokenReuse)
+	}
+
+	// Other devices stay signed in
+	if _, _, err := s.auth.Refresh(other.RefreshToken); err != nil {
+		t.Errorf("refreshing another session: %v", err)
+	}
+}
+
+func TestRefreshTokenExpires(t *testing.T) {
+	cfg := testConfig(jakarta)
+	s := newTestServices(t, cfg, time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	seedMember(t, s, "member@example.com", "secret123")
+	tokens := login(t, s, "member@example.com", "secret123")
+
+	// Each refresh starts a new lifetime
+	s.clock.Advance(cfg.RefreshTokenTTL - time.Minute)
+	_, tokens, err := s.auth.Refresh(tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("refreshing before expiry: %v", err)
+	}
+
+	s.clock.Advance(cfg.RefreshTokenTTL)
+	if _, _, err := s.auth.Refresh(tokens.RefreshToken); err == nil || err.Error() != "refresh token has expired" {
+		t.Errorf("refreshing after expiry: err = %v, want refresh token has expired", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	seedMember(t, s, "member@example.com", "secret123")
+	phone := login(t, s, "member@example.com", "secret123")
+	laptop := login(t, s, "member@example.com", "secret123")
+	tablet := login(t, s, "member@example.com", "secret123")
+
+	if err := s.auth.Logout("not-a-token", false); err == nil || err.Error() != "invalid refresh token" {
+		t.Errorf("logging out with an unknown token: err = %v, want invalid refresh token", err)
+	}
+
+	if err := s.auth.Logout(phone.RefreshToken, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.auth.Refresh(phone.RefreshToken); err == nil || err.Error() != "session has been revoked" {
+		t.Errorf("refreshing a logged out session: err = %v, want session has been revoked", err)
+	}
+	_, laptop, err := s.auth.Refresh(laptop.RefreshToken)
+	if err != nil {
+		t.Fatalf("refreshing another session: %v", err)
+	}
+
+	if err := s.auth.Logout(laptop.RefreshToken, true); err != nil {
+		t.Fatal(err)
+	}
+	for name, tokens := range map[string]*dto.AuthTokens{"laptop": laptop, "tablet": tablet} {
+		if _, _, err := s.auth.Refresh(tokens.RefreshToken); err == nil || err.Error() != "session has been revoked" {
+			t.Errorf("refreshing %s after logging out everywhere: err = %v, want session has been revoked", name, err)
+		}
+	}
+}
+
+var linkToken = regexp.MustCompile(`[?&]token=([^&\s"<]+)`)
+
+// emailedToken returns the token of the link in the latest email of an event sent to
+// an address
+func emailedToken(t *testing.T, s *testServices, to, event string) string {
+	t.Helper()
+	var message models.OutboxMessage
+	if err := s.db.Where("recipient = ? AND event = ?", to, event).Order("id DESC").First(&message).Error; err != nil {
+		t.Fatalf("no %s email to %s: %v", event, to, err)
+	}
+	match := linkToken.FindStringSubmatch(message.Body)
+	if match == nil {
+		t.Fatalf("no token link in %s email: %s", event, message.Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// accountTestConfig sets the lifetimes and links of account emails
+func accountTestConfig() *config.Config {
+	cfg := testConfig(jakarta)
+	cfg.FrontendURL = "https://studio.example"
+	cfg.PasswordResetTTL = time.Hour
+	cfg.EmailVerificationTTL = 48 * time.Hour
+	return cfg
+}
+
+func TestResetPassword(t *testing.T) {
+	s := newTestServices(t, accountTestConfig(), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	seedMember(t, s, "member@example.com", "secret123")
+	session := login(t, s, "member@example.com", "secret123")
+
+	if err := s.auth.RequestPasswordReset("member@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	token := emailedToken(t, s, "member@example.com", EventPasswordReset)
+
+	if err := s.auth.ResetPassword(token, "newsecret456"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.auth.ResetPassword(token, "another789"); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("using the reset token twice: err = %v, want invalid or expired token", err)
+	}
+
+	// Sessions started with the old password are signed out
+	if _, _, err := s.auth.Refresh(session.RefreshToken); err == nil || err.Error() != "session has been revoked" {
+		t.Errorf("refreshing a session after the reset: err = %v, want session has been revoked", err)
+	}
+	if _, _, err := s.auth.Login(dto.LoginRequest{Email: "member@example.com", Password: "secret123"}, dto.ClientInfo{}); err == nil {
+		t.Error("logged in with the old password")
+	}
+	login(t, s, "member@example.com", "newsecret456")
+}
+
+func TestResetPasswordTokenExpires(t *testing.T) {
+	cfg := accountTestConfig()
+	s := newTestServices(t, cfg, time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	seedMember(t, s, "member@example.com", "secret123")
+
+	if err := s.auth.RequestPasswordReset("member@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	token := emailedToken(t, s, "member@example.com", EventPasswordReset)
+
+	s.clock.Advance(cfg.PasswordResetTTL)
+	if err := s.auth.ResetPassword(token, "newsecret456"); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("using an expired reset token: err = %v, want invalid or expired token", err)
+	}
+	login(t, s, "member@example.com", "secret123")
+}
+
+func TestResetPasswordReplacesEarlierToken(t *testing.T) {
+	s := newTestServices(t, accountTestConfig(), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	seedMember(t, s, "member@example.com", "secret123")
+
+	if err := s.auth.RequestPasswordReset("member@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	earlier := emailedToken(t, s, "member@example.com", EventPasswordReset)
+
+	s.clock.Advance(2 * time.Minute)
+	if err := s.auth.RequestPasswordReset("member@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	latest := emailedToken(t, s, "member@example.com", EventPasswordReset)
+
+	if err := s.auth.ResetPassword(earlier, "newsecret456"); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("using a replaced reset token: err = %v, want invalid or expired token", err)
+	}
+	if err := s.auth.ResetPassword(latest, "newsecret456"); err != nil {
+		t.Errorf("using the latest reset token: %v", err)
+	}
+}
+
+func TestRequestPasswordResetGivesNoHint(t *testing.T) {
+	s := newTestServices(t, accountTestConfig(), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	seedMember(t, s, "member@example.com", "secret123")
+
+	known := s.auth.RequestPasswordReset("member@example.com")
+	unknown := s.auth.RequestPasswordReset("nobody@example.com")
+	if known != nil || unknown != nil {
+		t.Errorf("reset for a known email: %v, for an unknown email: %v, want both to succeed", known, unknown)
+	}
+
+	var sent int64
+	if err := s.db.Model(&models.OutboxMessage{}).Where("recipient = ?", "nobody@example.com").Count(&sent).Error; err != nil {
+		t.Fatal(err)
+	}
+	if sent != 0 {
+		t.Errorf("%d emails sent to an unknown address", sent)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	cfg := accountTestConfig()
+	s := newTestServices(t, cfg, time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	member := seedUser(t, s.db, "member@example.com")
+	late := seedUser(t, s.db, "late@example.com")
+
+	for _, user := range []*models.User{member, late} {
+		if err := s.auth.ResendVerification(user.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	token := emailedToken(t, s, "member@example.com", EventEmailVerification)
+	lateToken := emailedToken(t, s, "late@example.com", EventEmailVerification)
+
+	verified, err := s.auth.VerifyEmail(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verified.IsEmailVerified() {
+		t.Error("email address not verified")
+	}
+	if _, err := s.auth.VerifyEmail(token); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("using the verification token twice: err = %v, want invalid or expired token", err)
+	}
+	if err := s.auth.ResendVerification(member.ID); err == nil || err.Error() != "email address is already verified" {
+		t.Errorf("resending to a verified address: err = %v, want email address is already verified", err)
+	}
+
+	s.clock.Advance(cfg.EmailVerificationTTL)
+	if _, err := s.auth.VerifyEmail(lateToken); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("using an expired verification token: err = %v, want invalid or expired token", err)
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"reservation-api/api/dto"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClosureService handles studio holidays, closures and blackout dates. Adding a
+// closure cancels the classes it covers; removing it schedules them again.
+type ClosureService struct {
+	closureRepo    *repository.ClosureRepository
+	courtRepo      *repository.CourtRepository
+	timeslotRepo   *repository.TimeslotRepository
+	sessionService *SessionService
+}
+
+// NewClosureService creates a new closure service
+func NewClosureService(
+	closureRepo *repository.ClosureRepository,
+	courtRepo *repository.CourtRepository,
+	timeslotRepo *repository.TimeslotRepository,
+	sessionService *SessionService,
+) *ClosureService {
+	return &ClosureService{
+		closureRepo:    closureRepo,
+		courtRepo:      courtRepo,
+		timeslotRepo:   timeslotRepo,
+		sessionService: sessionService,
+	}
+}
+
+// GetClosures gets all closures
+func (s *ClosureService) GetClosures() ([]models.Closure, error) {
+	return s.closureRepo.FindAll()
+}
+
+// CreateClosure creates a closure and cancels the upcoming sessions it covers,
+// refunding their reservations. Returns the number of sessions cancelled.
+func (s *ClosureService) CreateClosure(req dto.ClosureRequest) (*models.Closure, int, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, 0, errors.New("invalid start_date format. Use YYYY-MM-DD")
+	}
+
+	endDate := startDate
+	if req.EndDate != "" {
+		if endDate, err = time.Parse("2006-01-02", req.EndDate); err != nil {
+			return nil, 0, errors.New("invalid end_date format. Use YYYY-MM-DD")
+		}
+	}
+
+	if endDate.Before(startDate) {
+		return nil, 0, errors.New("end_date must not be before start_date")
+	}
+	if req.Recurring && !endDate.Before(startDate.AddDate(1, 0, 0)) {
+		return nil, 0, errors.New("a recurring closure must be shorter than a year")
+	}
+
+	if req.CourtID != nil {
+		if _, err := s.courtRepo.FindByID(*req.CourtID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, 0, errors.New("court not found")
+			}
+			return nil, 0, err
+		}
+	}
+
+	if req.TimeslotID != nil {
+		if _, err := s.timeslotRepo.FindByID(*req.TimeslotID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, 0, errors.New("timeslot not found")
+			}
+			return nil, 0, err
+		}
+	}
+
+	closure := &models.Closure{
+		Name:       req.Name,
+		CourtID:    req.CourtID,
+		TimeslotID: req.TimeslotID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Recurring:  req.Recurring,
+	}
+
+	if err := s.closureRepo.Create(closure); err != nil {
+		return nil, 0, errors.New("failed to create closure")
+	}
+
+	cancelled, err := s.sessionService.CloseSessions(closure)
+	if err != nil {
+		return closure, cancelled, err
+	}
+
+	return closure, cancelled, nil
+}
+
+// DeleteClosure removes a closure and schedules its classes again. Reservations
+// cancelled by the closure are not restored.
+func (s *ClosureService) DeleteClosure(id uint) error {
+	closure, err := s.closureRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("closure not found")
+		}
+		return err
+	}
+
+	if err := s.closureRepo.Delete(closure.ID); err != nil {
+		return errors.New("failed to delete closure")
+	}
+
+	return s.sessionService.ReopenSessions(closure)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"reservation-api/api/dto"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreditService handles class packages and credit wallets
+type CreditService struct {
+	packageRepo    *repository.CreditPackageRepository
+	creditRepo     *repository.CreditRepository
+	userRepo       *repository.UserRepository
+	paymentService *PaymentService
+}
+
+// NewCreditService creates a new credit service
+func NewCreditService(
+	packageRepo *repository.CreditPackageRepository,
+	creditRepo *repository.CreditRepository,
+	userRepo *repository.UserRepository,
+	paymentService *PaymentService,
+) *CreditService {
+	return &CreditService{
+		packageRepo:    packageRepo,
+		creditRepo:     creditRepo,
+		userRepo:       userRepo,
+		paymentService: paymentService,
+	}
+}
+
+// GetPackages gets all purchasable packages
+func (s *CreditService) GetPackages() ([]models.CreditPackage, error) {
+	return s.packageRepo.FindAll()
+}
+
+// CreatePackage creates a new package
+func (s *CreditService) CreatePackage(req dto.CreditPackageRequest) (*models.CreditPackage, error) {
+	pkg := &models.CreditPackage{IsActive: true}
+	applyCreditPackageRequest(pkg, req)
+
+	if err := s.packageRepo.Create(pkg); err != nil {
+		return nil, errors.New("failed to create package")
+	}
+
+	return pkg, nil
+}
+
+// UpdatePackage updates a package. Existing purchases keep their credits and price.
+func (s *CreditService) UpdatePackage(id uint, req dto.CreditPackageRequest) (*models.CreditPackage, error) {
+	pkg, err := s.packageRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("package not found")
+		}
+		return nil, err
+	}
+
+	applyCreditPackageRequest(pkg, req)
+
+	if err := s.packageRepo.Update(pkg); err != nil {
+		return nil, errors.New("failed to update package")
+	}
+
+	return pkg, nil
+}
+
+// DeletePackage deletes a package
+func (s *CreditService) DeletePackage(id uint) error {
+	if _, err := s.packageRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("package not found")
+		}
+		return err
+	}
+
+	return s.packageRepo.Delete(id)
+}
+
+// PurchasePackage starts the purchase of a package. Credits are added once the
+// returned payment is settled.
+func (s *CreditService) PurchasePackage(userID uint, req dto.PurchasePackageRequest) (*models.Payment, string, string, error) {
+	pkg, err := s.packageRepo.FindByID(req.PackageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", "", errors.New("package not found")
+		}
+		return nil, "", "", err
+	}
+
+	if !pkg.IsActive {
+		return nil, "", "", errors.New("package is not available")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, "", "", errors.New("user not found")
+	}
+
+	purchase := &models.CreditPurchase{
+		UserID:    userID,
+		PackageID: pkg.ID,
+		Credits:   pkg.Credits,
+		Price:     pkg.Price,
+		Status:    models.PurchasePending,
+	}
+
+	if err := s.creditRepo.CreatePurchase(purchase); err != nil {
+		return nil, "", "", errors.New("failed to create purchase")
+	}
+	purchase.Package = pkg
+
+	return s.paymentService.CreatePurchasePayment(purchase, user)
+}
+
+// GetWallet gets the usable credit balance and purchases of a user
+func (s *CreditService) GetWallet(userID uint) (int, []models.CreditPurchase, error) {
+	balance, err := s.creditRepo.Balance(userID, time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	purchases, err := s.creditRepo.FindPurchasesByUserID(userID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return balance, purchases, nil
+}
+
+// GetTransactions gets the credit ledger of a user
+func (s *CreditService) GetTransactions(userID uint) ([]models.CreditTransaction, error) {
+	return s.creditRepo.FindTransactionsByUserID(userID)
+}
+
+// applyCreditPackageRequest copies a request onto a package
+func applyCreditPackageRequest(pkg *models.CreditPackage, req dto.CreditPackageRequest) {
+	pkg.Name = req.Name
+	pkg.Description = req.Description
+	pkg.Credits = req.Credits
+	pkg.Price = req.Price
+	pkg.ValidityDays = req.ValidityDays
+	if req.IsActive != nil {
+		pkg.IsActive = *req.IsActive
+	}
+}
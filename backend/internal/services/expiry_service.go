@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"log"
+	"reservation-api/internal/config"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+)
+
+// ExpiryService releases seats held by reservations that were never paid
+type ExpiryService struct {
+	reservationRepo     *repository.ReservationRepository
+	waitlistService     *WaitlistService
+	notificationService *NotificationService
+	config              *config.Config
+	clock               utils.Clock
+}
+
+// NewExpiryService creates a new expiry service
+func NewExpiryService(
+	reservationRepo *repository.ReservationRepository,
+	waitlistService *WaitlistService,
+	notificationService *NotificationService,
+	cfg *config.Config,
+	clock utils.Clock,
+) *ExpiryService {
+	return &ExpiryService{
+		reservationRepo:     reservationRepo,
+		waitlistService:     waitlistService,
+		notificationService: notificationService,
+		config:              cfg,
+		clock:               clock,
+	}
+}
+
+// ExpireHolds cancels pending reservations past their hold window and marks
+// their payments as expired, offering the freed seats to the waitlist and notifying
+// the users
+func (s *ExpiryService) ExpireHolds(ctx context.Context) error {
+	now := s.clock.Now()
+
+	reservations, err := s.reservationRepo.FindExpiredHolds(now, now.Add(-s.config.HoldExpiry))
+	if err != nil {
+		return err
+	}
+
+	expiredCount := 0
+	for _, reservation := range reservations {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		expired, err := s.reservationRepo.ExpireHold(reservation.ID)
+		if err != nil {
+			log.Printf("⚠️  Failed to expire reservation %d: %v", reservation.ID, err)
+			continue
+		}
+		if expired {
+			expiredCount++
+			s.notificationService.ReservationCancelled(reservation.ID, "The payment was not received in time")
+			s.waitlistService.ReleaseSeat(&reservation)
+		}
+	}
+
+	if expiredCount > 0 {
+		log.Printf("⌛ Expired %d unpaid reservation(s)", expiredCount)
+	}
+
+	return nil
+}
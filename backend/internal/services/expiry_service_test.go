@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"reservation-api/internal/models"
+	"reservation-api/internal/notifier"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/testdb"
+	"testing"
+	"time"
+)
+
+func TestExpireHolds(t *testing.T) {
+	db := testdb.Open(t)
+	cfg := testConfig(jakarta)
+	clock := &fixedClock{now: time.Date(2030, 1, 6, 9, 0, 0, 0, jakarta)}
+
+	reservationRepo := repository.NewReservationRepository(db)
+	notificationService := NewNotificationService(repository.NewOutboxRepository(db), reservationRepo, notifier.Notifiers{}, cfg, clock)
+	waitlistService := NewWaitlistService(repository.NewWaitlistRepository(db), reservationRepo, repository.NewSessionRepository(db), notificationService, cfg, clock)
+	service := NewExpiryService(reservationRepo, waitlistService, notificationService, cfg, clock)
+
+	user := seedUser(t, db, "member@example.com")
+	session := seedSession(t, db, 10, time.Date(2030, 1, 8, 0, 0, 0, 0, time.UTC), "07:00")
+	now := clock.Now()
+
+	stale := seedReservation(t, db, user, session, models.StatusPending, now.Add(-31*time.Minute))
+	fresh := seedReservation(t, db, user, session, models.StatusPending, now.Add(-10*time.Minute))
+
+	claimed := seedReservation(t, db, user, session, models.StatusPending, now.Add(-5*time.Minute))
+	holdUntil := now.Add(-time.Minute)
+	if err := db.Model(claimed).Update("hold_until", holdUntil).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// The payment expiry overrides the hold window
+	paying := seedReservation(t, db, user, session, models.StatusPending, now.Add(-40*time.Minute))
+	paymentExpiry := now.Add(10 * time.Minute)
+	payment := seedPayment(t, db, paying, models.PaymentPending, &paymentExpiry)
+
+	confirmed := seedReservation(t, db, user, session, models.StatusConfirmed, now.Add(-2*time.Hour))
+
+	expired, err := reservationRepo.FindExpiredHolds(now, now.Add(-cfg.HoldExpiry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[uint]bool{}
+	for _, reservation := range expired {
+		got[reservation.ID] = true
+	}
+	if len(got) != 2 || !got[stale.ID] || !got[claimed.ID] {
+		t.Fatalf("FindExpiredHolds = %v, want reservations %d and %d", got, stale.ID, claimed.ID)
+	}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		want    map[uint]models.ReservationStatus
+	}{
+		{"now", 0, map[uint]models.ReservationStatus{
+			stale.ID:     models.StatusCancelled,
+			fresh.ID:     models.StatusPending,
+			claimed.ID:   models.StatusCancelled,
+			paying.ID:    models.StatusPending,
+			confirmed.ID: models.StatusConfirmed,
+		}},
+		{"after payment expiry", 15 * time.Minute, map[uint]models.ReservationStatus{
+			fresh.ID:     models.StatusPending,
+			paying.ID:    models.StatusCancelled,
+			confirmed.ID: models.StatusConfirmed,
+		}},
+		{"after hold window", 10 * time.Minute, map[uint]models.ReservationStatus{
+			fresh.ID:     models.StatusCancelled,
+			confirmed.ID: models.StatusConfirmed,
+		}},
+	}
+
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		if err := service.ExpireHolds(context.Background()); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		for id, want := range step.want {
+			var reservation models.Reservation
+			if err := db.First(&reservation, id).Error; err != nil {
+				t.Fatal(err)
+			}
+			if reservation.Status != want {
+				t.Errorf("%s: reservation %d is %s, want %s", step.name, id, reservation.Status, want)
+			}
+		}
+	}
+
+	if err := db.First(payment, payment.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != models.PaymentExpired {
+		t.Errorf("payment is %s, want %s", payment.Status, models.PaymentExpired)
+	}
+}
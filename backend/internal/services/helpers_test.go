@@ -0,0 +1,235 @@
+package services
+
+import (
+	"fmt"
+	"reservation-api/internal/config"
+	"reservation-api/internal/gateway"
+	"reservation-api/internal/loginlimit"
+	"reservation-api/internal/models"
+	"reservation-api/internal/notifier"
+	"reservation-api/internal/oidc"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/testdb"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fixedClock is a Clock stopped at a given time, moved on with Advance
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fixedClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// jakarta is the studio time zone used by the tests
+var jakarta = mustLoadLocation("Asia/Jakarta")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// testConfig returns the default configuration with the studio in loc
+func testConfig(loc *time.Location) *config.Config {
+	return &config.Config{
+		AppEnv:                  "test",
+		Location:                loc,
+		JWTSecret:               "test-secret",
+		AccessTokenTTL:          15 * time.Minute,
+		RefreshTokenTTL:         30 * 24 * time.Hour,
+		PaymentGateway:          "fake",
+		DefaultSessionPrice:     100000,
+		HoldExpiry:              30 * time.Minute,
+		HoldCheckInterval:       time.Minute,
+		FullRefundWindow:        24 * time.Hour,
+		PartialRefundPercent:    50,
+		BookingMaxAdvanceDays:   30,
+		AllowLateCancel:         true,
+		CheckInOpens:            time.Hour,
+		NoShowWindowDays:        30,
+		WaitlistClaimWindow:     time.Hour,
+		MembershipRenewalLead:   3 * 24 * time.Hour,
+		MembershipCheckInterval: time.Hour,
+		SessionHorizon:          30,
+		SessionGenerateInterval: time.Hour,
+		StudioName:              "Pilates Studio",
+		DefaultCountryCode:      "62",
+		ReminderLead:            24 * time.Hour,
+		OutboxInterval:          30 * time.Second,
+		NotificationMaxAttempts: 8,
+	}
+}
+
+// seedUser creates a member with the given email address
+func seedUser(t *testing.T, db *gorm.DB, email string) *models.User {
+	t.Helper()
+	user := &models.User{Name: "Member", Email: email, Password: "x", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// seedSession creates a scheduled class at the given local date and time. Its court
+// and timeslot are loaded.
+func seedSession(t *testing.T, db *gorm.DB, capacity int, date time.Time, at string) *models.ClassSession {
+	t.Helper()
+	court := &models.Court{Name: "Reformer", Capacity: capacity, IsActive: true}
+	if err := db.Create(court).Error; err != nil {
+		t.Fatal(err)
+	}
+	timeslot := &models.Timeslot{Time: at, Duration: 60, IsActive: true}
+	if err := db.Create(timeslot).Error; err != nil {
+		t.Fatal(err)
+	}
+	session := &models.ClassSession{
+		CourtID:    court.ID,
+		TimeslotID: timeslot.ID,
+		Date:       time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Status:     models.SessionScheduled,
+	}
+	if err := db.Create(session).Error; err != nil {
+		t.Fatal(err)
+	}
+	session.Court = court
+	session.Timeslot = timeslot
+	return session
+}
+
+// seedReservation books a seat in a session with the given status
+func seedReservation(t *testing.T, db *gorm.DB, user *models.User, session *models.ClassSession, status models.ReservationStatus, createdAt time.Time) *models.Reservation {
+	t.Helper()
+	reservation := &models.Reservation{
+		UserID:     user.ID,
+		SessionID:  &session.ID,
+		CourtID:    session.CourtID,
+		TimeslotID: session.TimeslotID,
+		Date:       session.Date,
+		Status:     status,
+	}
+	reservation.CreatedAt = createdAt
+	if err := db.Create(reservation).Error; err != nil {
+		t.Fatal(err)
+	}
+	return reservation
+}
+
+// seedPayment creates a payment of a reservation
+func seedPayment(t *testing.T, db *gorm.DB, reservation *models.Reservation, status models.PaymentStatus, expiredAt *time.Time) *models.Payment {
+	t.Helper()
+	payment := &models.Payment{
+		UserID:        reservation.UserID,
+		ReservationID: &reservation.ID,
+		Amount:        100000,
+		Status:        status,
+		TransactionID: fmt.Sprintf("RES-%d", reservation.ID),
+		ExpiredAt:     expiredAt,
+	}
+	if err := db.Create(payment).Error; err != nil {
+		t.Fatal(err)
+	}
+	return payment
+}
+
+// testServices wires the services on a test database the way the routes do, with a
+// fake payment gateway and fake notifiers
+type testServices struct {
+	db        *gorm.DB
+	config    *config.Config
+	clock     *fixedClock
+	gateway   *gateway.FakeGateway
+	notifiers notifier.Notifiers
+
+	reservationRepo *repository.ReservationRepository
+	paymentRepo     *repository.PaymentRepository
+	membershipRepo  *repository.MembershipRepository
+	outboxRepo      *repository.OutboxRepository
+
+	notifications *NotificationService
+	waitlist      *WaitlistService
+	auth          *AuthService
+	refunds       *RefundService
+	attendance    *AttendanceService
+	reservations  *ReservationService
+	payments      *PaymentService
+	memberships   *MembershipService
+	sessions      *SessionService
+	expiry        *ExpiryService
+}
+
+// newTestServices opens a test database and wires the services on it. The test is
+// skipped when no test database is configured.
+func newTestServices(t *testing.T, cfg *config.Config, now time.Time) *testServices {
+	t.Helper()
+	db := testdb.Open(t)
+	clock := &fixedClock{now: now}
+
+	userRepo := repository.NewUserRepository(db)
+	courtRepo := repository.NewCourtRepository(db)
+	timeslotRepo := repository.NewTimeslotRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	paymentNotificationRepo := repository.NewPaymentNotificationRepository(db)
+	refundRepo := repository.NewRefundRepository(db)
+	pricingRuleRepo := repository.NewPricingRuleRepository(db)
+	creditRepo := repository.NewCreditRepository(db)
+	membershipPlanRepo := repository.NewMembershipPlanRepository(db)
+	membershipRepo := repository.NewMembershipRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	instructorRepo := repository.NewInstructorRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	closureRepo := repository.NewClosureRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+
+	paymentGateway := gateway.NewFakeGateway(cfg.FrontendURL)
+	notifiers := notifier.Notifiers{
+		models.ChannelEmail:    notifier.NewFakeNotifier(models.ChannelEmail),
+		models.ChannelSMS:      notifier.NewFakeNotifier(models.ChannelSMS),
+		models.ChannelWhatsApp: notifier.NewFakeNotifier(models.ChannelWhatsApp),
+	}
+	loginLimiter := loginlimit.NewFromConfig(cfg, loginlimit.NewMemoryStore())
+
+	s := &testServices{
+		db:              db,
+		config:          cfg,
+		clock:           clock,
+		gateway:         paymentGateway,
+		notifiers:       notifiers,
+		reservationRepo: reservationRepo,
+		paymentRepo:     paymentRepo,
+		membershipRepo:  membershipRepo,
+		outboxRepo:      outboxRepo,
+	}
+
+	pricingService := NewPricingService(pricingRuleRepo, sessionRepo, cfg)
+	s.notifications = NewNotificationService(outboxRepo, reservationRepo, notifiers, cfg, clock)
+	s.waitlist = NewWaitlistService(waitlistRepo, reservationRepo, sessionRepo, s.notifications, cfg, clock)
+	s.auth = NewAuthService(userRepo, repository.NewAuthSessionRepository(db), repository.NewUserTokenRepository(db),
+		repository.NewLoginFailureRepository(db), repository.NewUserIdentityRepository(db), reservationRepo,
+		membershipRepo, s.waitlist, s.notifications, loginLimiter, oidc.New(cfg), cfg, clock)
+	s.refunds = NewRefundService(refundRepo, paymentRepo, s.notifications, paymentGateway, cfg, clock)
+	ticketService := NewTicketService(reservationRepo, cfg)
+	s.attendance = NewAttendanceService(reservationRepo, sessionRepo, ticketService, cfg, clock)
+	s.reservations = NewReservationService(reservationRepo, userRepo, sessionRepo, closureRepo, creditRepo, membershipRepo,
+		s.refunds, s.waitlist, s.attendance, s.notifications, cfg, clock)
+	s.payments = NewPaymentService(paymentRepo, reservationRepo, paymentNotificationRepo, creditRepo, membershipRepo,
+		pricingService, s.waitlist, s.notifications, s.refunds, paymentGateway, cfg, clock)
+	s.memberships = NewMembershipService(membershipPlanRepo, membershipRepo, userRepo, s.payments, s.notifications, cfg, clock)
+	instructorService := NewInstructorService(instructorRepo, sessionRepo, reservationRepo, userRepo, cfg)
+	s.sessions = NewSessionService(sessionRepo, closureRepo, reservationRepo, courtRepo, timeslotRepo, creditRepo,
+		instructorService, s.refunds, s.waitlist, s.notifications, cfg, clock)
+	s.expiry = NewExpiryService(reservationRepo, s.waitlist, s.notifications, cfg, clock)
+
+	return s
+}
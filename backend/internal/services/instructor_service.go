@@ -0,0 +1,322 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InstructorService handles instructors, their class assignments and rosters
+type InstructorService struct {
+	instructorRepo  *repository.InstructorRepository
+	sessionRepo     *repository.SessionRepository
+	reservationRepo *repository.ReservationRepository
+	userRepo        *repository.UserRepository
+	config          *config.Config
+}
+
+// NewInstructorService creates a new instructor service
+func NewInstructorService(
+	instructorRepo *repository.InstructorRepository,
+	sessionRepo *repository.SessionRepository,
+	reservationRepo *repository.ReservationRepository,
+	userRepo *repository.UserRepository,
+	cfg *config.Config,
+) *InstructorService {
+	return &InstructorService{
+		instructorRepo:  instructorRepo,
+		sessionRepo:     sessionRepo,
+		reservationRepo: reservationRepo,
+		userRepo:        userRepo,
+		config:          cfg,
+	}
+}
+
+// GetInstructors gets instructors, optionally only active ones
+func (s *InstructorService) GetInstructors(activeOnly bool) ([]models.Instructor, error) {
+	return s.instructorRepo.FindAll(activeOnly)
+}
+
+// GetInstructor gets an instructor by ID
+func (s *InstructorService) GetInstructor(id uint) (*models.Instructor, error) {
+	instructor, err := s.instructorRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("instructor not found")
+		}
+		return nil, err
+	}
+	return instructor, nil
+}
+
+// CreateInstructor creates an instructor profile for a user and gives the user the
+// instructor role
+func (s *InstructorService) CreateInstructor(req dto.InstructorRequest) (*models.Instructor, error) {
+	if req.UserID == 0 {
+		return nil, errors.New("user_id is required")
+	}
+
+	user, err := s.userRepo.FindByID(req.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if _, err := s.instructorRepo.FindByUserID(user.ID); err == nil {
+		return nil, errors.New("user already has an instructor profile")
+	}
+
+	instructor := &models.Instructor{UserID: user.ID, Name: user.Name, IsActive: true}
+	if err := applyInstructorRequest(instructor, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.instructorRepo.Create(instructor); err != nil {
+		return nil, errors.New("failed to create instructor")
+	}
+
+	// Admins keep their role; everyone else becomes an instructor
+	if user.Role != models.RoleAdmin && user.Role != models.RoleInstructor {
+		user.Role = models.RoleInstructor
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, errors.New("failed to update user role")
+		}
+	}
+
+	return instructor, nil
+}
+
+// UpdateInstructor updates an instructor profile
+func (s *InstructorService) UpdateInstructor(id uint, req dto.InstructorRequest) (*models.Instructor, error) {
+	instructor, err := s.instructorRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("instructor not found")
+		}
+		return nil, err
+	}
+
+	if err := applyInstructorRequest(instructor, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.instructorRepo.Update(instructor); err != nil {
+		return nil, errors.New("failed to update instructor")
+	}
+
+	return instructor, nil
+}
+
+// DeleteInstructor deletes an instructor and unassigns them from their classes
+func (s *InstructorService) DeleteInstructor(id uint) error {
+	if _, err := s.instructorRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("instructor not found")
+		}
+		return err
+	}
+
+	return s.instructorRepo.Delete(id)
+}
+
+// AssignInstructor assigns an instructor to the session of a court and timeslot on
+// a date, replacing any previous instructor
+func (s *InstructorService) AssignInstructor(req dto.ClassAssignmentRequest) (*models.ClassSession, error) {
+	session, err := s.findSession(req.CourtID, req.TimeslotID, req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	instructor, err := s.CheckCanTeach(req.InstructorID, session)
+	if err != nil {
+		return nil, err
+	}
+
+	session.InstructorID = &instructor.ID
+	if err := s.sessionRepo.Update(session); err != nil {
+		return nil, errors.New("failed to assign instructor")
+	}
+
+	session.Instructor = instructor
+	return session, nil
+}
+
+// UnassignInstructor removes the instructor of the session of a court and timeslot on a date
+func (s *InstructorService) UnassignInstructor(courtID, timeslotID uint, dateStr string) error {
+	session, err := s.findSession(courtID, timeslotID, dateStr)
+	if err != nil {
+		return err
+	}
+
+	if session.InstructorID == nil {
+		return errors.New("class has no instructor assigned")
+	}
+
+	session.InstructorID = nil
+	if err := s.sessionRepo.Update(session); err != nil {
+		return errors.New("failed to unassign instructor")
+	}
+
+	return nil
+}
+
+// CheckCanTeach checks that an instructor can teach a session: the instructor must be
+// active, available at the session time and not teach another session at the same
+// time. Session must have its timeslot loaded.
+func (s *InstructorService) CheckCanTeach(instructorID uint, session *models.ClassSession) (*models.Instructor, error) {
+	instructor, err := s.GetInstructor(instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !instructor.IsActive {
+		return nil, errors.New("instructor is not active")
+	}
+
+	classStart, err := session.StartTime(s.config.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	if !instructor.IsAvailable(classStart, session.Timeslot.Duration) {
+		return nil, errors.New("instructor is not available at this time")
+	}
+
+	// Check for overlapping sessions of the instructor on other courts or timeslots
+	taught, err := s.sessionRepo.FindByInstructor(instructor.ID, session.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	classEnd, _ := session.EndTime(s.config.Location)
+	for i := range taught {
+		other := &taught[i]
+		if other.ID == session.ID || other.IsCancelled() {
+			continue
+		}
+		otherStart, err := other.StartTime(s.config.Location)
+		if err != nil {
+			continue
+		}
+		otherEnd, _ := other.EndTime(s.config.Location)
+		if classStart.Before(otherEnd) && otherStart.Before(classEnd) {
+			return nil, fmt.Errorf("instructor already teaches in %s at %s", other.Court.Name, other.Timeslot.Time)
+		}
+	}
+
+	return instructor, nil
+}
+
+// findSession finds the session of a court and timeslot on a date
+func (s *InstructorService) findSession(courtID, timeslotID uint, dateStr string) (*models.ClassSession, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+
+	session, err := s.sessionRepo.FindByClass(courtID, timeslotID, date)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class not found")
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetRoster gets the classes an instructor user teaches on a date with their attendees
+func (s *InstructorService) GetRoster(userID uint, dateStr string) ([]dto.RosterClass, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+
+	instructor, err := s.instructorRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("instructor profile not found")
+		}
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.FindByInstructor(instructor.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	roster := make([]dto.RosterClass, 0, len(sessions))
+	for i := range sessions {
+		session := &sessions[i]
+		reservations, err := s.reservationRepo.FindByClass(session.CourtID, session.TimeslotID, date)
+		if err != nil {
+			return nil, err
+		}
+
+		roster = append(roster, buildRosterClass(session, reservations))
+	}
+
+	return roster, nil
+}
+
+// buildRosterClass builds the roster of a session from its reservations
+func buildRosterClass(session *models.ClassSession, reservations []models.Reservation) dto.RosterClass {
+	class := dto.RosterClass{
+		SessionID:  session.ID,
+		Status:     string(session.Status),
+		CourtID:    session.CourtID,
+		CourtName:  session.Court.Name,
+		TimeslotID: session.TimeslotID,
+		Time:       session.Timeslot.Time,
+		Duration:   session.Timeslot.Duration,
+		Date:       session.Date.Format("2006-01-02"),
+		Capacity:   session.SeatCapacity(),
+		Attendees:  make([]dto.RosterAttendee, 0, len(reservations)),
+	}
+
+	for _, reservation := range reservations {
+		class.Attendees = append(class.Attendees, dto.RosterAttendee{
+			ReservationID: reservation.ID,
+			UserID:        reservation.UserID,
+			Name:          reservation.User.Name,
+			Email:         reservation.User.Email,
+			Phone:         reservation.User.Phone,
+			Status:        string(reservation.Status),
+			CheckedInAt:   reservation.CheckedInAt,
+			Notes:         reservation.Notes,
+		})
+	}
+
+	return class
+}
+
+// applyInstructorRequest copies a request onto an instructor
+func applyInstructorRequest(instructor *models.Instructor, req dto.InstructorRequest) error {
+	availability := make(models.WeeklyAvailability, 0, len(req.Availability))
+	for _, window := range req.Availability {
+		availability = append(availability, models.AvailabilityWindow{
+			Weekday: window.Weekday,
+			Start:   window.Start,
+			End:     window.End,
+		})
+	}
+	if err := models.ValidateAvailability(availability); err != nil {
+		return err
+	}
+
+	if req.Name != "" {
+		instructor.Name = req.Name
+	}
+	instructor.Bio = req.Bio
+	instructor.Specialties = req.Specialties
+	instructor.Availability = availability
+	if req.IsActive != nil {
+		instructor.IsActive = *req.IsActive
+	}
+	return nil
+}
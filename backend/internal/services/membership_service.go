@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// MembershipService handles membership plans, signups and monthly renewals
+type MembershipService struct {
+	planRepo            *repository.MembershipPlanRepository
+	membershipRepo      *repository.MembershipRepository
+	userRepo            *repository.UserRepository
+	paymentService      *PaymentService
+	notificationService *NotificationService
+	config              *config.Config
+	clock               utils.Clock
+}
+
+// NewMembershipService creates a new membership service
+func NewMembershipService(
+	planRepo *repository.MembershipPlanRepository,
+	membershipRepo *repository.MembershipRepository,
+	userRepo *repository.UserRepository,
+	paymentService *PaymentService,
+	notificationService *NotificationService,
+	cfg *config.Config,
+	clock utils.Clock,
+) *MembershipService {
+	return &MembershipService{
+		planRepo:            planRepo,
+		membershipRepo:      membershipRepo,
+		userRepo:            userRepo,
+		paymentService:      paymentService,
+		notificationService: notificationService,
+		config:              cfg,
+		clock:               clock,
+	}
+}
+
+// GetPlans gets all membership plans open for signup
+func (s *MembershipService) GetPlans() ([]models.MembershipPlan, error) {
+	return s.planRepo.FindAll()
+}
+
+// CreatePlan creates a new membership plan
+func (s *MembershipService) CreatePlan(req dto.MembershipPlanRequest) (*models.MembershipPlan, error) {
+	plan := &models.MembershipPlan{IsActive: true}
+	applyMembershipPlanRequest(plan, req)
+
+	if err := s.planRepo.Create(plan); err != nil {
+		return nil, errors.New("failed to create membership plan")
+	}
+
+	return plan, nil
+}
+
+// UpdatePlan updates a membership plan. Existing memberships keep their price;
+// booking limit changes apply to them right away.
+func (s *MembershipService) UpdatePlan(id uint, req dto.MembershipPlanRequest) (*models.MembershipPlan, error) {
+	plan, err := s.planRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("membership plan not found")
+		}
+		return nil, err
+	}
+
+	applyMembershipPlanRequest(plan, req)
+
+	if err := s.planRepo.Update(plan); err != nil {
+		return nil, errors.New("failed to update membership plan")
+	}
+
+	return plan, nil
+}
+
+// DeletePlan deletes a membership plan
+func (s *MembershipService) DeletePlan(id uint) error {
+	if _, err := s.planRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("membership plan not found")
+		}
+		return err
+	}
+
+	return s.planRepo.Delete(id)
+}
+
+// Subscribe signs a user up to a plan. The membership starts once the returned
+// payment for the first month is settled.
+func (s *MembershipService) Subscribe(userID uint, req dto.SubscribeRequest) (*models.Payment, string, string, error) {
+	plan, err := s.planRepo.FindByID(req.PlanID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", "", errors.New("membership plan not found")
+		}
+		return nil, "", "", err
+	}
+
+	if !plan.IsActive {
+		return nil, "", "", errors.New("membership plan is not available")
+	}
+
+	open, err := s.membershipRepo.HasOpenMembership(userID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if open {
+		return nil, "", "", errors.New("you already have a membership")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, "", "", errors.New("user not found")
+	}
+
+	membership := &models.Membership{
+		UserID:    userID,
+		PlanID:    plan.ID,
+		Price:     plan.Price,
+		Status:    models.MembershipPending,
+		AutoRenew: req.AutoRenew == nil || *req.AutoRenew,
+	}
+
+	if err := s.membershipRepo.Create(membership); err != nil {
+		return nil, "", "", errors.New("failed to create membership")
+	}
+	membership.Plan = plan
+
+	return s.paymentService.CreateMembershipPayment(membership, user, s.clock.Now().Add(s.config.HoldExpiry))
+}
+
+// GetMemberships gets all memberships of a user with their payments
+func (s *MembershipService) GetMemberships(userID uint) ([]models.Membership, error) {
+	return s.membershipRepo.FindByUserID(userID)
+}
+
+// Renew creates a payment for the next month of a suspended or expired membership,
+// restoring booking rights once it is settled
+func (s *MembershipService) Renew(id, userID uint) (*models.Payment, string, string, error) {
+	membership, err := s.getOwnedMembership(id, userID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if membership.Status != models.MembershipSuspended && membership.Status != models.MembershipExpired {
+		return nil, "", "", errors.New("only suspended or expired memberships can be renewed")
+	}
+
+	return s.paymentService.CreateMembershipPayment(membership, membership.User, s.clock.Now().Add(s.config.HoldExpiry))
+}
+
+// UpdateAutoRenew turns monthly renewal of a membership on or off. Without
+// auto-renew the membership expires at the end of the paid month.
+func (s *MembershipService) UpdateAutoRenew(id, userID uint, autoRenew bool) (*models.Membership, error) {
+	membership, err := s.getOwnedMembership(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.membershipRepo.UpdateAutoRenew(membership.ID, autoRenew); err != nil {
+		return nil, errors.New("failed to update membership")
+	}
+
+	return s.membershipRepo.FindByID(membership.ID)
+}
+
+// getOwnedMembership gets a membership, verifying it belongs to the user
+func (s *MembershipService) getOwnedMembership(id, userID uint) (*models.Membership, error) {
+	membership, err := s.membershipRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("membership not found")
+		}
+		return nil, err
+	}
+
+	if membership.UserID != userID {
+		return nil, errors.New("unauthorized access to membership")
+	}
+
+	return membership, nil
+}
+
+// RenewMemberships creates renewal payments for auto-renewing memberships close to
+// the end of their paid month and sends members the payment link. It also lapses
+// memberships whose month ended unpaid: auto-renewing ones are suspended until paid,
+// others expire.
+func (s *MembershipService) RenewMemberships(ctx context.Context) error {
+	now := s.clock.Now()
+
+	due, err := s.membershipRepo.FindDueForRenewal(now.Add(s.config.MembershipRenewalLead))
+	if err != nil {
+		return err
+	}
+
+	for i := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// The renewal can be paid until the current month ends
+		membership := &due[i]
+		expiredAt := *membership.EndDate
+		if minExpiry := now.Add(s.config.HoldExpiry); expiredAt.Before(minExpiry) {
+			expiredAt = minExpiry
+		}
+
+		payment, paymentURL, _, err := s.paymentService.CreateMembershipPayment(membership, membership.User, expiredAt)
+		if err != nil {
+			log.Printf("⚠️  Failed to create renewal payment for membership %d: %v", membership.ID, err)
+			continue
+		}
+		log.Printf("🔁 Created renewal payment %s for membership %d", payment.TransactionID, membership.ID)
+
+		s.notificationService.MembershipRenewalDue(membership, payment, paymentURL)
+	}
+
+	lapsed, err := s.membershipRepo.FindLapsed(now)
+	if err != nil {
+		return err
+	}
+
+	lapsedCount := 0
+	for _, membership := range lapsed {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		ok, err := s.membershipRepo.Lapse(membership.ID, now)
+		if err != nil {
+			log.Printf("⚠️  Failed to lapse membership %d: %v", membership.ID, err)
+			continue
+		}
+		if ok {
+			lapsedCount++
+		}
+	}
+
+	if lapsedCount > 0 {
+		log.Printf("⌛ Lapsed %d unpaid membership(s)", lapsedCount)
+	}
+
+	return nil
+}
+
+// applyMembershipPlanRequest copies a request onto a plan
+func applyMembershipPlanRequest(plan *models.MembershipPlan, req dto.MembershipPlanRequest) {
+	plan.Name = req.Name
+	plan.Description = req.Description
+	plan.Price = req.Price
+	plan.WeeklyBookingLimit = req.WeeklyBookingLimit
+	if req.IsActive != nil {
+		plan.IsActive = *req.IsActive
+	}
+}
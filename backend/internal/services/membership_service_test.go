@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"reservation-api/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenewMembershipsSendsPaymentLink(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	now := s.clock.Now()
+
+	user := seedUser(t, s.db, "renewing@example.com")
+	plan := &models.MembershipPlan{Name: "Unlimited", Price: 900000, IsActive: true}
+	if err := s.db.Create(plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	start, end := now.AddDate(0, -1, 1), now.AddDate(0, 0, 1)
+	membership := &models.Membership{UserID: user.ID, PlanID: plan.ID, Price: plan.Price, Status: models.MembershipActive, AutoRenew: true, StartDate: &start, EndDate: &end}
+	if err := s.db.Create(membership).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// A second run must neither create another payment nor send the link again
+	for i := 0; i < 2; i++ {
+		if err := s.memberships.RenewMemberships(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var payments []models.Payment
+	if err := s.db.Where("membership_id = ?", membership.ID).Find(&payments).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 1 || payments[0].Status != models.PaymentPending {
+		t.Fatalf("renewal payments = %+v, want one pending payment", payments)
+	}
+
+	var messages []models.OutboxMessage
+	if err := s.db.Where("event = ?", EventMembershipRenewal).Find(&messages).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("%d renewal message(s) queued, want 1", len(messages))
+	}
+	message := messages[0]
+	if message.Recipient != user.Email || message.Channel != models.ChannelEmail {
+		t.Errorf("message to %s over %s, want %s over email", message.Recipient, message.Channel, user.Email)
+	}
+	if !strings.Contains(message.Body, "transaction_id="+payments[0].TransactionID) {
+		t.Errorf("message body has no payment link:\n%s", message.Body)
+	}
+	if !strings.Contains(message.Body, "Rp 900.000") {
+		t.Errorf("message body has no amount:\n%s", message.Body)
+	}
+}
@@ -0,0 +1,439 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/notifier"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification events, named after their templates
+const (
+	EventBookingCreated       = "booking_created"
+	EventPaymentConfirmed     = "payment_confirmed"
+	EventReminder             = "reminder"
+	EventReservationCancelled = "reservation_cancelled"
+	EventRefundIssued         = "refund_issued"
+	EventWaitlistOffered      = "waitlist_offered"
+	EventPasswordReset        = "password_reset"
+	EventEmailVerification    = "email_verification"
+	EventMembershipRenewal    = "membership_renewal"
+)
+
+const (
+	outboxBatchSize  = 50               // Messages delivered per run
+	outboxLease      = 5 * time.Minute  // How long a claimed message is not claimed again
+	outboxMaxBackoff = 60 * time.Minute // Longest wait between delivery attempts
+)
+
+// NotificationService notifies users about their bookings over the channels they
+// chose: email, SMS and WhatsApp. Messages are rendered and stored in the outbox
+// when an event happens and delivered by a background job, so failed sends are
+// retried and pending messages survive restarts.
+type NotificationService struct {
+	outboxRepo      *repository.OutboxRepository
+	reservationRepo *repository.ReservationRepository
+	notifiers       notifier.Notifiers
+	config          *config.Config
+	clock           utils.Clock
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	outboxRepo *repository.OutboxRepository,
+	reservationRepo *repository.ReservationRepository,
+	notifiers notifier.Notifiers,
+	cfg *config.Config,
+	clock utils.Clock,
+) *NotificationService {
+	return &NotificationService{
+		outboxRepo:      outboxRepo,
+		reservationRepo: reservationRepo,
+		notifiers:       notifiers,
+		config:          cfg,
+		clock:           clock,
+	}
+}
+
+// messageData is the data available to message templates
+type messageData struct {
+	Studio        string
+	Name          string
+	ReservationID uint
+	Class         string
+	Date          string
+	Time          string
+	Instructor    string
+	Confirmed     bool
+	Amount        string
+	PayBy         string
+	CancelBy      string
+	Reason        string
+	Plan          string
+	URL           string
+	ValidFor      string
+}
+
+// BookingCreated notifies the confirmation of a new booking, or the payment deadline
+// if it still has to be paid
+func (s *NotificationService) BookingCreated(reservationID uint) {
+	s.notify(reservationID, EventBookingCreated, "", func(reservation *models.Reservation, data *messageData) {
+		data.PayBy = s.formatTime(reservation.HoldDeadline(s.config.HoldExpiry))
+	})
+}
+
+// PaymentConfirmed notifies the confirmation of a paid booking
+func (s *NotificationService) PaymentConfirmed(reservationID uint) {
+	s.notify(reservationID, EventPaymentConfirmed, "", func(reservation *models.Reservation, data *messageData) {
+		if reservation.Payment != nil {
+			data.Amount = formatRupiah(reservation.Payment.Amount)
+		}
+	})
+}
+
+// ReservationCancelled notifies that a booking was cancelled and why
+func (s *NotificationService) ReservationCancelled(reservationID uint, reason string) {
+	s.notify(reservationID, EventReservationCancelled, "", func(reservation *models.Reservation, data *messageData) {
+		data.Reason = strings.TrimSuffix(reason, ".")
+	})
+}
+
+// RefundIssued notifies that a refund of a booking went through
+func (s *NotificationService) RefundIssued(reservationID uint, refund *models.Refund) {
+	key := fmt.Sprintf("%s:%d", EventRefundIssued, refund.ID)
+	s.notify(reservationID, EventRefundIssued, key, func(reservation *models.Reservation, data *messageData) {
+		data.Amount = formatRupiah(refund.Amount)
+		data.Reason = refund.Reason
+	})
+}
+
+// WaitlistOffered notifies that a seat from the waitlist is held for the user
+func (s *NotificationService) WaitlistOffered(reservationID uint) {
+	s.notify(reservationID, EventWaitlistOffered, "", func(reservation *models.Reservation, data *messageData) {
+		data.PayBy = s.formatTime(reservation.HoldDeadline(s.config.HoldExpiry))
+	})
+}
+
+// MembershipRenewalDue sends the payment link of a membership renewal. The
+// membership's User and Plan must be loaded.
+func (s *NotificationService) MembershipRenewalDue(membership *models.Membership, payment *models.Payment, paymentURL string) {
+	data := messageData{
+		Studio: s.config.StudioName,
+		Name:   membership.User.Name,
+		Plan:   membership.Plan.Name,
+		Amount: formatRupiah(payment.Amount),
+		URL:    paymentURL,
+	}
+	if payment.ExpiredAt != nil {
+		data.PayBy = s.formatTime(*payment.ExpiredAt)
+	}
+
+	s.enqueue(membership.User, EventMembershipRenewal, fmt.Sprintf("%s:%d", EventMembershipRenewal, payment.ID), data)
+}
+
+// SendAccountEmail queues an email about the account itself, such as a password reset
+// link. It goes to the given address whatever channels the user chose, and is never
+// deduplicated as each one carries a new link.
+func (s *NotificationService) SendAccountEmail(user *models.User, to, event, link string, validFor time.Duration) error {
+	data := messageData{
+		Studio:   s.config.StudioName,
+		Name:     user.Name,
+		URL:      link,
+		ValidFor: formatDuration(validFor),
+	}
+
+	subject, body, err := notifier.Render(event, models.ChannelEmail, data)
+	if err != nil {
+		log.Printf("⚠️  Failed to render %s email for user %d: %v", event, user.ID, err)
+		return errors.New("failed to send email")
+	}
+
+	_, err = s.outboxRepo.Enqueue(&models.OutboxMessage{
+		UserID:        &user.ID,
+		Event:         event,
+		Channel:       models.ChannelEmail,
+		Recipient:     to,
+		Subject:       subject,
+		Body:          body,
+		Status:        models.OutboxPending,
+		NextAttemptAt: s.clock.Now(),
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to queue %s email for user %d: %v", event, user.ID, err)
+		return errors.New("failed to send email")
+	}
+	return nil
+}
+
+// SendReminders queues reminders for confirmed classes starting within the reminder
+// lead. Each reservation gets one reminder per channel.
+func (s *NotificationService) SendReminders(ctx context.Context) error {
+	now := s.clock.Now()
+	until := now.Add(s.config.ReminderLead)
+
+	reservations, err := s.reservationRepo.FindConfirmedBetween(
+		utils.DateOf(now, s.config.Location), utils.DateOf(until, s.config.Location))
+	if err != nil {
+		return err
+	}
+
+	policy := defaultBookingPolicy(s.config)
+	queued := 0
+	for i := range reservations {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		reservation := &reservations[i]
+		classStart, err := reservation.StartTime(s.config.Location)
+		if err != nil || !classStart.After(now) || classStart.After(until) {
+			continue
+		}
+
+		data := s.reservationData(reservation)
+		if deadline := reservation.BookingPolicy(policy).CancelDeadlineAt(classStart); deadline.After(now) {
+			data.CancelBy = s.formatTime(deadline)
+		}
+
+		queued += s.enqueue(&reservation.User, EventReminder, fmt.Sprintf("%s:%d", EventReminder, reservation.ID), data)
+	}
+
+	if queued > 0 {
+		log.Printf("⏰ Queued %d class reminder(s)", queued)
+	}
+
+	return nil
+}
+
+// DeliverOutbox sends the queued messages that are due. Failed sends are retried
+// with a growing delay until the maximum number of attempts is reached.
+func (s *NotificationService) DeliverOutbox(ctx context.Context) error {
+	now := s.clock.Now()
+
+	messages, err := s.outboxRepo.ClaimDue(now, outboxLease, outboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := s.send(&message)
+		if err == nil {
+			if err := s.outboxRepo.MarkSent(message.ID, s.clock.Now()); err != nil {
+				log.Printf("⚠️  Failed to mark notification %d as sent: %v", message.ID, err)
+			}
+			sent++
+			continue
+		}
+
+		attempts := message.Attempts + 1
+		giveUp := attempts >= s.config.NotificationMaxAttempts
+		if giveUp {
+			log.Printf("❌ %s notification %d to %s failed after %d attempt(s): %v",
+				message.Channel, message.ID, message.Recipient, attempts, err)
+		} else {
+			log.Printf("⚠️  %s notification %d to %s failed (attempt %d): %v",
+				message.Channel, message.ID, message.Recipient, attempts, err)
+		}
+
+		if err := s.outboxRepo.MarkAttemptFailed(message.ID, err.Error(), now.Add(retryBackoff(attempts)), giveUp); err != nil {
+			log.Printf("⚠️  Failed to record delivery attempt of notification %d: %v", message.ID, err)
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("📨 Sent %d notification(s)", sent)
+	}
+
+	return nil
+}
+
+// GetMessages gets the latest outbox messages, optionally filtered by status and channel
+func (s *NotificationService) GetMessages(status, channel string) ([]models.OutboxMessage, error) {
+	return s.outboxRepo.FindAll(status, channel, 200)
+}
+
+// RetryMessage queues a failed message for delivery again
+func (s *NotificationService) RetryMessage(id uint) (*models.OutboxMessage, error) {
+	message, err := s.outboxRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification not found")
+		}
+		return nil, err
+	}
+
+	if !message.IsFailed() {
+		return nil, errors.New("only failed notifications can be retried")
+	}
+
+	if _, err := s.outboxRepo.Requeue(id, s.clock.Now()); err != nil {
+		return nil, errors.New("failed to retry notification")
+	}
+
+	return s.outboxRepo.FindByID(id)
+}
+
+// send delivers a message with the notifier of its channel
+func (s *NotificationService) send(message *models.OutboxMessage) error {
+	n, ok := s.notifiers[message.Channel]
+	if !ok {
+		return fmt.Errorf("no notifier for channel %q", message.Channel)
+	}
+
+	return n.Send(notifier.Message{
+		To:      message.Recipient,
+		Subject: message.Subject,
+		Body:    message.Body,
+	})
+}
+
+// notify loads a reservation and queues messages about it. Failures are logged and
+// never fail the operation that triggered the notification. The dedupe key defaults
+// to the event and reservation, so each event is sent once per reservation.
+func (s *NotificationService) notify(reservationID uint, event, dedupeKey string, fill func(*models.Reservation, *messageData)) {
+	reservation, err := s.reservationRepo.FindByID(reservationID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load reservation %d for %s notification: %v", reservationID, event, err)
+		return
+	}
+
+	if dedupeKey == "" {
+		dedupeKey = fmt.Sprintf("%s:%d", event, reservation.ID)
+	}
+
+	data := s.reservationData(reservation)
+	fill(reservation, &data)
+	s.enqueue(&reservation.User, event, dedupeKey, data)
+}
+
+// enqueue renders a template for every channel the user chose and stores the
+// messages in the outbox. Returns the number of new messages queued.
+func (s *NotificationService) enqueue(user *models.User, event, dedupeKey string, data messageData) int {
+	queued := 0
+	for _, channel := range user.Channels() {
+		recipient, err := s.recipient(user, channel)
+		if err != nil {
+			log.Printf("⚠️  No %s recipient for user %d: %v", channel, user.ID, err)
+			continue
+		}
+
+		subject, body, err := notifier.Render(event, channel, data)
+		if err != nil {
+			log.Printf("⚠️  Failed to render %s %s notification for user %d: %v", channel, event, user.ID, err)
+			continue
+		}
+
+		key := dedupeKey + ":" + string(channel)
+		created, err := s.outboxRepo.Enqueue(&models.OutboxMessage{
+			UserID:        &user.ID,
+			Event:         event,
+			Channel:       channel,
+			Recipient:     recipient,
+			Subject:       subject,
+			Body:          body,
+			DedupeKey:     &key,
+			Status:        models.OutboxPending,
+			NextAttemptAt: s.clock.Now(),
+		})
+		if err != nil {
+			log.Printf("⚠️  Failed to queue %s %s notification for user %d: %v", channel, event, user.ID, err)
+			continue
+		}
+		if created {
+			queued++
+		}
+	}
+	return queued
+}
+
+// recipient returns the address of a user on a channel: the email address, or the
+// phone number in international format for SMS and WhatsApp
+func (s *NotificationService) recipient(user *models.User, channel models.NotificationChannel) (string, error) {
+	if channel == models.ChannelEmail {
+		if user.Email == "" {
+			return "", errors.New("no email address")
+		}
+		return user.Email, nil
+	}
+
+	if user.Phone == "" {
+		return "", errors.New("no phone number")
+	}
+	return notifier.NormalizePhone(user.Phone, s.config.DefaultCountryCode)
+}
+
+// reservationData fills the template data describing a reservation. User, Court,
+// Timeslot and Session must be loaded.
+func (s *NotificationService) reservationData(reservation *models.Reservation) messageData {
+	data := messageData{
+		Studio:        s.config.StudioName,
+		Name:          reservation.User.Name,
+		ReservationID: reservation.ID,
+		Class:         reservation.Court.Name,
+		Date:          reservation.Date.Format("Monday, 2 January 2006"),
+		Time:          reservation.Timeslot.Time,
+		Confirmed:     reservation.IsConfirmed(),
+		URL:           strings.TrimRight(s.config.FrontendURL, "/") + "/reservations",
+	}
+	if reservation.Session != nil && reservation.Session.Instructor != nil {
+		data.Instructor = reservation.Session.Instructor.Name
+	}
+	return data
+}
+
+// formatTime formats a time in the studio time zone
+func (s *NotificationService) formatTime(t time.Time) string {
+	return t.In(s.config.Location).Format("2 Jan 2006 15:04 MST")
+}
+
+// retryBackoff returns how long to wait before the next delivery attempt: one minute
+// after the first failure, doubling up to outboxMaxBackoff
+func retryBackoff(attempts int) time.Duration {
+	backoff := time.Minute
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
+
+// formatDuration formats a link lifetime for people, e.g. 60 minutes or 48 hours
+func formatDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		if hours := int(d / time.Hour); hours != 1 {
+			return fmt.Sprintf("%d hours", hours)
+		}
+		return "1 hour"
+	}
+	return fmt.Sprintf("%d minutes", int(d/time.Minute))
+}
+
+// formatRupiah formats an amount in IDR, e.g. Rp 150.000
+func formatRupiah(amount float64) string {
+	digits := strconv.FormatInt(int64(amount+0.5), 10)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	return "Rp " + b.String()
+}
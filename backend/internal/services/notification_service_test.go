@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reservation-api/internal/models"
+	"reservation-api/internal/notifier"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplatesRender(t *testing.T) {
+	events := []string{
+		EventBookingCreated, EventPaymentConfirmed, EventReminder, EventReservationCancelled, EventRefundIssued,
+		EventWaitlistOffered, EventPasswordReset, EventEmailVerification, EventMembershipRenewal,
+	}
+	data := messageData{
+		Studio:        "Pilates Studio",
+		Name:          "Member",
+		ReservationID: 42,
+		Class:         "Reformer",
+		Date:          "Monday, 7 January 2030",
+		Time:          "07:00",
+		Instructor:    "Ayu",
+		Amount:        "Rp 150.000",
+		PayBy:         "7 Jan 2030 06:30 WIB",
+		CancelBy:      "6 Jan 2030 07:00 WIB",
+		Reason:        "Studio closed",
+		Plan:          "Unlimited",
+		URL:           "http://localhost:3000/reservations",
+		ValidFor:      "1 hour",
+	}
+
+	for _, event := range events {
+		for _, channel := range []models.NotificationChannel{models.ChannelEmail, models.ChannelSMS, models.ChannelWhatsApp} {
+			subject, body, err := notifier.Render(event, channel, data)
+			if err != nil {
+				t.Errorf("%s over %s: %v", event, channel, err)
+				continue
+			}
+			if strings.Contains(subject+body, "<no value>") {
+				t.Errorf("%s over %s uses unknown data:\n%s\n%s", event, channel, subject, body)
+			}
+			if channel == models.ChannelEmail {
+				if subject == "" || body == "" {
+					t.Errorf("%s email has no subject or body", event)
+				}
+				continue
+			}
+			if subject != "" || body == "" || strings.Contains(body, "\n") {
+				t.Errorf("%s over %s is not a single line of text: %q", event, channel, body)
+			}
+		}
+	}
+}
+
+func TestNotificationChannels(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+
+	tests := []struct {
+		name  string
+		prefs map[string]interface{}
+		want  map[models.NotificationChannel]string // Recipient by channel
+	}{
+		{
+			name:  "email by default",
+			prefs: map[string]interface{}{"phone": "081234567890"},
+			want:  map[models.NotificationChannel]string{models.ChannelEmail: "email by default"},
+		},
+		{
+			name:  "phone channels only",
+			prefs: map[string]interface{}{"notify_email": false, "notify_sms": true, "notify_whats_app": true, "phone": "0812-3456-7890"},
+			want: map[models.NotificationChannel]string{
+				models.ChannelSMS:      "+6281234567890",
+				models.ChannelWhatsApp: "+6281234567890",
+			},
+		},
+		{
+			name:  "sms without phone",
+			prefs: map[string]interface{}{"notify_sms": true, "phone": ""},
+			want:  map[models.NotificationChannel]string{models.ChannelEmail: "sms without phone"},
+		},
+		{
+			name:  "whatsapp with invalid phone",
+			prefs: map[string]interface{}{"notify_email": false, "notify_whats_app": true, "phone": "call me"},
+			want:  map[models.NotificationChannel]string{},
+		},
+		{
+			name:  "nothing",
+			prefs: map[string]interface{}{"notify_email": false},
+			want:  map[models.NotificationChannel]string{},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := fmt.Sprintf("member%d@example.com", i)
+			user := seedUser(t, s.db, email)
+			if err := s.db.Model(user).Updates(tt.prefs).Error; err != nil {
+				t.Fatal(err)
+			}
+			reservation := seedReservation(t, s.db, user, session, models.StatusConfirmed, now)
+
+			s.notifications.PaymentConfirmed(reservation.ID)
+
+			var messages []models.OutboxMessage
+			if err := s.db.Where("user_id = ?", user.ID).Find(&messages).Error; err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[models.NotificationChannel]string)
+			for _, message := range messages {
+				got[message.Channel] = message.Recipient
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("queued over %v, want %v", got, tt.want)
+			}
+			for channel, recipient := range tt.want {
+				if channel == models.ChannelEmail {
+					recipient = email
+				}
+				if got[channel] != recipient {
+					t.Errorf("%s message to %q, want %q", channel, got[channel], recipient)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationDedupe(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+
+	user := seedUser(t, s.db, "member@example.com")
+	if err := s.db.Model(user).Updates(map[string]interface{}{"notify_sms": true, "phone": "081234567890"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	reservation := seedReservation(t, s.db, user, session, models.StatusConfirmed, now)
+
+	// Each event is queued once per channel however often it fires
+	s.notifications.PaymentConfirmed(reservation.ID)
+	s.notifications.PaymentConfirmed(reservation.ID)
+
+	// Every refund is its own event
+	payment := seedPayment(t, s.db, reservation, models.PaymentPaid, nil)
+	for i := 0; i < 2; i++ {
+		refund := &models.Refund{PaymentID: payment.ID, Amount: 50000, Status: models.RefundSucceeded,
+			Reason: "Class cancelled", RefundKey: fmt.Sprintf("R-%d-%d", payment.ID, i)}
+		if err := s.db.Create(refund).Error; err != nil {
+			t.Fatal(err)
+		}
+		s.notifications.RefundIssued(reservation.ID, refund)
+		s.notifications.RefundIssued(reservation.ID, refund)
+	}
+
+	var messages []models.OutboxMessage
+	if err := s.db.Where("user_id = ?", user.ID).Order("id").Find(&messages).Error; err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, message := range messages {
+		keys = append(keys, *message.DedupeKey)
+	}
+	want := []string{
+		fmt.Sprintf("payment_confirmed:%d:email", reservation.ID),
+		fmt.Sprintf("payment_confirmed:%d:sms", reservation.ID),
+	}
+	if len(keys) != 6 || strings.Join(keys[:2], ",") != strings.Join(want, ",") ||
+		!strings.HasPrefix(keys[2], "refund_issued:") || keys[2] == keys[4] {
+		t.Errorf("dedupe keys = %v", keys)
+	}
+
+	// Delivery hands each message to the notifier of its channel
+	if err := s.notifications.DeliverOutbox(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	email := s.notifiers[models.ChannelEmail].(*notifier.FakeNotifier).Sent()
+	sms := s.notifiers[models.ChannelSMS].(*notifier.FakeNotifier).Sent()
+	if len(email) != 3 || len(sms) != 3 {
+		t.Fatalf("sent %d email(s) and %d SMS, want 3 each", len(email), len(sms))
+	}
+	if email[0].To != "member@example.com" || email[0].Subject == "" || sms[0].To != "+6281234567890" || sms[0].Subject != "" {
+		t.Errorf("email %+v, SMS %+v", email[0], sms[0])
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	want := []time.Duration{
+		time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute, 32 * time.Minute,
+		60 * time.Minute, 60 * time.Minute,
+	}
+	for i, backoff := range want {
+		if got := retryBackoff(i + 1); got != backoff {
+			t.Errorf("retryBackoff(%d) = %s, want %s", i+1, got, backoff)
+		}
+	}
+}
+
+func TestDeliverOutboxRetries(t *testing.T) {
+	cfg := testConfig(jakarta)
+	cfg.NotificationMaxAttempts = 3
+	s := newTestServices(t, cfg, time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+	ctx := context.Background()
+
+	user := seedUser(t, s.db, "member@example.com")
+	if err := s.notifications.SendAccountEmail(user, user.Email, EventPasswordReset, "http://localhost:3000/reset", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	email := s.notifiers[models.ChannelEmail].(*notifier.FakeNotifier)
+	email.Err = errors.New("connection refused")
+
+	message := func() *models.OutboxMessage {
+		t.Helper()
+		var messages []models.OutboxMessage
+		if err := s.db.Where("user_id = ?", user.ID).Find(&messages).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(messages) != 1 {
+			t.Fatalf("%d message(s) queued, want 1", len(messages))
+		}
+		return &messages[0]
+	}
+
+	// Each failure waits longer before the next attempt
+	for attempt, backoff := range []time.Duration{time.Minute, 2 * time.Minute} {
+		failedAt := s.clock.Now()
+		if err := s.notifications.DeliverOutbox(ctx); err != nil {
+			t.Fatal(err)
+		}
+		m := message()
+		if m.Status != models.OutboxPending || m.Attempts != attempt+1 || m.LastError != "connection refused" {
+			t.Fatalf("after attempt %d: status %s, %d attempt(s), error %q", attempt+1, m.Status, m.Attempts, m.LastError)
+		}
+		if !m.NextAttemptAt.Equal(failedAt.Add(backoff)) {
+			t.Errorf("after attempt %d: next attempt at %s, want %s", attempt+1, m.NextAttemptAt, failedAt.Add(backoff))
+		}
+
+		// Not due yet
+		s.clock.Advance(backoff - time.Second)
+		if err := s.notifications.DeliverOutbox(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if m := message(); m.Attempts != attempt+1 {
+			t.Errorf("attempted %d time(s) before the backoff ended", m.Attempts)
+		}
+		s.clock.Advance(time.Second)
+	}
+
+	// The last attempt gives up
+	if err := s.notifications.DeliverOutbox(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if m := message(); m.Status != models.OutboxFailed || m.Attempts != 3 {
+		t.Fatalf("after the last attempt: status %s, %d attempt(s)", m.Status, m.Attempts)
+	}
+	s.clock.Advance(24 * time.Hour)
+	if err := s.notifications.DeliverOutbox(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if m := message(); m.Attempts != 3 {
+		t.Errorf("failed message attempted again: %d attempt(s)", m.Attempts)
+	}
+
+	// An admin retries it once the provider is back
+	email.Err = nil
+	if _, err := s.notifications.RetryMessage(message().ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.notifications.DeliverOutbox(ctx); err != nil {
+		t.Fatal(err)
+	}
+	m := message()
+	if m.Status != models.OutboxSent || m.SentAt == nil || m.LastError != "" {
+		t.Errorf("after retry: status %s, sent at %v, error %q", m.Status, m.SentAt, m.LastError)
+	}
+	if sent := email.Sent(); len(sent) != 1 || sent[0].To != user.Email {
+		t.Errorf("sent %+v, want one email to %s", sent, user.Email)
+	}
+}
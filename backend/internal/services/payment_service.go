@@ -1,43 +1,68 @@
 package services
 
 import (
-	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"log"
+	"math"
 	"reservation-api/api/dto"
 	"reservation-api/internal/config"
+	"reservation-api/internal/gateway"
 	"reservation-api/internal/models"
 	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-const (
-	DefaultSessionPrice = 100000.0 // IDR
-)
-
 // PaymentService handles payment business logic
 type PaymentService struct {
-	paymentRepo     *repository.PaymentRepository
-	reservationRepo *repository.ReservationRepository
-	config          *config.Config
+	paymentRepo         *repository.PaymentRepository
+	reservationRepo     *repository.ReservationRepository
+	notificationRepo    *repository.PaymentNotificationRepository
+	creditRepo          *repository.CreditRepository
+	membershipRepo      *repository.MembershipRepository
+	pricingService      *PricingService
+	waitlistService     *WaitlistService
+	notificationService *NotificationService
+	refundService       *RefundService
+	gateway             gateway.PaymentGateway
+	config              *config.Config
+	clock               utils.Clock
 }
 
 // NewPaymentService creates a new payment service
 func NewPaymentService(
 	paymentRepo *repository.PaymentRepository,
 	reservationRepo *repository.ReservationRepository,
+	notificationRepo *repository.PaymentNotificationRepository,
+	creditRepo *repository.CreditRepository,
+	membershipRepo *repository.MembershipRepository,
+	pricingService *PricingService,
+	waitlistService *WaitlistService,
+	notificationService *NotificationService,
+	refundService *RefundService,
+	paymentGateway gateway.PaymentGateway,
 	cfg *config.Config,
+	clock utils.Clock,
 ) *PaymentService {
 	return &PaymentService{
-		paymentRepo:     paymentRepo,
-		reservationRepo: reservationRepo,
-		config:          cfg,
+		paymentRepo:         paymentRepo,
+		reservationRepo:     reservationRepo,
+		notificationRepo:    notificationRepo,
+		creditRepo:          creditRepo,
+		membershipRepo:      membershipRepo,
+		pricingService:      pricingService,
+		waitlistService:     waitlistService,
+		notificationService: notificationService,
+		refundService:       refundService,
+		gateway:             paymentGateway,
+		config:              cfg,
+		clock:               clock,
 	}
 }
 
@@ -63,6 +88,12 @@ func (s *PaymentService) CreatePayment(userID uint, req dto.CreatePaymentRequest
 	}
 
 	// Check if already paid
+	if reservation.PaidWithCredit {
+		return nil, "", "", errors.New("reservation already paid with credit")
+	}
+	if reservation.MembershipID != nil {
+		return nil, "", "", errors.New("reservation is covered by membership")
+	}
 	paid, err := s.paymentRepo.CheckPaidByReservationID(req.ReservationID)
 	if err != nil {
 		return nil, "", "", err
@@ -71,215 +102,427 @@ func (s *PaymentService) CreatePayment(userID uint, req dto.CreatePaymentRequest
 		return nil, "", "", errors.New("reservation already paid")
 	}
 
-	// Calculate amount
-	amount := DefaultSessionPrice
+	// The seat is only held for a limited window after booking
+	expiredAt := reservation.HoldDeadline(s.config.HoldExpiry)
+	if !s.clock.Now().Before(expiredAt) {
+		return nil, "", "", errors.New("reservation hold has expired")
+	}
 
-	// Generate unique transaction ID
-	transactionID := fmt.Sprintf("TRX-%s-%d", uuid.New().String()[:8], time.Now().Unix())
+	// Calculate amount from pricing rules
+	amount, breakdown, err := s.pricingService.CalculatePrice(reservation.CourtID, reservation.TimeslotID, reservation.Date)
+	if err != nil {
+		return nil, "", "", errors.New("failed to calculate price")
+	}
+	// The gateway cannot charge nothing; free classes are not paid online
+	if amount <= 0 {
+		return nil, "", "", errors.New("reservation has nothing to pay")
+	}
 
 	// Create payment record
 	payment := &models.Payment{
-		ReservationID: req.ReservationID,
+		UserID:        userID,
+		ReservationID: &reservation.ID,
 		Amount:        amount,
+		Breakdown:     breakdown,
 		Status:        models.PaymentPending,
-		TransactionID: transactionID,
+		TransactionID: newTransactionID(),
+		ExpiredAt:     &expiredAt, // Payment expires together with the reservation hold
 	}
 
-	if err := s.paymentRepo.Create(payment); err != nil {
-		return nil, "", "", errors.New("failed to create payment")
+	item := dto.ItemDetail{
+		ID:       fmt.Sprintf("COURT-%d", reservation.CourtID),
+		Price:    amount,
+		Quantity: 1,
+		Name:     fmt.Sprintf("Pilates Class - %s at %s", reservation.Court.Name, reservation.Timeslot.Time),
 	}
 
-	// Load payment with relations for response
-	payment, err = s.paymentRepo.FindByID(payment.ID)
-	if err != nil {
-		// Continue even if reload fails, payment is already created
-		payment.Reservation = *reservation
+	return s.startPayment(payment, &reservation.User, item)
+}
+
+// CreatePurchasePayment creates a payment transaction for a credit package purchase
+func (s *PaymentService) CreatePurchasePayment(purchase *models.CreditPurchase, user *models.User) (*models.Payment, string, string, error) {
+	expiredAt := s.clock.Now().Add(s.config.HoldExpiry)
+
+	payment := &models.Payment{
+		UserID:           user.ID,
+		CreditPurchaseID: &purchase.ID,
+		Amount:           purchase.Price,
+		Breakdown: models.PriceBreakdown{
+			{Name: purchase.Package.Name, Value: purchase.Price, Price: purchase.Price},
+		},
+		Status:        models.PaymentPending,
+		TransactionID: newTransactionID(),
+		ExpiredAt:     &expiredAt,
 	}
 
-	// Check if Midtrans is configured
-	if s.config.MidtransServerKey == "" || s.config.MidtransClientKey == "" {
-		// DUMMY PAYMENT MODE - Skip Midtrans integration
-		dummyURL := fmt.Sprintf("http://localhost:3000/payment/dummy?transaction_id=%s", transactionID)
-		payment.MidtransURL = dummyURL
-		payment.MidtransToken = "DUMMY_TOKEN_" + transactionID
+	item := dto.ItemDetail{
+		ID:       fmt.Sprintf("PACKAGE-%d", purchase.PackageID),
+		Price:    purchase.Price,
+		Quantity: 1,
+		Name:     fmt.Sprintf("Pilates Package - %s (%d classes)", purchase.Package.Name, purchase.Credits),
+	}
 
-		// Set expiration (24 hours)
-		expiredAt := time.Now().Add(24 * time.Hour)
-		payment.ExpiredAt = &expiredAt
+	return s.startPayment(payment, user, item)
+}
 
-		if err := s.paymentRepo.Update(payment); err != nil {
-			return payment, dummyURL, payment.MidtransToken, err
-		}
+// CreateMembershipPayment creates a payment transaction for one month of a membership,
+// payable until expiredAt
+func (s *PaymentService) CreateMembershipPayment(membership *models.Membership, user *models.User, expiredAt time.Time) (*models.Payment, string, string, error) {
+	payment := &models.Payment{
+		UserID:       user.ID,
+		MembershipID: &membership.ID,
+		Amount:       membership.Price,
+		Breakdown: models.PriceBreakdown{
+			{Name: membership.Plan.Name, Value: membership.Price, Price: membership.Price},
+		},
+		Status:        models.PaymentPending,
+		TransactionID: newTransactionID(),
+		ExpiredAt:     &expiredAt,
+	}
 
-		return payment, dummyURL, payment.MidtransToken, nil
+	item := dto.ItemDetail{
+		ID:       fmt.Sprintf("MEMBERSHIP-%d", membership.PlanID),
+		Price:    membership.Price,
+		Quantity: 1,
+		Name:     fmt.Sprintf("Pilates Membership - %s (1 month)", membership.Plan.Name),
+	}
+
+	return s.startPayment(payment, user, item)
+}
+
+// startPayment stores a pending payment and creates its charge at the payment gateway.
+// Returns the payment, its payment page URL and token.
+func (s *PaymentService) startPayment(payment *models.Payment, user *models.User, item dto.ItemDetail) (*models.Payment, string, string, error) {
+	if err := s.paymentRepo.Create(payment); err != nil {
+		return nil, "", "", errors.New("failed to create payment")
 	}
 
-	// REAL MIDTRANS INTEGRATION (when configured)
-	// Create Midtrans transaction
-	
 	// Handle empty phone (Midtrans requires phone)
-	phone := reservation.User.Phone
+	phone := user.Phone
 	if phone == "" {
 		phone = "08123456789" // Default dummy phone for users without phone
 	}
-	
-	midtransReq := dto.MidtransRequest{
-		TransactionDetails: dto.TransactionDetails{
-			OrderID:     transactionID,
-			GrossAmount: amount,
-		},
-		CustomerDetails: dto.CustomerDetails{
-			FirstName: reservation.User.Name,
-			Email:     reservation.User.Email,
+
+	// Create charge at the payment gateway
+	charge, err := s.gateway.CreateCharge(gateway.ChargeRequest{
+		OrderID:   payment.TransactionID,
+		Amount:    payment.Amount,
+		ExpiresAt: *payment.ExpiredAt,
+		Customer: dto.CustomerDetails{
+			FirstName: user.Name,
+			Email:     user.Email,
 			Phone:     phone, // Use phone or default
 		},
-		ItemDetails: []dto.ItemDetail{
-			{
-				ID:       fmt.Sprintf("COURT-%d", reservation.CourtID),
-				Price:    amount,
-				Quantity: 1,
-				Name:     fmt.Sprintf("Pilates Class - %s at %s", reservation.Court.Name, reservation.Timeslot.Time),
-			},
-		},
-	}
-
-	// Call Midtrans API
-	midtransResp, err := s.createMidtransTransaction(midtransReq)
+		Items: []dto.ItemDetail{item},
+	})
 	if err != nil {
-		// Return payment ID even if Midtrans fails, so user can retry
+		// Return payment ID even if the gateway fails, so user can retry
 		return payment, "", "", fmt.Errorf("failed to create payment transaction: %v", err)
 	}
 
-	// Update payment with Midtrans info
-	payment.MidtransToken = midtransResp.Token
-	payment.MidtransURL = midtransResp.RedirectURL
-
-	// Set expiration (24 hours)
-	expiredAt := time.Now().Add(24 * time.Hour)
-	payment.ExpiredAt = &expiredAt
+	// Update payment with gateway info
+	payment.MidtransToken = charge.Token
+	payment.MidtransURL = charge.RedirectURL
 
 	if err := s.paymentRepo.Update(payment); err != nil {
-		return payment, midtransResp.RedirectURL, midtransResp.Token, err
+		return payment, charge.RedirectURL, charge.Token, err
 	}
 
-	return payment, midtransResp.RedirectURL, midtransResp.Token, nil
+	// Load payment with relations for response
+	if loaded, err := s.paymentRepo.FindByID(payment.ID); err == nil {
+		payment = loaded
+	}
+
+	return payment, charge.RedirectURL, charge.Token, nil
+}
+
+// newTransactionID generates a unique order ID for the payment gateway
+func newTransactionID() string {
+	return fmt.Sprintf("TRX-%s-%d", uuid.New().String()[:8], time.Now().Unix())
 }
 
-// HandleCallback handles payment callback from Midtrans
-func (s *PaymentService) HandleCallback(req dto.PaymentCallbackRequest) (*models.Payment, error) {
+// HandleCallback handles payment notification from the payment gateway. Every
+// notification is recorded for audit; only authentic notifications matching the
+// payment amount are applied, and only while the payment is still pending, so
+// replayed or out-of-order notifications are ignored.
+func (s *PaymentService) HandleCallback(req dto.PaymentCallbackRequest, rawBody []byte) (*models.Payment, error) {
+	notification := &models.PaymentNotification{
+		OrderID:           req.OrderID,
+		TransactionStatus: req.TransactionStatus,
+		StatusCode:        req.StatusCode,
+		GrossAmount:       req.GrossAmount,
+		RawBody:           string(rawBody),
+	}
+	defer s.recordNotification(notification)
+
+	// Verify notification signature
+	notification.SignatureValid = s.gateway.VerifyNotification(req)
+	if !notification.SignatureValid {
+		notification.Outcome = models.NotificationInvalidSignature
+		return nil, errors.New("invalid signature")
+	}
+
 	// Find payment by transaction ID
 	payment, err := s.paymentRepo.FindByTransactionID(req.OrderID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			notification.Outcome = models.NotificationUnknownOrder
 			return nil, errors.New("payment not found")
 		}
 		return nil, err
 	}
 
-	// Update payment status based on Midtrans callback
-	switch req.TransactionStatus {
-	case "capture", "settlement":
-		payment.Status = models.PaymentPaid
-		now := time.Now()
-		payment.PaidAt = &now
-
-		// Update reservation status to confirmed
-		reservation, err := s.reservationRepo.FindByID(payment.ReservationID)
-		if err == nil {
-			reservation.Status = models.StatusConfirmed
-			s.reservationRepo.Update(reservation)
-		}
-
-	case "pending":
-		payment.Status = models.PaymentPending
+	return s.applyNotification(payment, req, notification)
+}
 
-	case "deny", "expire", "cancel":
-		payment.Status = models.PaymentFailed
+// SimulatePayment finishes the dummy payment page of the fake gateway for a payment
+// of the user: the charge is settled or denied at the gateway, and the notification
+// it sends is handled like one from a real gateway
+func (s *PaymentService) SimulatePayment(id, userID uint, result string) (*models.Payment, error) {
+	fake, ok := s.gateway.(*gateway.FakeGateway)
+	if !ok {
+		return nil, errors.New("payments can only be simulated with the fake gateway")
+	}
 
-		// Update reservation status to cancelled
-		reservation, err := s.reservationRepo.FindByID(payment.ReservationID)
-		if err == nil {
-			reservation.Status = models.StatusCancelled
-			s.reservationRepo.Update(reservation)
-		}
+	payment, err := s.GetPayment(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !payment.IsPending() {
+		return nil, errors.New("payment is not pending")
 	}
 
-	// Save payment
-	if err := s.paymentRepo.Update(payment); err != nil {
-		return nil, errors.New("failed to update payment status")
+	var notification dto.PaymentCallbackRequest
+	switch result {
+	case "settlement":
+		notification, err = fake.Settle(payment.TransactionID)
+	case "deny":
+		notification, err = fake.Deny(payment.TransactionID)
+	default:
+		return nil, fmt.Errorf("unknown payment result %q", result)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate payment: %v", err)
 	}
 
-	// Load full payment details
-	payment, err = s.paymentRepo.FindByID(payment.ID)
+	rawBody, err := json.Marshal(notification)
 	if err != nil {
 		return nil, err
 	}
-
-	return payment, nil
+	return s.HandleCallback(notification, rawBody)
 }
 
-// GetPayment gets payment details
-func (s *PaymentService) GetPayment(id, userID uint) (*models.Payment, error) {
-	payment, err := s.paymentRepo.FindByID(id)
+// RefreshStatus queries the payment gateway for the current status of a payment and
+// applies it, for when a notification was missed or delayed
+func (s *PaymentService) RefreshStatus(id, userID uint) (*models.Payment, error) {
+	payment, err := s.GetPayment(id, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("payment not found")
-		}
 		return nil, err
 	}
 
-	// Verify ownership
-	if payment.Reservation.UserID != userID {
-		return nil, errors.New("unauthorized access to payment")
+	if !payment.IsPending() {
+		return payment, nil
 	}
 
-	return payment, nil
+	status, err := s.gateway.GetStatus(payment.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment status: %v", err)
+	}
+
+	notification := &models.PaymentNotification{
+		OrderID:           status.OrderID,
+		TransactionStatus: status.TransactionStatus,
+		StatusCode:        status.StatusCode,
+		GrossAmount:       status.GrossAmount,
+		SignatureValid:    true, // Fetched from the gateway API directly
+		RawBody:           "status query",
+	}
+	defer s.recordNotification(notification)
+
+	return s.applyNotification(payment, *status, notification)
 }
 
-// createMidtransTransaction calls Midtrans Snap API
-func (s *PaymentService) createMidtransTransaction(req dto.MidtransRequest) (*dto.MidtransResponse, error) {
-	// Check if Midtrans credentials are set
-	if s.config.MidtransServerKey == "" {
-		return nil, errors.New("midtrans credentials not configured")
+// applyNotification applies a gateway transaction status to a payment and its reservation
+func (s *PaymentService) applyNotification(
+	payment *models.Payment,
+	req dto.PaymentCallbackRequest,
+	notification *models.PaymentNotification,
+) (*models.Payment, error) {
+	notification.PaymentID = &payment.ID
+
+	// Verify amount
+	grossAmount, err := strconv.ParseFloat(req.GrossAmount, 64)
+	if err != nil || math.Abs(grossAmount-payment.Amount) > 0.005 {
+		notification.Outcome = models.NotificationAmountMismatch
+		return nil, errors.New("gross amount does not match payment amount")
 	}
 
-	// Marshal request
-	jsonData, err := json.Marshal(req)
+	// Map gateway status to payment and reservation status
+	var (
+		status            models.PaymentStatus
+		reservationStatus models.ReservationStatus
+		paidAt            *time.Time
+	)
+
+	switch req.TransactionStatus {
+	case "capture", "settlement":
+		// Card captures flagged by fraud detection stay pending until reviewed
+		if req.TransactionStatus == "capture" && req.FraudStatus == "challenge" {
+			notification.Outcome = models.NotificationIgnored
+			return s.paymentRepo.FindByID(payment.ID)
+		}
+		status = models.PaymentPaid
+		reservationStatus = models.StatusConfirmed
+		now := s.clock.Now()
+		paidAt = &now
+
+	case "deny", "cancel":
+		status = models.PaymentFailed
+		reservationStatus = models.StatusCancelled
+
+	case "expire":
+		status = models.PaymentExpired
+		reservationStatus = models.StatusCancelled
+
+	default:
+		// pending and unknown statuses do not change anything
+		notification.Outcome = models.NotificationIgnored
+		return s.paymentRepo.FindByID(payment.ID)
+	}
+
+	if req.PaymentType != "" {
+		payment.PaymentMethod = req.PaymentType
+	}
+
+	applied, err := s.paymentRepo.SettlePending(payment, status, paidAt, reservationStatus)
 	if err != nil {
-		return nil, err
+		return nil, errors.New("failed to update payment status")
+	}
+
+	if applied {
+		notification.Outcome = models.NotificationApplied
+		s.completeReservation(payment, status)
+		s.completePurchase(payment, status)
+		s.completeMembership(payment, status)
+	} else {
+		notification.Outcome = models.NotificationIgnored
+		if status == models.PaymentPaid && (payment.Status == models.PaymentExpired || payment.Status == models.PaymentFailed) {
+			// The customer paid after the order was given up; the money goes back
+			log.Printf("⚠️  Payment %s settled after it was %s, refunding", payment.TransactionID, payment.Status)
+			notification.Outcome = models.NotificationRefunded
+			s.refundLate(payment)
+		}
 	}
 
-	// Create HTTP request
-	url := s.config.MidtransBaseURL + "/transactions"
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	// Load full payment details
+	return s.paymentRepo.FindByID(payment.ID)
+}
+
+// completeReservation updates the waitlist for the reservation paid by a settled
+// payment and notifies the user: a paid offer is claimed, and a failed payment
+// releases the seat
+func (s *PaymentService) completeReservation(payment *models.Payment, status models.PaymentStatus) {
+	if payment.ReservationID == nil {
+		return
+	}
+
+	reservation, err := s.reservationRepo.FindByID(*payment.ReservationID)
 	if err != nil {
-		return nil, err
+		log.Printf("⚠️  Failed to load reservation %d for payment %s: %v", *payment.ReservationID, payment.TransactionID, err)
+		return
 	}
 
-	// Set headers
-	auth := base64.StdEncoding.EncodeToString([]byte(s.config.MidtransServerKey + ":"))
-	httpReq.Header.Set("Authorization", "Basic "+auth)
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
+	if status == models.PaymentPaid {
+		// A reservation cancelled while its payment was in progress is not confirmed
+		// by it; the payment is refunded instead
+		if !reservation.IsConfirmed() {
+			log.Printf("⚠️  Payment %s settled for %s reservation %d, refunding", payment.TransactionID, reservation.Status, reservation.ID)
+			if _, err := s.refundService.RefundReservation(reservation, 100, "Paid after the reservation was cancelled"); err != nil {
+				log.Printf("⚠️  Refund for reservation %d not completed: %v", reservation.ID, err)
+			}
+			return
+		}
+		s.waitlistService.ClaimOffer(reservation.ID)
+		s.notificationService.PaymentConfirmed(reservation.ID)
+		return
+	}
 
-	// Send request
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(httpReq)
+	s.notificationService.ReservationCancelled(reservation.ID, "The payment was not completed")
+	s.waitlistService.ReleaseSeat(reservation)
+}
+
+// refundLate refunds a payment settled after it had expired or failed
+func (s *PaymentService) refundLate(payment *models.Payment) {
+	if _, err := s.refundService.RefundLatePayment(payment); err != nil {
+		log.Printf("⚠️  Refund of late payment %s not completed: %v", payment.TransactionID, err)
+	}
+}
+
+// completePurchase credits or cancels the package purchase paid by a settled payment
+func (s *PaymentService) completePurchase(payment *models.Payment, status models.PaymentStatus) {
+	if payment.CreditPurchaseID == nil {
+		return
+	}
+
+	var err error
+	if status == models.PaymentPaid {
+		_, err = s.creditRepo.ActivatePurchase(*payment.CreditPurchaseID, s.clock.Now())
+	} else {
+		err = s.creditRepo.CancelPurchase(*payment.CreditPurchaseID)
+	}
 	if err != nil {
-		return nil, err
+		log.Printf("⚠️  Failed to update credit purchase %d for payment %s: %v", *payment.CreditPurchaseID, payment.TransactionID, err)
+	}
+}
+
+// completeMembership extends or suspends the membership billed by a settled payment,
+// or refunds the payment if the membership was cancelled
+func (s *PaymentService) completeMembership(payment *models.Payment, status models.PaymentStatus) {
+	if payment.MembershipID == nil {
+		return
+	}
+
+	if status == models.PaymentPaid {
+		extended, err := s.membershipRepo.Extend(*payment.MembershipID, s.clock.Now())
+		if err != nil {
+			log.Printf("⚠️  Failed to extend membership %d for payment %s: %v", *payment.MembershipID, payment.TransactionID, err)
+		} else if !extended {
+			// A membership cancelled while its payment was in progress is not extended
+			// by it; the payment is refunded instead
+			log.Printf("⚠️  Payment %s settled for cancelled membership %d, refunding", payment.TransactionID, *payment.MembershipID)
+			if _, err := s.refundService.RefundPaidPayment(payment.ID, 100, "Paid after the membership was cancelled"); err != nil {
+				log.Printf("⚠️  Refund of payment %s not completed: %v", payment.TransactionID, err)
+			}
+		}
+		return
+	}
+
+	if err := s.membershipRepo.FailPayment(*payment.MembershipID, payment.ID); err != nil {
+		log.Printf("⚠️  Failed to suspend membership %d for payment %s: %v", *payment.MembershipID, payment.TransactionID, err)
 	}
-	defer resp.Body.Close()
+}
 
-	// Check status code
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("midtrans API returned status %d", resp.StatusCode)
+// recordNotification stores a notification audit record
+func (s *PaymentService) recordNotification(notification *models.PaymentNotification) {
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("⚠️  Failed to record payment notification for %s: %v", notification.OrderID, err)
 	}
+}
 
-	// Parse response
-	var midtransResp dto.MidtransResponse
-	if err := json.NewDecoder(resp.Body).Decode(&midtransResp); err != nil {
+// GetPayment gets payment details
+func (s *PaymentService) GetPayment(id, userID uint) (*models.Payment, error) {
+	payment, err := s.paymentRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
 		return nil, err
 	}
 
-	return &midtransResp, nil
-}
\ No newline at end of file
+	// Verify ownership
+	if !payment.BelongsTo(userID) {
+		return nil, errors.New("unauthorized access to payment")
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,439 @@
+package services
+
+import (
+	"fmt"
+	"reservation-api/api/dto"
+	"reservation-api/internal/gateway"
+	"reservation-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestSimulatePaymentAndRefund(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	stranger := seedUser(t, s.db, "stranger@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.payments.SimulatePayment(payment.ID, stranger.ID, "settlement"); err == nil || err.Error() != "unauthorized access to payment" {
+		t.Fatalf("simulating another user's payment: err = %v", err)
+	}
+
+	paid, err := s.payments.SimulatePayment(payment.ID, owner.ID, "settlement")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.Status != models.PaymentPaid {
+		t.Errorf("payment is %s, want %s", paid.Status, models.PaymentPaid)
+	}
+
+	booked, err := s.reservationRepo.FindByID(reservation.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booked.Status != models.StatusConfirmed {
+		t.Errorf("reservation is %s, want %s", booked.Status, models.StatusConfirmed)
+	}
+
+	refund, err := s.refunds.RefundReservation(booked, 100, "Class cancelled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund == nil || refund.Status != models.RefundSucceeded {
+		t.Fatalf("refund = %+v, want a succeeded refund", refund)
+	}
+
+	refunded, err := s.paymentRepo.FindByID(payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refunded.Status != models.PaymentRefunded {
+		t.Errorf("payment is %s, want %s", refunded.Status, models.PaymentRefunded)
+	}
+}
+
+func TestForgedNotificationIsRejected(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged := dto.PaymentCallbackRequest{
+		OrderID:           payment.TransactionID,
+		TransactionStatus: "settlement",
+		StatusCode:        "200",
+		GrossAmount:       "100000.00",
+	}
+	if _, err := s.payments.HandleCallback(forged, []byte("{}")); err == nil || err.Error() != "invalid signature" {
+		t.Fatalf("unsigned notification: err = %v, want invalid signature", err)
+	}
+
+	pending, err := s.paymentRepo.FindByID(payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != models.PaymentPending {
+		t.Errorf("payment is %s, want %s", pending.Status, models.PaymentPending)
+	}
+}
+
+func TestNotificationWithOtherAmountIsRejected(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A correctly signed notification for a smaller charge under the same order
+	if _, err := s.gateway.CreateCharge(gateway.ChargeRequest{OrderID: payment.TransactionID, Amount: 1000, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	notification, err := s.gateway.Settle(payment.TransactionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.payments.HandleCallback(notification, []byte("{}")); err == nil || err.Error() != "gross amount does not match payment amount" {
+		t.Fatalf("notification with another amount: err = %v, want gross amount does not match payment amount", err)
+	}
+
+	pending, err := s.paymentRepo.FindByID(payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != models.PaymentPending || pending.PaidAt != nil {
+		t.Errorf("payment is %s paid at %v, want it still pending", pending.Status, pending.PaidAt)
+	}
+	if pending.Reservation.Status != models.StatusPending {
+		t.Errorf("reservation is %s, want %s", pending.Reservation.Status, models.StatusPending)
+	}
+	assertNotificationOutcomes(t, s, payment.ID, models.NotificationAmountMismatch)
+}
+
+func TestReplayedNotificationIsIgnored(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notification, err := s.gateway.Settle(payment.TransactionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paid, err := s.payments.HandleCallback(notification, []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.clock.Advance(time.Hour)
+	replayed, err := s.payments.HandleCallback(notification, []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Status != models.PaymentPaid || replayed.PaidAt == nil || !replayed.PaidAt.Equal(*paid.PaidAt) {
+		t.Errorf("replay left payment %s paid at %v, want %s paid at %v",
+			replayed.Status, replayed.PaidAt, models.PaymentPaid, paid.PaidAt)
+	}
+	assertNotificationOutcomes(t, s, payment.ID, models.NotificationApplied, models.NotificationIgnored)
+
+	var refunds int64
+	if err := s.db.Model(&models.Refund{}).Where("payment_id = ?", payment.ID).Count(&refunds).Error; err != nil {
+		t.Fatal(err)
+	}
+	if refunds != 0 {
+		t.Errorf("replay created %d refunds", refunds)
+	}
+}
+
+// assertNotificationOutcomes checks the outcomes recorded for the notifications of a
+// payment, in the order they were received
+func assertNotificationOutcomes(t *testing.T, s *testServices, paymentID uint, want ...models.NotificationOutcome) {
+	t.Helper()
+
+	var notifications []models.PaymentNotification
+	if err := s.db.Where("payment_id = ?", paymentID).Order("id").Find(&notifications).Error; err != nil {
+		t.Fatal(err)
+	}
+	got := make([]models.NotificationOutcome, len(notifications))
+	for i, notification := range notifications {
+		got[i] = notification.Outcome
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("notification outcomes = %v, want %v", got, want)
+	}
+}
+
+func TestPaymentAfterCancellationIsRefunded(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.reservations.CancelReservation(reservation.ID, owner.ID); err != nil {
+		t.Fatal(err)
+	}
+	expired, err := s.paymentRepo.FindByID(payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired.Status != models.PaymentExpired {
+		t.Fatalf("payment of cancelled reservation is %s, want %s", expired.Status, models.PaymentExpired)
+	}
+
+	// The customer completes the payment page anyway
+	notification, err := s.gateway.Settle(payment.TransactionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.payments.HandleCallback(notification, []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRefunded(t, s, payment.ID, reservation.ID)
+}
+
+func TestPaymentForCancelledReservationIsRefunded(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancelled without touching the payment, as bookings made before payments were
+	// expired on cancellation
+	if err := s.db.Model(reservation).Update("status", models.StatusCancelled).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.payments.SimulatePayment(payment.ID, owner.ID, "settlement"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRefunded(t, s, payment.ID, reservation.ID)
+}
+
+func TestPaymentForCancelledMembershipIsRefunded(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	plan := &models.MembershipPlan{Name: "Unlimited", Price: 900000, IsActive: true}
+	if err := s.db.Create(plan).Error; err != nil {
+		t.Fatal(err)
+	}
+	payment, _, _, err := s.memberships.Subscribe(owner.ID, dto.SubscribeRequest{PlanID: plan.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancelled while the payment was in progress, without touching the payment
+	if err := s.db.Model(&models.Membership{}).Where("id = ?", *payment.MembershipID).
+		Update("status", models.MembershipCancelled).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.payments.SimulatePayment(payment.ID, owner.ID, "settlement"); err != nil {
+		t.Fatal(err)
+	}
+
+	refunded, err := s.paymentRepo.FindByID(payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refunded.Status != models.PaymentRefunded || refunded.PaidAt == nil {
+		t.Errorf("payment is %s paid at %v, want %s with its paid time", refunded.Status, refunded.PaidAt, models.PaymentRefunded)
+	}
+
+	var refunds []models.Refund
+	if err := s.db.Where("payment_id = ?", payment.ID).Find(&refunds).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(refunds) != 1 || refunds[0].Status != models.RefundSucceeded || refunds[0].Amount != payment.Amount {
+		t.Errorf("refunds = %+v, want one full refund", refunds)
+	}
+
+	var membership models.Membership
+	if err := s.db.First(&membership, *payment.MembershipID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if membership.Status != models.MembershipCancelled || membership.EndDate != nil {
+		t.Errorf("membership is %s until %v, want it to stay cancelled", membership.Status, membership.EndDate)
+	}
+}
+
+// assertRefunded checks that a payment was refunded in full, its reservation stayed
+// cancelled and the user was never told the booking is confirmed
+func TestRefundAfterEarlierPaymentExpired(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+	expired := seedPayment(t, s.db, reservation, models.PaymentExpired, nil)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.payments.SimulatePayment(payment.ID, owner.ID, "settlement"); err != nil {
+		t.Fatal(err)
+	}
+
+	booked, err := s.reservationRepo.FindByID(reservation.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refund, err := s.refunds.RefundReservation(booked, 100, "Class cancelled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund == nil || refund.PaymentID != payment.ID {
+		t.Fatalf("refund = %+v, want a refund of payment %d", refund, payment.ID)
+	}
+
+	untouched, err := s.paymentRepo.FindByID(expired.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if untouched.Status != models.PaymentExpired {
+		t.Errorf("expired payment is %s, want %s", untouched.Status, models.PaymentExpired)
+	}
+}
+
+func TestPaymentIsRefundedOnce(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paid, err := s.payments.SimulatePayment(payment.ID, owner.ID, "settlement")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two refunds that both saw the payment as paid
+	stale := *paid
+	if refund, err := s.refunds.refundPayment(paid, 100, "Class cancelled"); err != nil || refund == nil {
+		t.Fatalf("first refund = %+v, %v", refund, err)
+	}
+	if refund, err := s.refunds.refundPayment(&stale, 100, "Class cancelled"); err != nil || refund != nil {
+		t.Errorf("second refund = %+v, %v, want none", refund, err)
+	}
+
+	var refunds int64
+	if err := s.db.Model(&models.Refund{}).Where("payment_id = ?", payment.ID).Count(&refunds).Error; err != nil {
+		t.Fatal(err)
+	}
+	if refunds != 1 {
+		t.Errorf("payment has %d refunds, want 1", refunds)
+	}
+}
+
+func assertRefunded(t *testing.T, s *testServices, paymentID, reservationID uint) {
+	t.Helper()
+
+	payment, err := s.paymentRepo.FindByID(paymentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != models.PaymentRefunded {
+		t.Errorf("payment is %s, want %s", payment.Status, models.PaymentRefunded)
+	}
+
+	var refunds []models.Refund
+	if err := s.db.Where("payment_id = ?", paymentID).Find(&refunds).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(refunds) != 1 || refunds[0].Status != models.RefundSucceeded || refunds[0].Amount != payment.Amount {
+		t.Errorf("refunds = %+v, want one full refund", refunds)
+	}
+
+	reservation, err := s.reservationRepo.FindByID(reservationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reservation.Status != models.StatusCancelled {
+		t.Errorf("reservation is %s, want %s", reservation.Status, models.StatusCancelled)
+	}
+
+	var confirmations int64
+	if err := s.db.Model(&models.OutboxMessage{}).
+		Where("event = ?", EventPaymentConfirmed).
+		Count(&confirmations).Error; err != nil {
+		t.Fatal(err)
+	}
+	if confirmations != 0 {
+		t.Errorf("%d payment confirmation(s) queued for a cancelled reservation", confirmations)
+	}
+}
+
+func TestCreatePaymentWithinHold(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, jakarta)
+	cfg := testConfig(jakarta)
+	s := newTestServices(t, cfg, now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	held := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+	lapsed := seedReservation(t, s.db, seedUser(t, s.db, "late@example.com"), session, models.StatusPending, now)
+
+	s.clock.Advance(cfg.HoldExpiry - time.Minute)
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: held.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(cfg.HoldExpiry); payment.ExpiredAt == nil || !payment.ExpiredAt.Equal(want) {
+		t.Errorf("payment expires at %v, want %v", payment.ExpiredAt, want)
+	}
+
+	s.clock.Advance(time.Minute)
+	if _, _, _, err := s.payments.CreatePayment(lapsed.UserID, dto.CreatePaymentRequest{ReservationID: lapsed.ID}); err == nil || err.Error() != "reservation hold has expired" {
+		t.Errorf("paying after the hold: err = %v, want reservation hold has expired", err)
+	}
+}
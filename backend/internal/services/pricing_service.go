@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PricingService calculates session prices from pricing rules
+type PricingService struct {
+	pricingRuleRepo *repository.PricingRuleRepository
+	sessionRepo     *repository.SessionRepository
+	config          *config.Config
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(
+	pricingRuleRepo *repository.PricingRuleRepository,
+	sessionRepo *repository.SessionRepository,
+	cfg *config.Config,
+) *PricingService {
+	return &PricingService{
+		pricingRuleRepo: pricingRuleRepo,
+		sessionRepo:     sessionRepo,
+		config:          cfg,
+	}
+}
+
+// CalculatePrice calculates the price of a class. A price set on the class session
+// is final; otherwise it starts from the default session price and applies every
+// matching active rule in priority order.
+func (s *PricingService) CalculatePrice(courtID, timeslotID uint, date time.Time) (float64, models.PriceBreakdown, error) {
+	session, err := s.sessionRepo.FindByClass(courtID, timeslotID, date)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil, err
+	}
+	if session != nil && session.Price != nil {
+		price := math.Max(0, math.Round(*session.Price))
+		return price, models.PriceBreakdown{
+			{Name: "Session price", Action: models.PricingSet, Value: *session.Price, Price: price},
+		}, nil
+	}
+
+	rules, err := s.pricingRuleRepo.FindActive()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	price := s.config.DefaultSessionPrice
+	breakdown := models.PriceBreakdown{
+		{Name: "Default session price", Value: price, Price: price},
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Matches(courtID, timeslotID, date) {
+			continue
+		}
+
+		price = rule.Apply(price)
+		breakdown = append(breakdown, models.PriceComponent{
+			RuleID: rule.ID,
+			Name:   rule.Name,
+			Action: rule.Action,
+			Value:  rule.Value,
+			Price:  price,
+		})
+	}
+
+	// Charge whole Rupiah and never a negative amount
+	price = math.Max(0, math.Round(price))
+
+	return price, breakdown, nil
+}
+
+// GetPriceQuote calculates the price of a class from request parameters
+func (s *PricingService) GetPriceQuote(courtID, timeslotID uint, dateStr string) (float64, models.PriceBreakdown, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return 0, nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+	return s.CalculatePrice(courtID, timeslotID, date)
+}
+
+// GetRules gets all pricing rules
+func (s *PricingService) GetRules() ([]models.PricingRule, error) {
+	return s.pricingRuleRepo.FindAll()
+}
+
+// CreateRule creates a new pricing rule
+func (s *PricingService) CreateRule(req dto.PricingRuleRequest) (*models.PricingRule, error) {
+	rule := &models.PricingRule{IsActive: true}
+	if err := applyPricingRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.pricingRuleRepo.Create(rule); err != nil {
+		return nil, errors.New("failed to create pricing rule")
+	}
+
+	return rule, nil
+}
+
+// UpdateRule replaces a pricing rule's settings
+func (s *PricingService) UpdateRule(id uint, req dto.PricingRuleRequest) (*models.PricingRule, error) {
+	rule, err := s.pricingRuleRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pricing rule not found")
+		}
+		return nil, err
+	}
+
+	if err := applyPricingRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.pricingRuleRepo.Update(rule); err != nil {
+		return nil, errors.New("failed to update pricing rule")
+	}
+
+	return rule, nil
+}
+
+// DeleteRule deletes a pricing rule
+func (s *PricingService) DeleteRule(id uint) error {
+	if _, err := s.pricingRuleRepo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("pricing rule not found")
+		}
+		return err
+	}
+
+	return s.pricingRuleRepo.Delete(id)
+}
+
+// applyPricingRuleRequest validates a request and copies it onto a rule
+func applyPricingRuleRequest(rule *models.PricingRule, req dto.PricingRuleRequest) error {
+	action := models.PricingAction(req.Action)
+	if !action.IsValid() {
+		return errors.New("invalid action. Use set, percent or amount")
+	}
+	if action == models.PricingSet && req.Value < 0 {
+		return errors.New("price cannot be negative")
+	}
+	if req.Weekday != nil && (*req.Weekday < 0 || *req.Weekday > 6) {
+		return errors.New("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+
+	startDate, err := parseOptionalDate(req.StartDate)
+	if err != nil {
+		return err
+	}
+	endDate, err := parseOptionalDate(req.EndDate)
+	if err != nil {
+		return err
+	}
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return errors.New("end_date must not be before start_date")
+	}
+
+	rule.Name = req.Name
+	rule.CourtID = req.CourtID
+	rule.TimeslotID = req.TimeslotID
+	rule.Weekday = req.Weekday
+	rule.StartDate = startDate
+	rule.EndDate = endDate
+	rule.Action = action
+	rule.Value = req.Value
+	rule.Priority = req.Priority
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, returning nil for an empty string
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+	return &date, nil
+}
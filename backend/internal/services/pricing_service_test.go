@@ -0,0 +1,119 @@
+package services
+
+import (
+	"reservation-api/api/dto"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestCalculatePrice(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)
+	cfg := testConfig(jakarta)
+	s := newTestServices(t, cfg, now)
+	pricing := NewPricingService(repository.NewPricingRuleRepository(s.db), repository.NewSessionRepository(s.db), cfg)
+
+	// Classes on Saturday 12 January 2030, each on its own court
+	class := func() *models.ClassSession {
+		return seedSession(t, s.db, 10, time.Date(2030, 1, 12, 0, 0, 0, 0, jakarta), "07:00")
+	}
+	rule := func(rule models.PricingRule) {
+		if rule.Name == "" {
+			rule.Name = string(rule.Action)
+		}
+		if err := s.db.Create(&rule).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain := class()
+
+	ordered := class()
+	rule(models.PricingRule{CourtID: &ordered.CourtID, Action: models.PricingPercent, Value: 20, Priority: 10})
+	rule(models.PricingRule{CourtID: &ordered.CourtID, Action: models.PricingSet, Value: 80000, Priority: 0})
+
+	scoped := class()
+	saturday, sunday := int(time.Saturday), int(time.Sunday)
+	rule(models.PricingRule{CourtID: &scoped.CourtID, Weekday: &saturday, Action: models.PricingAmount, Value: 25000})
+	rule(models.PricingRule{CourtID: &scoped.CourtID, Weekday: &sunday, Action: models.PricingAmount, Value: 50000})
+	otherSlot := scoped.TimeslotID + 1000
+	rule(models.PricingRule{CourtID: &scoped.CourtID, TimeslotID: &otherSlot, Action: models.PricingSet, Value: 1})
+	ended := time.Date(2030, 1, 11, 0, 0, 0, 0, time.UTC)
+	rule(models.PricingRule{CourtID: &scoped.CourtID, EndDate: &ended, Action: models.PricingSet, Value: 1})
+
+	rounded := class()
+	rule(models.PricingRule{CourtID: &rounded.CourtID, Action: models.PricingPercent, Value: -33.3333})
+
+	free := class()
+	rule(models.PricingRule{CourtID: &free.CourtID, Action: models.PricingAmount, Value: -150000})
+
+	inactive := class()
+	disabled := models.PricingRule{Name: "disabled", CourtID: &inactive.CourtID, Action: models.PricingSet, Value: 1}
+	if err := s.db.Create(&disabled).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.Model(&disabled).Update("is_active", false).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	overridden := class()
+	sessionPrice := 55000.4
+	if err := s.db.Model(overridden).Update("price", sessionPrice).Error; err != nil {
+		t.Fatal(err)
+	}
+	rule(models.PricingRule{CourtID: &overridden.CourtID, Action: models.PricingSet, Value: 1})
+
+	tests := []struct {
+		name  string
+		class *models.ClassSession
+		want  float64
+		steps int // Breakdown entries, including the starting price
+	}{
+		{"default price", plain, 100000, 1},
+		{"rules in priority order", ordered, 96000, 3},
+		{"weekday, timeslot and date conditions", scoped, 125000, 2},
+		{"rounded to whole Rupiah", rounded, 66667, 2},
+		{"never below zero", free, 0, 2},
+		{"inactive rule ignored", inactive, 100000, 1},
+		{"session price is final", overridden, 55000, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, breakdown, err := pricing.CalculatePrice(tt.class.CourtID, tt.class.TimeslotID, tt.class.Date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if price != tt.want {
+				t.Errorf("price = %v, want %v", price, tt.want)
+			}
+			if len(breakdown) != tt.steps {
+				t.Errorf("breakdown = %+v, want %d steps", breakdown, tt.steps)
+			}
+		})
+	}
+}
+
+func TestCreatePaymentForFreeClass(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	if err := s.db.Create(&models.PricingRule{Name: "Open day", CourtID: &session.CourtID, Action: models.PricingSet, Value: 0}).Error; err != nil {
+		t.Fatal(err)
+	}
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	if _, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID}); err == nil || err.Error() != "reservation has nothing to pay" {
+		t.Fatalf("paying for a free class: err = %v, want reservation has nothing to pay", err)
+	}
+
+	var payments int64
+	if err := s.db.Model(&models.Payment{}).Where("reservation_id = ?", reservation.ID).Count(&payments).Error; err != nil {
+		t.Fatal(err)
+	}
+	if payments != 0 {
+		t.Errorf("%d payments created for a free class", payments)
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"reservation-api/internal/config"
+	"reservation-api/internal/gateway"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefundService returns money for paid reservations that are cancelled
+type RefundService struct {
+	refundRepo          *repository.RefundRepository
+	paymentRepo         *repository.PaymentRepository
+	notificationService *NotificationService
+	gateway             gateway.PaymentGateway
+	config              *config.Config
+	clock               utils.Clock
+}
+
+// NewRefundService creates a new refund service
+func NewRefundService(
+	refundRepo *repository.RefundRepository,
+	paymentRepo *repository.PaymentRepository,
+	notificationService *NotificationService,
+	paymentGateway gateway.PaymentGateway,
+	cfg *config.Config,
+	clock utils.Clock,
+) *RefundService {
+	return &RefundService{
+		refundRepo:          refundRepo,
+		paymentRepo:         paymentRepo,
+		notificationService: notificationService,
+		gateway:             paymentGateway,
+		config:              cfg,
+		clock:               clock,
+	}
+}
+
+// RefundPercent applies the cancellation policy: a full refund when cancelling at
+// least FullRefundWindow before class start, PartialRefundPercent when cancelling
+// later, and nothing once the class has started
+func (s *RefundService) RefundPercent(classStart time.Time) int {
+	untilStart := classStart.Sub(s.clock.Now())
+	switch {
+	case untilStart >= s.config.FullRefundWindow:
+		return 100
+	case untilStart > 0:
+		return s.config.PartialRefundPercent
+	default:
+		return 0
+	}
+}
+
+// RefundCancelledReservation refunds the payment of a reservation cancelled by the
+// customer according to the cancellation policy. Returns nil if there is nothing to
+// refund.
+func (s *RefundService) RefundCancelledReservation(reservation *models.Reservation) (*models.Refund, error) {
+	classStart, err := reservation.StartTime(s.config.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RefundReservation(reservation, s.RefundPercent(classStart), "Reservation cancelled by customer")
+}
+
+// RefundReservation refunds a percentage of the payment of a reservation. Returns
+// nil if the reservation was not paid or the percentage is zero.
+func (s *RefundService) RefundReservation(reservation *models.Reservation, percent int, reason string) (*models.Refund, error) {
+	if percent <= 0 {
+		return nil, nil
+	}
+
+	payment, err := s.paymentRepo.FindPaidByReservationID(reservation.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s.refundPayment(payment, percent, reason)
+}
+
+// RefundPaidPayment refunds a percentage of a paid payment, e.g. one settled for a
+// membership cancelled while the payment was in progress. Returns nil if the payment
+// is not paid or was already refunded.
+func (s *RefundService) RefundPaidPayment(paymentID uint, percent int, reason string) (*models.Refund, error) {
+	payment, err := s.paymentRepo.FindByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if !payment.IsPaid() {
+		return nil, nil
+	}
+
+	return s.refundPayment(payment, percent, reason)
+}
+
+// RefundLatePayment handles a payment settled at the gateway after it had expired or
+// failed here, e.g. paid after its reservation was cancelled: it is recorded as paid
+// and refunded in full. Returns nil if the late payment was already handled.
+func (s *RefundService) RefundLatePayment(payment *models.Payment) (*models.Refund, error) {
+	recorded, err := s.paymentRepo.RecordLateSettlement(payment.ID, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, nil
+	}
+
+	payment, err = s.paymentRepo.FindByID(payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.refundPayment(payment, 100, "Paid after the order was cancelled")
+}
+
+// refundPayment creates a refund of a percentage of a paid payment and processes it.
+// A payment is refunded at most once: the refund key is derived from the payment, so
+// a concurrent second refund is dropped and nil returned.
+func (s *RefundService) refundPayment(payment *models.Payment, percent int, reason string) (*models.Refund, error) {
+	refund := &models.Refund{
+		PaymentID: payment.ID,
+		Amount:    math.Round(payment.Amount * float64(percent) / 100),
+		Percent:   percent,
+		Reason:    reason,
+		Status:    models.RefundPending,
+		RefundKey: fmt.Sprintf("RFD-%s", payment.TransactionID),
+	}
+
+	created, err := s.refundRepo.Create(refund)
+	if err != nil {
+		return nil, errors.New("failed to create refund")
+	}
+	if !created {
+		log.Printf("⚠️  Payment %s already has a refund", payment.TransactionID)
+		return nil, nil
+	}
+
+	return refund, s.process(refund, payment)
+}
+
+// RetryRefund sends a failed refund to the payment gateway again
+func (s *RefundService) RetryRefund(id uint) (*models.Refund, error) {
+	refund, err := s.refundRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refund not found")
+		}
+		return nil, err
+	}
+
+	if !refund.IsFailed() {
+		return nil, errors.New("only failed refunds can be retried")
+	}
+
+	return refund, s.process(refund, refund.Payment)
+}
+
+// GetRefunds gets all refunds
+func (s *RefundService) GetRefunds() ([]models.Refund, error) {
+	return s.refundRepo.FindAll()
+}
+
+// process calls the gateway refund API and records the result on the refund and
+// payment. The user is notified once the refund went through.
+func (s *RefundService) process(refund *models.Refund, payment *models.Payment) error {
+	_, err := s.gateway.Refund(gateway.RefundRequest{
+		OrderID:   payment.TransactionID,
+		RefundKey: refund.RefundKey,
+		Amount:    refund.Amount,
+		Reason:    refund.Reason,
+	})
+	if err != nil {
+		log.Printf("⚠️  Refund %s for payment %s failed: %v", refund.RefundKey, payment.TransactionID, err)
+		refund.Status = models.RefundFailed
+		refund.FailureReason = err.Error()
+		if updateErr := s.refundRepo.Update(refund); updateErr != nil {
+			return updateErr
+		}
+		return fmt.Errorf("refund failed: %v", err)
+	}
+
+	now := s.clock.Now()
+	refund.Status = models.RefundSucceeded
+	refund.FailureReason = ""
+	refund.RefundedAt = &now
+	if err := s.refundRepo.Update(refund); err != nil {
+		return err
+	}
+
+	payment.Status = models.PaymentPartiallyRefunded
+	if refund.Percent >= 100 {
+		payment.Status = models.PaymentRefunded
+	}
+	if err := s.paymentRepo.Update(payment); err != nil {
+		return err
+	}
+
+	if payment.ReservationID != nil {
+		s.notificationService.RefundIssued(*payment.ReservationID, refund)
+	}
+	return nil
+}
@@ -2,9 +2,13 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"reservation-api/api/dto"
+	"reservation-api/internal/config"
 	"reservation-api/internal/models"
 	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,93 +16,173 @@ import (
 
 // ReservationService handles reservation business logic
 type ReservationService struct {
-	reservationRepo *repository.ReservationRepository
-	courtRepo       *repository.CourtRepository
-	timeslotRepo    *repository.TimeslotRepository
+	reservationRepo     *repository.ReservationRepository
+	userRepo            *repository.UserRepository
+	sessionRepo         *repository.SessionRepository
+	closureRepo         *repository.ClosureRepository
+	creditRepo          *repository.CreditRepository
+	membershipRepo      *repository.MembershipRepository
+	refundService       *RefundService
+	waitlistService     *WaitlistService
+	attendanceService   *AttendanceService
+	notificationService *NotificationService
+	config              *config.Config
+	clock               utils.Clock
 }
 
 // NewReservationService creates a new reservation service
 func NewReservationService(
 	reservationRepo *repository.ReservationRepository,
-	courtRepo *repository.CourtRepository,
-	timeslotRepo *repository.TimeslotRepository,
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	closureRepo *repository.ClosureRepository,
+	creditRepo *repository.CreditRepository,
+	membershipRepo *repository.MembershipRepository,
+	refundService *RefundService,
+	waitlistService *WaitlistService,
+	attendanceService *AttendanceService,
+	notificationService *NotificationService,
+	cfg *config.Config,
+	clock utils.Clock,
 ) *ReservationService {
 	return &ReservationService{
-		reservationRepo: reservationRepo,
-		courtRepo:       courtRepo,
-		timeslotRepo:    timeslotRepo,
+		reservationRepo:     reservationRepo,
+		userRepo:            userRepo,
+		sessionRepo:         sessionRepo,
+		closureRepo:         closureRepo,
+		creditRepo:          creditRepo,
+		membershipRepo:      membershipRepo,
+		refundService:       refundService,
+		waitlistService:     waitlistService,
+		attendanceService:   attendanceService,
+		notificationService: notificationService,
+		config:              cfg,
+		clock:               clock,
 	}
 }
 
-// CreateReservation creates a new reservation
+// CreateReservation creates a new reservation in a class session
 func (s *ReservationService) CreateReservation(userID uint, req dto.CreateReservationRequest) (*models.Reservation, error) {
-	// Parse date
-	date, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	if req.UseCredit && req.UseMembership {
+		return nil, errors.New("choose either a credit or your membership to book")
 	}
 
-	// Check if date is in the past
-	today := time.Now().Truncate(24 * time.Hour)
-	if date.Before(today) {
-		return nil, errors.New("cannot book past dates")
+	if err := s.checkEmailVerified(userID); err != nil {
+		return nil, err
 	}
 
-	// Verify court exists
-	court, err := s.courtRepo.FindByID(req.CourtID)
+	if err := s.attendanceService.CheckBookingAllowed(userID); err != nil {
+		return nil, err
+	}
+
+	session, err := s.findBookingSession(req)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("court not found")
-		}
 		return nil, err
 	}
 
-	if !court.IsActive {
-		return nil, errors.New("court is not active")
+	// Check if the class already started in the studio time zone
+	now := s.clock.Now()
+	if session.Date.Before(utils.DateOf(now, s.config.Location)) {
+		return nil, errors.New("cannot book past dates")
 	}
 
-	// Verify timeslot exists
-	timeslot, err := s.timeslotRepo.FindByID(req.TimeslotID)
+	classStart, err := session.StartTime(s.config.Location)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("timeslot not found")
-		}
 		return nil, err
 	}
+	if !now.Before(classStart) {
+		return nil, errors.New("this class has already started")
+	}
+
+	if closure, err := s.closureRepo.FindCovering(session.CourtID, session.TimeslotID, session.Date); err == nil {
+		return nil, fmt.Errorf("the studio is closed on this date: %s", closure.Name)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if session.IsCancelled() {
+		return nil, errors.New("this class has been cancelled")
+	}
 
-	if !timeslot.IsActive {
+	if !session.Court.IsActive {
+		return nil, errors.New("court is not active")
+	}
+
+	if !session.Timeslot.IsActive {
 		return nil, errors.New("timeslot is not active")
 	}
 
-	// Check if court has available capacity (Group Class)
-	available, bookedCount, err := s.reservationRepo.CheckAvailability(req.CourtID, req.TimeslotID, date)
+	// Check the booking window and remaining seats of the class (Group Class)
+	class, err := s.GetSessionAvailability(session)
 	if err != nil {
 		return nil, err
 	}
 
-	if !available {
-		return nil, errors.New("failed to check availability")
-	}
-
-	// Check if court is full
-	if bookedCount >= court.Capacity {
+	switch class.LockReason {
+	case dto.LockNotOpenYet:
+		return nil, fmt.Errorf("booking for this class opens at %s", class.BookingOpensAt.Format("2006-01-02 15:04"))
+	case dto.LockClosed:
+		return nil, errors.New("booking for this class has closed")
+	case dto.LockFull:
 		return nil, errors.New("this class is already full. Please select another court or timeslot.")
 	}
 
-	// Create reservation
+	// Create reservation, atomically re-checking capacity against concurrent bookings
 	reservation := &models.Reservation{
 		UserID:     userID,
-		CourtID:    req.CourtID,
-		TimeslotID: req.TimeslotID,
-		Date:       date,
+		SessionID:  &session.ID,
+		CourtID:    session.CourtID,
+		TimeslotID: session.TimeslotID,
+		Date:       session.Date,
 		Status:     models.StatusPending,
 		Notes:      req.Notes,
 	}
 
-	if err := s.reservationRepo.Create(reservation); err != nil {
+	// Bookings paid with a credit or covered by a membership are confirmed right away.
+	// The credit is redeemed, or the weekly limit checked, in the same transaction so
+	// a failure does not leave a booking behind.
+	var onCreate func(tx *gorm.DB) error
+	if req.UseCredit {
+		reservation.Status = models.StatusConfirmed
+		reservation.PaidWithCredit = true
+		onCreate = func(tx *gorm.DB) error {
+			return s.creditRepo.WithTx(tx).Redeem(userID, reservation.ID, now)
+		}
+	}
+	if req.UseMembership {
+		membership, err := s.getBookingMembership(userID, classStart)
+		if err != nil {
+			return nil, err
+		}
+
+		reservation.Status = models.StatusConfirmed
+		reservation.MembershipID = &membership.ID
+		date := session.Date
+		weekStart := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7)) // Monday
+		onCreate = func(tx *gorm.DB) error {
+			return s.membershipRepo.WithTx(tx).CheckWeeklyLimit(membership.ID,
+				membership.Plan.WeeklyBookingLimit, weekStart, weekStart.AddDate(0, 0, 7))
+		}
+	}
+
+	if err := s.reservationRepo.CreateWithinCapacity(reservation, onCreate); err != nil {
+		if errors.Is(err, repository.ErrClassFull) {
+			return nil, errors.New("this class is already full. Please select another court or timeslot.")
+		}
+		if errors.Is(err, repository.ErrSessionCancelled) {
+			return nil, errors.New("this class has been cancelled")
+		}
+		if errors.Is(err, repository.ErrNoCredits) {
+			return nil, errors.New("no usable credits. Please purchase a class package")
+		}
+		if errors.Is(err, repository.ErrWeeklyLimitReached) {
+			return nil, errors.New("weekly booking limit of your membership reached")
+		}
 		return nil, errors.New("failed to create reservation")
 	}
 
+	s.notificationService.BookingCreated(reservation.ID)
+
 	// Load relations
 	reservation, err = s.reservationRepo.FindByID(reservation.ID)
 	if err != nil {
@@ -108,6 +192,70 @@ func (s *ReservationService) CreateReservation(userID uint, req dto.CreateReserv
 	return reservation, nil
 }
 
+// checkEmailVerified checks that a user verified their email address, if the studio
+// requires it to book
+func (s *ReservationService) checkEmailVerified(userID uint) error {
+	if !s.config.RequireVerifiedEmail {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	if !user.IsEmailVerified() {
+		return errors.New("booking restricted until your email address is verified")
+	}
+	return nil
+}
+
+// findBookingSession finds the session a reservation request books, either by its ID
+// or by court, timeslot and date
+func (s *ReservationService) findBookingSession(req dto.CreateReservationRequest) (*models.ClassSession, error) {
+	var session *models.ClassSession
+	var err error
+	if req.SessionID != 0 {
+		session, err = s.sessionRepo.FindByID(req.SessionID)
+	} else {
+		date, parseErr := time.Parse("2006-01-02", req.Date)
+		if parseErr != nil {
+			return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+		}
+		session, err = s.sessionRepo.FindByClass(req.CourtID, req.TimeslotID, date)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class not found")
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// getBookingMembership gets the membership a user books a class under. The membership
+// must currently give booking rights and be paid through the start of the class.
+func (s *ReservationService) getBookingMembership(userID uint, classStart time.Time) (*models.Membership, error) {
+	membership, err := s.membershipRepo.FindCovering(userID, s.clock.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no active membership. Please renew or purchase a membership")
+		}
+		return nil, err
+	}
+
+	if !classStart.Before(*membership.EndDate) {
+		return nil, errors.New("class is after the end of your paid membership period")
+	}
+
+	return membership, nil
+}
+
 // GetUserReservations gets all reservations for a user
 func (s *ReservationService) GetUserReservations(userID uint) ([]models.Reservation, error) {
 	return s.reservationRepo.FindByUserID(userID)
@@ -151,105 +299,333 @@ func (s *ReservationService) CancelReservation(id, userID uint) (*models.Reserva
 		return nil, errors.New("reservation cannot be cancelled")
 	}
 
-	// Check if reservation date is in the past
-	if reservation.Date.Before(time.Now().Truncate(24 * time.Hour)) {
+	// Check if the class already started in the studio time zone
+	classStart, err := reservation.StartTime(s.config.Location)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	if !now.Before(classStart) {
 		return nil, errors.New("cannot cancel past reservations")
 	}
 
-	// Update status
-	reservation.Status = models.StatusCancelled
-	if err := s.reservationRepo.Update(reservation); err != nil {
+	// Cancelling after the deadline is a late cancel, if the class allows it at all
+	policy := reservation.BookingPolicy(defaultBookingPolicy(s.config))
+	if !now.Before(policy.CancelDeadlineAt(classStart)) {
+		if !policy.AllowLateCancel {
+			return nil, errors.New("the cancellation deadline for this class has passed")
+		}
+		reservation.LateCancel = true
+	}
+
+	// Update status; a payment still in progress is expired with it. Only the request
+	// that cancels the reservation goes on to refund it and release the seat.
+	cancelled, err := s.reservationRepo.Cancel(reservation)
+	if err != nil {
 		return nil, errors.New("failed to cancel reservation")
 	}
+	if !cancelled {
+		return nil, errors.New("reservation cannot be cancelled")
+	}
+	reservation.Status = models.StatusCancelled
 
-	return reservation, nil
+	s.notificationService.ReservationCancelled(reservation.ID, "")
+
+	// Offer the seat to the waitlist
+	s.waitlistService.ReleaseSeat(reservation)
+
+	// Refund paid reservations according to the cancellation policy. A failed refund
+	// is kept for retry and does not undo the cancellation.
+	switch {
+	case reservation.MembershipID != nil:
+		// Not paid separately; cancelling only frees a weekly booking of the membership
+	case reservation.PaidWithCredit:
+		if err := s.returnCredit(reservation); err != nil {
+			log.Printf("⚠️  Credit for reservation %d not returned: %v", reservation.ID, err)
+		}
+	default:
+		if _, err := s.refundService.RefundCancelledReservation(reservation); err != nil {
+			log.Printf("⚠️  Refund for reservation %d not completed: %v", reservation.ID, err)
+		}
+	}
+
+	// Reload with payment and refund details
+	return s.reservationRepo.FindByID(reservation.ID)
+}
+
+// returnCredit gives the credit of a cancelled reservation back when the cancellation
+// qualifies for a full refund under the cancellation policy
+func (s *ReservationService) returnCredit(reservation *models.Reservation) error {
+	classStart, err := reservation.StartTime(s.config.Location)
+	if err != nil {
+		return err
+	}
+
+	if s.refundService.RefundPercent(classStart) < 100 {
+		return nil
+	}
+
+	_, err = s.creditRepo.ReturnCredit(reservation.ID, "Reservation cancelled by customer")
+	return err
+}
+
+// GetSessionAvailability gets seat usage and the booking window of a single session
+func (s *ReservationService) GetSessionAvailability(session *models.ClassSession) (dto.ClassAvailability, error) {
+	count, err := s.reservationRepo.CountSeats(session.CourtID, session.TimeslotID, session.Date)
+	if err != nil {
+		return dto.ClassAvailability{}, err
+	}
+	return s.buildClassAvailability(session, count), nil
+}
+
+// buildClassAvailability derives remaining seats from a session capacity and its seat
+// count, and whether the session can be booked now
+func (s *ReservationService) buildClassAvailability(session *models.ClassSession, count repository.SeatCount) dto.ClassAvailability {
+	capacity := session.SeatCapacity()
+	remaining := capacity - count.Booked - count.Held
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return dto.ClassAvailability{
+		SessionID:      session.ID,
+		CourtID:        session.CourtID,
+		TimeslotID:     session.TimeslotID,
+		Date:           session.Date.Format("2006-01-02"),
+		Capacity:       capacity,
+		SeatsBooked:    count.Booked,
+		SeatsHeld:      count.Held,
+		SeatsRemaining: remaining,
+		BookingWindow:  s.bookingWindow(session, remaining),
+	}
+}
+
+// defaultBookingPolicy gets the studio booking rules that courts and sessions override
+func defaultBookingPolicy(cfg *config.Config) models.BookingPolicy {
+	return models.BookingPolicy{
+		MinLead:         cfg.BookingMinLead,
+		MaxAdvanceDays:  cfg.BookingMaxAdvanceDays,
+		CancelDeadline:  cfg.CancelDeadline,
+		AllowLateCancel: cfg.AllowLateCancel,
+	}
+}
+
+// bookingWindow gets when a session can be booked and cancelled under its booking
+// rules, and why it cannot be booked now. Court and Timeslot must be loaded.
+func (s *ReservationService) bookingWindow(session *models.ClassSession, seatsRemaining int) dto.BookingWindow {
+	policy := session.BookingPolicy(defaultBookingPolicy(s.config))
+	classStart, err := session.StartTime(s.config.Location)
+	if err != nil {
+		return dto.BookingWindow{LockReason: dto.LockClosed}
+	}
+
+	window := dto.BookingWindow{
+		BookingOpensAt:  policy.BookingOpensAt(classStart),
+		BookingClosesAt: policy.BookingClosesAt(classStart),
+		CancelDeadline:  policy.CancelDeadlineAt(classStart),
+		AllowLateCancel: policy.AllowLateCancel,
+	}
+
+	now := s.clock.Now()
+	switch {
+	case now.Before(window.BookingOpensAt):
+		window.LockReason = dto.LockNotOpenYet
+	case !now.Before(window.BookingClosesAt):
+		window.LockReason = dto.LockClosed
+	case seatsRemaining <= 0:
+		window.LockReason = dto.LockFull
+	default:
+		window.Bookable = true
+	}
+	return window
+}
+
+// getSeatCounts gets taken seats of every class on a date, keyed by court ID and timeslot ID
+func (s *ReservationService) getSeatCounts(date time.Time) (map[[2]uint]repository.SeatCount, error) {
+	counts, err := s.reservationRepo.CountSeatsByDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[[2]uint]repository.SeatCount, len(counts))
+	for _, count := range counts {
+		result[[2]uint{count.CourtID, count.TimeslotID}] = count
+	}
+	return result, nil
+}
+
+// getBookableSessions gets the sessions on a date that are open for booking: scheduled,
+// not started yet and running on an active court and timeslot. When instructorID is
+// set, only sessions taught by that instructor are included.
+func (s *ReservationService) getBookableSessions(date time.Time, instructorID uint) ([]models.ClassSession, error) {
+	sessions, err := s.sessionRepo.FindByDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	result := sessions[:0]
+	for _, session := range sessions {
+		if session.IsCancelled() || !session.Court.IsActive || !session.Timeslot.IsActive {
+			continue
+		}
+		if start, err := session.StartTime(s.config.Location); err != nil || !now.Before(start) {
+			continue
+		}
+		if instructorID != 0 && (session.InstructorID == nil || *session.InstructorID != instructorID) {
+			continue
+		}
+		result = append(result, session)
+	}
+	return result, nil
+}
+
+// instructorSummary converts an assigned instructor for an availability response
+func instructorSummary(instructor *models.Instructor) *dto.InstructorSummary {
+	if instructor == nil {
+		return nil
+	}
+	return &dto.InstructorSummary{ID: instructor.ID, Name: instructor.Name}
+}
+
+// GetAvailableDates gets the dates, from today in the studio time zone, that have
+// scheduled classes open for booking under their booking rules
+func (s *ReservationService) GetAvailableDates() ([]string, error) {
+	today := utils.DateOf(s.clock.Now(), s.config.Location)
+	sessions, err := s.sessionRepo.FindUpcoming(today)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions are ordered by date, so sessions of a date are adjacent
+	var result []string
+	for i := range sessions {
+		session := &sessions[i]
+		date := session.Date.Format("2006-01-02")
+		if len(result) > 0 && result[len(result)-1] == date {
+			continue
+		}
+		if !session.Court.IsActive || !session.Timeslot.IsActive {
+			continue
+		}
+
+		window := s.bookingWindow(session, 1)
+		if window.LockReason == "" {
+			result = append(result, date)
+		}
+	}
+	return result, nil
 }
 
-// GetTimeslotsAvailability gets timeslots with availability info for a date
-func (s *ReservationService) GetTimeslotsAvailability(dateStr string) ([]dto.TimeslotAvailability, error) {
+// GetTimeslotsAvailability gets the timeslots with scheduled sessions on a date with
+// availability info. When instructorID is set, only sessions taught by that
+// instructor are included.
+func (s *ReservationService) GetTimeslotsAvailability(dateStr string, instructorID uint) ([]dto.TimeslotAvailability, error) {
 	// Parse date
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
 		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
 	}
 
-	// Get all timeslots
-	timeslots, err := s.timeslotRepo.FindAll()
+	sessions, err := s.getBookableSessions(date, instructorID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get total courts
-	totalCourts, err := s.courtRepo.CountAll()
+	seatCounts, err := s.getSeatCounts(date)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build availability info
+	// Build availability info, summing seats over all sessions of the timeslot.
+	// Sessions are ordered by time, so sessions of a timeslot are adjacent.
 	var result []dto.TimeslotAvailability
-	for _, ts := range timeslots {
-		bookedCount, err := s.reservationRepo.CountBookedByDateAndTimeslot(date, ts.ID)
-		if err != nil {
-			return nil, err
+	seen := make(map[uint]bool)
+	for i := range sessions {
+		session := &sessions[i]
+		if len(result) == 0 || result[len(result)-1].ID != session.TimeslotID {
+			result = append(result, dto.TimeslotAvailability{
+				ID:       session.Timeslot.ID,
+				Time:     session.Timeslot.Time,
+				Duration: session.Timeslot.Duration,
+				IsActive: session.Timeslot.IsActive,
+			})
+			seen = make(map[uint]bool)
 		}
+		item := &result[len(result)-1]
 
-		availableCourts := int(totalCourts) - int(bookedCount)
-		available := availableCourts > 0
+		if session.Instructor != nil && !seen[session.Instructor.ID] {
+			seen[session.Instructor.ID] = true
+			item.Instructors = append(item.Instructors, *instructorSummary(session.Instructor))
+		}
 
-		result = append(result, dto.TimeslotAvailability{
-			ID:              ts.ID,
-			Time:            ts.Time,
-			Duration:        ts.Duration,
-			IsActive:        ts.IsActive,
-			Available:       available,
-			BookedCount:     int(bookedCount),
-			AvailableCourts: availableCourts,
-		})
+		class := s.buildClassAvailability(session, seatCounts[[2]uint{session.CourtID, session.TimeslotID}])
+		item.Capacity += class.Capacity
+		item.SeatsBooked += class.SeatsBooked
+		item.SeatsHeld += class.SeatsHeld
+		item.SeatsRemaining += class.SeatsRemaining
+		if class.Bookable {
+			item.AvailableCourts++
+		} else if item.LockReason == "" {
+			item.LockReason = class.LockReason
+		}
+	}
+
+	for i := range result {
+		result[i].BookedCount = result[i].SeatsBooked + result[i].SeatsHeld
+		result[i].Available = result[i].AvailableCourts > 0
+		if result[i].Available {
+			result[i].LockReason = ""
+		}
 	}
 
 	return result, nil
 }
 
-// GetCourtsAvailability gets courts availability for a date and timeslot
-func (s *ReservationService) GetCourtsAvailability(dateStr string, timeslotID uint) ([]dto.CourtAvailability, error) {
+// GetCourtsAvailability gets the courts running a timeslot on a date with availability
+// info. When instructorID is set, only courts where that instructor teaches are included.
+func (s *ReservationService) GetCourtsAvailability(dateStr string, timeslotID, instructorID uint) ([]dto.CourtAvailability, error) {
 	// Parse date
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
 		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
 	}
 
-	// Get all courts
-	courts, err := s.courtRepo.FindAll()
+	sessions, err := s.getBookableSessions(date, instructorID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get booked court IDs
-	bookedCourtIDs, err := s.reservationRepo.GetBookedCourtIDs(date, timeslotID)
+	seatCounts, err := s.getSeatCounts(date)
 	if err != nil {
 		return nil, err
 	}
 
 	// Build availability info
 	var result []dto.CourtAvailability
-	for _, court := range courts {
-		available := true
-		for _, bookedID := range bookedCourtIDs {
-			if court.ID == bookedID {
-				available = false
-				break
-			}
+	for i := range sessions {
+		session := &sessions[i]
+		if session.TimeslotID != timeslotID {
+			continue
 		}
 
+		class := s.buildClassAvailability(session, seatCounts[[2]uint{session.CourtID, session.TimeslotID}])
+
 		result = append(result, dto.CourtAvailability{
-			ID:          court.ID,
-			Name:        court.Name,
-			Capacity:    court.Capacity,
-			Description: court.Description,
-			IsActive:    court.IsActive,
-			Available:   available,
+			ID:             session.Court.ID,
+			SessionID:      session.ID,
+			Name:           session.Court.Name,
+			Capacity:       class.Capacity,
+			Description:    session.Court.Description,
+			IsActive:       session.Court.IsActive,
+			Available:      class.Bookable,
+			SeatsBooked:    class.SeatsBooked,
+			SeatsHeld:      class.SeatsHeld,
+			SeatsRemaining: class.SeatsRemaining,
+			Instructor:     instructorSummary(session.Instructor),
+			BookingWindow:  class.BookingWindow,
 		})
 	}
 
 	return result, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"reservation-api/api/dto"
+	"reservation-api/internal/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreateReservationAroundJakartaMidnight(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Now())
+	user := seedUser(t, s.db, "member@example.com")
+
+	// Class dates are calendar dates, stored as midnight UTC
+	day := func(d int) time.Time { return time.Date(2030, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		date    time.Time
+		at      string
+		wantErr string
+	}{
+		{"late evening books after midnight", time.Date(2030, 1, 7, 23, 50, 0, 0, jakarta), day(8), "00:30", ""},
+		{"late evening books the same night", time.Date(2030, 1, 7, 23, 50, 0, 0, jakarta), day(7), "23:55", ""},
+		// Still 7 January in UTC, but 8 January at the studio
+		{"after midnight cannot book yesterday", time.Date(2030, 1, 8, 0, 10, 0, 0, jakarta), day(7), "23:00", "cannot book past dates"},
+		{"after midnight class already started", time.Date(2030, 1, 8, 0, 10, 0, 0, jakarta), day(8), "00:05", "this class has already started"},
+		{"after midnight books today", time.Date(2030, 1, 8, 0, 10, 0, 0, jakarta), day(8), "07:00", ""},
+		{"booking opens 30 days ahead", time.Date(2030, 1, 8, 0, 10, 0, 0, jakarta), day(7).AddDate(0, 1, 0), "00:30", "booking for this class opens at 2030-01-08 00:30"},
+		{"booking open 30 days ahead", time.Date(2030, 1, 8, 0, 10, 0, 0, jakarta), day(7).AddDate(0, 1, 0), "00:05", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.clock.now = tt.now
+			session := seedSession(t, s.db, 10, tt.date, tt.at)
+
+			reservation, err := s.reservations.CreateReservation(user.ID, dto.CreateReservationRequest{SessionID: session.ID})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reservation.Date.Equal(tt.date) {
+				t.Errorf("reservation date %s, want %s", reservation.Date, tt.date)
+			}
+		})
+	}
+}
+
+func TestConcurrentCancelsRefundOnce(t *testing.T) {
+	now := time.Now().In(jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	owner := seedUser(t, s.db, "owner@example.com")
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 7), "07:00")
+	reservation := seedReservation(t, s.db, owner, session, models.StatusPending, now)
+
+	payment, _, _, err := s.payments.CreatePayment(owner.ID, dto.CreatePaymentRequest{ReservationID: reservation.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.payments.SimulatePayment(payment.ID, owner.ID, "settlement"); err != nil {
+		t.Fatal(err)
+	}
+
+	const requests = 4
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, requests)
+	)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.reservations.CancelReservation(reservation.ID, owner.ID)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case err.Error() != "reservation cannot be cancelled":
+			t.Errorf("concurrent cancel: err = %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d cancels succeeded, want 1", succeeded)
+	}
+
+	var refunds int64
+	if err := s.db.Model(&models.Refund{}).Where("payment_id = ?", payment.ID).Count(&refunds).Error; err != nil {
+		t.Fatal(err)
+	}
+	if refunds != 1 {
+		t.Errorf("payment has %d refunds, want 1", refunds)
+	}
+}
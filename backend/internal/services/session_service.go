@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionService handles the class schedule: weekly class templates, the sessions
+// generated from them and per-session overrides and cancellations
+type SessionService struct {
+	sessionRepo         *repository.SessionRepository
+	closureRepo         *repository.ClosureRepository
+	reservationRepo     *repository.ReservationRepository
+	courtRepo           *repository.CourtRepository
+	timeslotRepo        *repository.TimeslotRepository
+	creditRepo          *repository.CreditRepository
+	instructorService   *InstructorService
+	refundService       *RefundService
+	waitlistService     *WaitlistService
+	notificationService *NotificationService
+	config              *config.Config
+	clock               utils.Clock
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(
+	sessionRepo *repository.SessionRepository,
+	closureRepo *repository.ClosureRepository,
+	reservationRepo *repository.ReservationRepository,
+	courtRepo *repository.CourtRepository,
+	timeslotRepo *repository.TimeslotRepository,
+	creditRepo *repository.CreditRepository,
+	instructorService *InstructorService,
+	refundService *RefundService,
+	waitlistService *WaitlistService,
+	notificationService *NotificationService,
+	cfg *config.Config,
+	clock utils.Clock,
+) *SessionService {
+	return &SessionService{
+		sessionRepo:         sessionRepo,
+		closureRepo:         closureRepo,
+		reservationRepo:     reservationRepo,
+		courtRepo:           courtRepo,
+		timeslotRepo:        timeslotRepo,
+		creditRepo:          creditRepo,
+		instructorService:   instructorService,
+		refundService:       refundService,
+		waitlistService:     waitlistService,
+		notificationService: notificationService,
+		config:              cfg,
+		clock:               clock,
+	}
+}
+
+// GetTemplates gets all class templates
+func (s *SessionService) GetTemplates() ([]models.ClassTemplate, error) {
+	return s.sessionRepo.FindTemplates()
+}
+
+// CreateTemplate creates a class template and generates its upcoming sessions
+func (s *SessionService) CreateTemplate(req dto.ClassTemplateRequest) (*models.ClassTemplate, error) {
+	template := &models.ClassTemplate{IsActive: true}
+	if err := s.applyTemplateRequest(template, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.CreateTemplate(template); err != nil {
+		return nil, errors.New("failed to create class template")
+	}
+
+	s.generate()
+	return template, nil
+}
+
+// UpdateTemplate replaces a class template's settings. Sessions already generated
+// keep their settings; only sessions generated later use the new ones.
+func (s *SessionService) UpdateTemplate(id uint, req dto.ClassTemplateRequest) (*models.ClassTemplate, error) {
+	template, err := s.sessionRepo.FindTemplateByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class template not found")
+		}
+		return nil, err
+	}
+
+	if err := s.applyTemplateRequest(template, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.UpdateTemplate(template); err != nil {
+		return nil, errors.New("failed to update class template")
+	}
+
+	s.generate()
+	return template, nil
+}
+
+// DeleteTemplate deletes a class template. Sessions already generated from it stay
+// scheduled and have to be cancelled separately.
+func (s *SessionService) DeleteTemplate(id uint) error {
+	if _, err := s.sessionRepo.FindTemplateByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("class template not found")
+		}
+		return err
+	}
+
+	return s.sessionRepo.DeleteTemplate(id)
+}
+
+// GenerateSessions creates the sessions of active templates from today through the
+// session horizon. Classes covered by a closure are not scheduled.
+func (s *SessionService) GenerateSessions(ctx context.Context) error {
+	today := utils.DateOf(s.clock.Now(), s.config.Location)
+	closures, err := s.closureRepo.FindFrom(today)
+	if err != nil {
+		return err
+	}
+
+	closed := func(courtID, timeslotID uint, date time.Time) bool {
+		for i := range closures {
+			if closures[i].Covers(courtID, timeslotID, date) {
+				return true
+			}
+		}
+		return false
+	}
+
+	created, err := s.sessionRepo.GenerateFromTemplates(today, today.AddDate(0, 0, s.config.SessionHorizon), closed)
+	if err != nil {
+		return err
+	}
+
+	if created > 0 {
+		log.Printf("📅 Generated %d class session(s)", created)
+	}
+
+	return nil
+}
+
+// generate runs session generation after a template change so its sessions show up
+// without waiting for the next scheduled run
+func (s *SessionService) generate() {
+	if err := s.GenerateSessions(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to generate class sessions: %v", err)
+	}
+}
+
+// GetSessions gets all sessions on a date, including cancelled ones
+func (s *SessionService) GetSessions(dateStr string) ([]models.ClassSession, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+	return s.sessionRepo.FindByDate(date)
+}
+
+// CreateSession schedules a one-off session that is not generated from a template
+func (s *SessionService) CreateSession(req dto.CreateSessionRequest) (*models.ClassSession, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+
+	if date.Before(utils.DateOf(s.clock.Now(), s.config.Location)) {
+		return nil, errors.New("cannot schedule sessions on past dates")
+	}
+
+	court, timeslot, err := s.findCourtAndTimeslot(req.CourtID, req.TimeslotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if closure, err := s.closureRepo.FindCovering(court.ID, timeslot.ID, date); err == nil {
+		return nil, fmt.Errorf("the studio is closed on this date: %s", closure.Name)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if _, err := s.sessionRepo.FindByClass(court.ID, timeslot.ID, date); err == nil {
+		return nil, errors.New("a session is already scheduled for this court, timeslot and date")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	session := &models.ClassSession{
+		CourtID:      court.ID,
+		TimeslotID:   timeslot.ID,
+		Date:         date,
+		Status:       models.SessionScheduled,
+		Capacity:     req.Capacity,
+		Price:        req.Price,
+		BookingRules: bookingRules(req.BookingRulesRequest),
+		Court:        court,
+		Timeslot:     timeslot,
+	}
+
+	if req.InstructorID != nil {
+		instructor, err := s.instructorService.CheckCanTeach(*req.InstructorID, session)
+		if err != nil {
+			return nil, err
+		}
+		session.InstructorID = &instructor.ID
+		session.Instructor = instructor
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	return session, nil
+}
+
+// UpdateSession replaces the capacity, instructor, price and booking rule overrides of a session.
+// The capacity cannot drop below the seats already taken; seats added are offered
+// to the waitlist.
+func (s *SessionService) UpdateSession(id uint, req dto.UpdateSessionRequest) (*models.ClassSession, error) {
+	session, err := s.findSession(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsCancelled() {
+		return nil, errors.New("cannot update a cancelled session")
+	}
+
+	capacity := session.Court.Capacity
+	if req.Capacity != nil {
+		capacity = *req.Capacity
+	}
+
+	count, err := s.reservationRepo.CountSeats(session.CourtID, session.TimeslotID, session.Date)
+	if err != nil {
+		return nil, err
+	}
+	if taken := count.Booked + count.Held; capacity < taken {
+		return nil, fmt.Errorf("capacity cannot be below the %d seats already taken", taken)
+	}
+
+	session.Instructor = nil
+	session.InstructorID = nil
+	if req.InstructorID != nil {
+		instructor, err := s.instructorService.CheckCanTeach(*req.InstructorID, session)
+		if err != nil {
+			return nil, err
+		}
+		session.InstructorID = &instructor.ID
+		session.Instructor = instructor
+	}
+
+	session.Capacity = req.Capacity
+	session.Price = req.Price
+	session.BookingRules = bookingRules(req.BookingRulesRequest)
+
+	if err := s.sessionRepo.Update(session); err != nil {
+		return nil, errors.New("failed to update session")
+	}
+
+	s.waitlistService.OfferFreeSeats(session)
+	return session, nil
+}
+
+// CancelSession cancels a session and its reservations. Customers get a full refund
+// of paid bookings or their credit back; membership bookings are simply released.
+// A failed refund is kept for retry and does not undo the cancellation.
+func (s *SessionService) CancelSession(id uint, reason string) (*models.ClassSession, error) {
+	session, err := s.findSession(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsCancelled() {
+		return nil, errors.New("session is already cancelled")
+	}
+
+	if reason == "" {
+		reason = "Class cancelled by the studio"
+	}
+
+	if err := s.cancel(session, reason, nil); err != nil {
+		return nil, err
+	}
+
+	return s.sessionRepo.FindByID(session.ID)
+}
+
+// cancel cancels a session and its reservations, notifying the users, returning credits
+// and refunding paid bookings in full. closureID is set when a closure cancels the
+// session.
+func (s *SessionService) cancel(session *models.ClassSession, reason string, closureID *uint) error {
+	cancelled, err := s.sessionRepo.Cancel(session.ID, reason, closureID)
+	if err != nil {
+		return errors.New("failed to cancel session")
+	}
+
+	for i := range cancelled {
+		reservation := &cancelled[i]
+		s.notificationService.ReservationCancelled(reservation.ID, reason)
+
+		switch {
+		case reservation.MembershipID != nil:
+			// Not paid separately; the weekly booking is released with the reservation
+		case reservation.PaidWithCredit:
+			if _, err := s.creditRepo.ReturnCredit(reservation.ID, reason); err != nil {
+				log.Printf("⚠️  Credit for reservation %d not returned: %v", reservation.ID, err)
+			}
+		default:
+			if _, err := s.refundService.RefundReservation(reservation, 100, reason); err != nil {
+				log.Printf("⚠️  Refund for reservation %d not completed: %v", reservation.ID, err)
+			}
+		}
+	}
+
+	log.Printf("🚫 Cancelled session %d and %d reservation(s)", session.ID, len(cancelled))
+	return nil
+}
+
+// CloseSessions cancels the scheduled sessions covered by a closure that have not
+// started yet. Returns the number of sessions cancelled.
+func (s *SessionService) CloseSessions(closure *models.Closure) (int, error) {
+	now := s.clock.Now()
+	sessions, err := s.sessionRepo.FindScheduledFrom(utils.DateOf(now, s.config.Location))
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	reason := "Studio closed: " + closure.Name
+	for i := range sessions {
+		session := &sessions[i]
+		if !closure.Covers(session.CourtID, session.TimeslotID, session.Date) {
+			continue
+		}
+		// Classes that already started today are left as they are
+		start, err := session.StartTime(s.config.Location)
+		if err != nil {
+			return closed, err
+		}
+		if !start.After(now) {
+			continue
+		}
+		if err := s.cancel(session, reason, &closure.ID); err != nil {
+			return closed, err
+		}
+		closed++
+	}
+
+	return closed, nil
+}
+
+// ReopenSessions schedules the sessions cancelled by a removed closure again, unless
+// another closure still covers them, and generates sessions skipped while it applied
+func (s *SessionService) ReopenSessions(closure *models.Closure) error {
+	sessions, err := s.sessionRepo.FindByClosure(closure.ID)
+	if err != nil {
+		return err
+	}
+
+	today := utils.DateOf(s.clock.Now(), s.config.Location)
+	for i := range sessions {
+		session := &sessions[i]
+		if session.Date.Before(today) {
+			continue
+		}
+		if _, err := s.closureRepo.FindCovering(session.CourtID, session.TimeslotID, session.Date); err == nil {
+			continue
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := s.sessionRepo.Reopen(session.ID); err != nil {
+			return err
+		}
+	}
+
+	s.generate()
+	return nil
+}
+
+// findSession finds a session by ID
+func (s *SessionService) findSession(id uint) (*models.ClassSession, error) {
+	session, err := s.sessionRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// findCourtAndTimeslot finds the court and timeslot a class runs on
+func (s *SessionService) findCourtAndTimeslot(courtID, timeslotID uint) (*models.Court, *models.Timeslot, error) {
+	court, err := s.courtRepo.FindByID(courtID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("court not found")
+		}
+		return nil, nil, err
+	}
+
+	timeslot, err := s.timeslotRepo.FindByID(timeslotID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("timeslot not found")
+		}
+		return nil, nil, err
+	}
+
+	return court, timeslot, nil
+}
+
+// applyTemplateRequest validates a request and copies it onto a template. A default
+// instructor must be active and available at the template's weekday and time.
+func (s *SessionService) applyTemplateRequest(template *models.ClassTemplate, req dto.ClassTemplateRequest) error {
+	court, timeslot, err := s.findCourtAndTimeslot(req.CourtID, req.TimeslotID)
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.sessionRepo.TemplateExists(court.ID, timeslot.ID, req.Weekday, template.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("a class template already exists for this court, timeslot and weekday")
+	}
+
+	if req.InstructorID != nil {
+		instructor, err := s.instructorService.GetInstructor(*req.InstructorID)
+		if err != nil {
+			return err
+		}
+		if !instructor.IsActive {
+			return errors.New("instructor is not active")
+		}
+
+		// Check availability on the next date falling on the template's weekday
+		today := utils.DateOf(s.clock.Now(), s.config.Location)
+		date := today.AddDate(0, 0, (req.Weekday-int(today.Weekday())+7)%7)
+		session := models.ClassSession{Date: date, Timeslot: timeslot}
+		start, err := session.StartTime(s.config.Location)
+		if err != nil {
+			return err
+		}
+		if !instructor.IsAvailable(start, timeslot.Duration) {
+			return errors.New("instructor is not available at this time")
+		}
+	}
+
+	template.CourtID = court.ID
+	template.TimeslotID = timeslot.ID
+	template.Weekday = req.Weekday
+	template.InstructorID = req.InstructorID
+	template.Capacity = req.Capacity
+	template.Price = req.Price
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// bookingRules converts the booking rules of a request
+func bookingRules(req dto.BookingRulesRequest) models.BookingRules {
+	return models.BookingRules{
+		MinLeadMinutes:      req.MinLeadMinutes,
+		MaxAdvanceDays:      req.MaxAdvanceDays,
+		CancelDeadlineHours: req.CancelDeadlineHours,
+		AllowLateCancel:     req.AllowLateCancel,
+	}
+}
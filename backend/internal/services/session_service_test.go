@@ -0,0 +1,44 @@
+package services
+
+import (
+	"reservation-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCloseSessionsSkipsStartedClasses(t *testing.T) {
+	s := newTestServices(t, testConfig(jakarta), time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta))
+
+	today := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	started := seedSession(t, s.db, 10, today, "07:00")
+	later := seedSession(t, s.db, 10, today, "18:00")
+	yesterday := seedSession(t, s.db, 10, today.AddDate(0, 0, -1), "18:00")
+
+	closure := &models.Closure{Name: "Renovation", StartDate: today.AddDate(0, 0, -1), EndDate: today}
+	if err := s.db.Create(closure).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	closed, err := s.sessions.CloseSessions(closure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closed != 1 {
+		t.Errorf("closed %d sessions, want 1", closed)
+	}
+
+	want := map[uint]models.SessionStatus{
+		started.ID:   models.SessionScheduled,
+		later.ID:     models.SessionCancelled,
+		yesterday.ID: models.SessionScheduled,
+	}
+	for id, status := range want {
+		var session models.ClassSession
+		if err := s.db.First(&session, id).Error; err != nil {
+			t.Fatal(err)
+		}
+		if session.Status != status {
+			t.Errorf("session %d is %s, want %s", id, session.Status, status)
+		}
+	}
+}
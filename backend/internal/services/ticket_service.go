@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+// ticketQRSize is the width and height of ticket QR codes in pixels
+const ticketQRSize = 320
+
+// TicketService issues and verifies signed tickets of confirmed reservations
+type TicketService struct {
+	reservationRepo *repository.ReservationRepository
+	config          *config.Config
+}
+
+// NewTicketService creates a new ticket service
+func NewTicketService(reservationRepo *repository.ReservationRepository, cfg *config.Config) *TicketService {
+	return &TicketService{
+		reservationRepo: reservationRepo,
+		config:          cfg,
+	}
+}
+
+// GetTicket gets the ticket of a user's confirmed reservation
+func (s *TicketService) GetTicket(reservationID, userID uint) (*dto.TicketResponse, error) {
+	reservation, err := s.findTicketReservation(reservationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TicketResponse{
+		ReservationID: reservation.ID,
+		Token:         s.sign(reservation),
+		QRCodeURL:     fmt.Sprintf("/api/v1/reservations/%d/ticket/qr", reservation.ID),
+		TicketClass:   ticketClass(reservation),
+	}, nil
+}
+
+// GetTicketQR gets the ticket token of a user's confirmed reservation as a PNG QR code
+func (s *TicketService) GetTicketQR(reservationID, userID uint) ([]byte, error) {
+	reservation, err := s.findTicketReservation(reservationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.Encode(s.sign(reservation), qrcode.Medium, ticketQRSize)
+}
+
+// VerifyTicket validates the signature of a ticket token and that it still matches
+// a reservation with a ticket. Returns the reservation with relations.
+func (s *TicketService) VerifyTicket(token string) (*models.Reservation, error) {
+	ticket, err := utils.ParseTicket(token, s.config.TicketSecret)
+	if err != nil {
+		return nil, errors.New("invalid ticket")
+	}
+
+	reservation, err := s.reservationRepo.FindByID(ticket.ReservationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid ticket")
+		}
+		return nil, err
+	}
+
+	// A ticket is issued for one class; rebooked or moved reservations need a new one
+	if ticket.Date != reservation.Date.Format("2006-01-02") || ticket.CourtID != reservation.CourtID {
+		return nil, errors.New("ticket does not match the reservation")
+	}
+
+	if !reservation.HasTicket() && reservation.Status != models.StatusNoShow {
+		return nil, fmt.Errorf("ticket is no longer valid: reservation is %s", reservation.Status)
+	}
+
+	return reservation, nil
+}
+
+// GetVerification verifies a ticket token and gets its attendee and class details
+func (s *TicketService) GetVerification(token string) (*dto.TicketVerification, error) {
+	reservation, err := s.VerifyTicket(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TicketVerification{
+		ReservationID: reservation.ID,
+		Status:        string(reservation.Status),
+		CheckedInAt:   reservation.CheckedInAt,
+		Attendee: dto.TicketAttendee{
+			UserID: reservation.UserID,
+			Name:   reservation.User.Name,
+			Email:  reservation.User.Email,
+			Phone:  reservation.User.Phone,
+		},
+		Class: ticketClass(reservation),
+	}, nil
+}
+
+// findTicketReservation finds a reservation of a user that has a ticket
+func (s *TicketService) findTicketReservation(reservationID, userID uint) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.FindByID(reservationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("reservation not found")
+		}
+		return nil, err
+	}
+
+	if reservation.UserID != userID {
+		return nil, errors.New("unauthorized access to reservation")
+	}
+
+	if !reservation.HasTicket() {
+		return nil, errors.New("tickets are only available for confirmed reservations")
+	}
+
+	return reservation, nil
+}
+
+// sign signs the ticket of a reservation
+func (s *TicketService) sign(reservation *models.Reservation) string {
+	return utils.SignTicket(utils.Ticket{
+		ReservationID: reservation.ID,
+		Date:          reservation.Date.Format("2006-01-02"),
+		CourtID:       reservation.CourtID,
+	}, s.config.TicketSecret)
+}
+
+// ticketClass converts the class of a reservation for a ticket. Court, Timeslot and
+// Session must be loaded.
+func ticketClass(reservation *models.Reservation) dto.TicketClass {
+	class := dto.TicketClass{
+		CourtID:   reservation.CourtID,
+		CourtName: reservation.Court.Name,
+		Date:      reservation.Date.Format("2006-01-02"),
+		Time:      reservation.Timeslot.Time,
+		Duration:  reservation.Timeslot.Duration,
+	}
+	if reservation.Session != nil {
+		class.SessionID = reservation.Session.ID
+		class.Instructor = instructorSummary(reservation.Session.Instructor)
+	}
+	return class
+}
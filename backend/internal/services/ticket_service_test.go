@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"reservation-api/internal/models"
+	"reservation-api/internal/utils"
+	"testing"
+	"time"
+)
+
+func TestVerifyTicket(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)
+	cfg := testConfig(jakarta)
+	cfg.TicketSecret = "ticket-secret"
+	s := newTestServices(t, cfg, now)
+	tickets := NewTicketService(s.reservationRepo, cfg)
+
+	session := seedSession(t, s.db, 10, now.AddDate(0, 0, 1), "07:00")
+	other := seedSession(t, s.db, 10, now.AddDate(0, 0, 2), "07:00")
+
+	members := 0
+	book := func(status models.ReservationStatus) *models.Reservation {
+		members++
+		member := seedUser(t, s.db, fmt.Sprintf("member%d@example.com", members))
+		return seedReservation(t, s.db, member, session, status, now)
+	}
+	confirmed := book(models.StatusConfirmed)
+	noShow := book(models.StatusNoShow)
+	cancelled := book(models.StatusCancelled)
+	moved := book(models.StatusConfirmed)
+	rescheduled := book(models.StatusConfirmed)
+
+	// Tokens are issued for the class at booking time
+	tokens := map[uint]string{}
+	for _, reservation := range []*models.Reservation{confirmed, noShow, cancelled, moved, rescheduled} {
+		tokens[reservation.ID] = tickets.sign(reservation)
+	}
+	if err := s.db.Model(moved).Update("court_id", other.CourtID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.Model(rescheduled).Update("date", other.Date).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"confirmed", tokens[confirmed.ID], ""},
+		{"no-show can still check in late", tokens[noShow.ID], ""},
+		{"cancelled", tokens[cancelled.ID], "ticket is no longer valid: reservation is cancelled"},
+		{"moved to another court", tokens[moved.ID], "ticket does not match the reservation"},
+		{"moved to another date", tokens[rescheduled.ID], "ticket does not match the reservation"},
+		{"signed with another secret", utils.SignTicket(utils.Ticket{
+			ReservationID: confirmed.ID, Date: confirmed.Date.Format("2006-01-02"), CourtID: confirmed.CourtID,
+		}, "other-secret"), "invalid ticket"},
+		{"unknown reservation", utils.SignTicket(utils.Ticket{
+			ReservationID: rescheduled.ID + 1000, Date: confirmed.Date.Format("2006-01-02"), CourtID: confirmed.CourtID,
+		}, cfg.TicketSecret), "invalid ticket"},
+		{"garbage", "not-a-ticket", "invalid ticket"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reservation, err := tickets.VerifyTicket(tt.token)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("VerifyTicket: err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyTicket: %v", err)
+			}
+			if reservation.User.ID != reservation.UserID {
+				t.Errorf("VerifyTicket returned reservation %d without its attendee", reservation.ID)
+			}
+		})
+	}
+}
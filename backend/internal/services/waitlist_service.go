@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reservation-api/api/dto"
+	"reservation-api/internal/config"
+	"reservation-api/internal/models"
+	"reservation-api/internal/repository"
+	"reservation-api/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistService handles waitlists of full classes. When a seat is released it is
+// offered to the next user in line as a pending reservation they can claim by
+// paying within the claim window.
+type WaitlistService struct {
+	waitlistRepo        *repository.WaitlistRepository
+	reservationRepo     *repository.ReservationRepository
+	sessionRepo         *repository.SessionRepository
+	notificationService *NotificationService
+	config              *config.Config
+	clock               utils.Clock
+}
+
+// NewWaitlistService creates a new waitlist service
+func NewWaitlistService(
+	waitlistRepo *repository.WaitlistRepository,
+	reservationRepo *repository.ReservationRepository,
+	sessionRepo *repository.SessionRepository,
+	notificationService *NotificationService,
+	cfg *config.Config,
+	clock utils.Clock,
+) *WaitlistService {
+	return &WaitlistService{
+		waitlistRepo:        waitlistRepo,
+		reservationRepo:     reservationRepo,
+		sessionRepo:         sessionRepo,
+		notificationService: notificationService,
+		config:              cfg,
+		clock:               clock,
+	}
+}
+
+// Join puts a user on the waitlist of a full class
+func (s *WaitlistService) Join(userID uint, req dto.JoinWaitlistRequest) (*models.WaitlistEntry, error) {
+	var session *models.ClassSession
+	var err error
+	if req.SessionID != 0 {
+		session, err = s.sessionRepo.FindByID(req.SessionID)
+	} else {
+		date, parseErr := time.Parse("2006-01-02", req.Date)
+		if parseErr != nil {
+			return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+		}
+		session, err = s.sessionRepo.FindByClass(req.CourtID, req.TimeslotID, date)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("class not found")
+		}
+		return nil, err
+	}
+
+	date := session.Date
+	now := s.clock.Now()
+	if date.Before(utils.DateOf(now, s.config.Location)) {
+		return nil, errors.New("cannot join waitlist for past dates")
+	}
+
+	classStart, err := session.StartTime(s.config.Location)
+	if err != nil {
+		return nil, err
+	}
+	if !now.Before(classStart) {
+		return nil, errors.New("this class has already started")
+	}
+
+	if session.IsCancelled() {
+		return nil, errors.New("this class has been cancelled")
+	}
+
+	court, timeslot := session.Court, session.Timeslot
+	if !court.IsActive {
+		return nil, errors.New("court is not active")
+	}
+	if !timeslot.IsActive {
+		return nil, errors.New("timeslot is not active")
+	}
+
+	// Only full classes have a waitlist
+	count, err := s.reservationRepo.CountSeats(court.ID, timeslot.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	if count.Booked+count.Held < session.SeatCapacity() {
+		return nil, errors.New("this class still has seats available. Please book it directly")
+	}
+
+	booked, err := s.reservationRepo.HasActiveReservation(userID, court.ID, timeslot.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	if booked {
+		return nil, errors.New("you already have a reservation for this class")
+	}
+
+	waiting, err := s.waitlistRepo.HasOpenEntry(userID, court.ID, timeslot.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	if waiting {
+		return nil, errors.New("you are already on the waitlist for this class")
+	}
+
+	entry := &models.WaitlistEntry{
+		UserID:     userID,
+		CourtID:    court.ID,
+		TimeslotID: timeslot.ID,
+		Date:       date,
+		Status:     models.WaitlistWaiting,
+	}
+
+	if err := s.waitlistRepo.Create(entry); err != nil {
+		return nil, errors.New("failed to join waitlist")
+	}
+
+	entry.Court = court
+	entry.Timeslot = timeslot
+	entry.Position, err = s.waitlistRepo.Position(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetUserWaitlist gets the waitlist entries of a user with their place in line
+func (s *WaitlistService) GetUserWaitlist(userID uint) ([]models.WaitlistEntry, error) {
+	entries, err := s.waitlistRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].Status != models.WaitlistWaiting {
+			continue
+		}
+		if entries[i].Position, err = s.waitlistRepo.Position(&entries[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// Leave takes a user off a waitlist. Leaving with an open offer gives the seat to
+// the next user in line.
+func (s *WaitlistService) Leave(id, userID uint) error {
+	entry, err := s.waitlistRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("waitlist entry not found")
+		}
+		return err
+	}
+
+	if entry.UserID != userID {
+		return errors.New("unauthorized access to waitlist entry")
+	}
+
+	if !entry.IsOpen() {
+		return errors.New("waitlist entry is no longer active")
+	}
+
+	released, err := s.waitlistRepo.Leave(entry.ID)
+	if err != nil {
+		return errors.New("failed to leave waitlist")
+	}
+
+	if released {
+		s.promote(entry.CourtID, entry.TimeslotID, entry.Date)
+	}
+
+	return nil
+}
+
+// LeaveAll takes a user off every waitlist they are on
+func (s *WaitlistService) LeaveAll(userID uint) error {
+	entries, err := s.waitlistRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsOpen() {
+			continue
+		}
+		if err := s.Leave(entry.ID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseSeat is called when a reservation stops holding a seat (cancelled or hold
+// expired). An offer made with the reservation is closed as expired and the seat
+// is offered to the next user in line.
+func (s *WaitlistService) ReleaseSeat(reservation *models.Reservation) {
+	if err := s.waitlistRepo.CloseOffer(reservation.ID, models.WaitlistExpired); err != nil {
+		log.Printf("⚠️  Failed to close waitlist offer for reservation %d: %v", reservation.ID, err)
+	}
+
+	s.promote(reservation.CourtID, reservation.TimeslotID, reservation.Date)
+}
+
+// OfferFreeSeats offers any free seats of a session to its waitlist, e.g. after its
+// capacity was raised
+func (s *WaitlistService) OfferFreeSeats(session *models.ClassSession) {
+	s.promote(session.CourtID, session.TimeslotID, session.Date)
+}
+
+// ClaimOffer marks the waitlist offer made with a reservation as claimed once paid
+func (s *WaitlistService) ClaimOffer(reservationID uint) {
+	if err := s.waitlistRepo.CloseOffer(reservationID, models.WaitlistClaimed); err != nil {
+		log.Printf("⚠️  Failed to claim waitlist offer for reservation %d: %v", reservationID, err)
+	}
+}
+
+// ExpirePastEntries expires entries still waiting for classes that already took place
+func (s *WaitlistService) ExpirePastEntries(ctx context.Context) error {
+	expired, err := s.waitlistRepo.ExpireWaiting(utils.DateOf(s.clock.Now(), s.config.Location))
+	if err != nil {
+		return err
+	}
+
+	if expired > 0 {
+		log.Printf("⌛ Expired %d waitlist entry(s) of past classes", expired)
+	}
+
+	return nil
+}
+
+// promote offers the free seats of a class to the next users on its waitlist, as
+// long as the class has not started
+func (s *WaitlistService) promote(courtID, timeslotID uint, date time.Time) {
+	now := s.clock.Now()
+	if date.Before(utils.DateOf(now, s.config.Location)) {
+		return
+	}
+
+	session, err := s.sessionRepo.FindByClass(courtID, timeslotID, date)
+	if err != nil {
+		log.Printf("⚠️  Failed to load class of court %d, timeslot %d on %s for its waitlist: %v",
+			courtID, timeslotID, date.Format("2006-01-02"), err)
+		return
+	}
+	start, err := session.StartTime(s.config.Location)
+	if err != nil {
+		log.Printf("⚠️  Failed to get start of session %d for its waitlist: %v", session.ID, err)
+		return
+	}
+	if !start.After(now) {
+		return
+	}
+
+	promoted, err := s.waitlistRepo.PromoteNext(courtID, timeslotID, date, now, now.Add(s.config.WaitlistClaimWindow))
+	if err != nil {
+		log.Printf("⚠️  Failed to promote waitlist of court %d, timeslot %d on %s: %v",
+			courtID, timeslotID, date.Format("2006-01-02"), err)
+		return
+	}
+
+	for _, entry := range promoted {
+		log.Printf("🎟️  Offered seat to user %d from waitlist (reservation %d)", entry.UserID, *entry.ReservationID)
+		s.notificationService.WaitlistOffered(*entry.ReservationID)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reservation-api/api/dto"
+	"reservation-api/internal/models"
+	"testing"
+	"time"
+)
+
+// joinWaitlist puts new members on the waitlist of a class, in order
+func joinWaitlist(t *testing.T, s *testServices, session *models.ClassSession, members int) []*models.WaitlistEntry {
+	t.Helper()
+	entries := make([]*models.WaitlistEntry, members)
+	for i := range entries {
+		user := seedUser(t, s.db, fmt.Sprintf("waiting%d@example.com", i))
+		entry, err := s.waitlist.Join(user.ID, dto.JoinWaitlistRequest{SessionID: session.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// assertWaitlist checks the status of waitlist entries
+func assertWaitlist(t *testing.T, s *testServices, entries []*models.WaitlistEntry, want ...models.WaitlistStatus) {
+	t.Helper()
+	for i, entry := range entries {
+		var current models.WaitlistEntry
+		if err := s.db.First(&current, entry.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if current.Status != want[i] {
+			t.Errorf("entry %d is %s, want %s", i, current.Status, want[i])
+		}
+	}
+}
+
+func TestWaitlistPromotesInOrder(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)
+	cfg := testConfig(jakarta)
+	s := newTestServices(t, cfg, now)
+
+	session := seedSession(t, s.db, 1, now.AddDate(0, 0, 1), "07:00")
+	booked := seedReservation(t, s.db, seedUser(t, s.db, "booked@example.com"), session, models.StatusConfirmed, now)
+	entries := joinWaitlist(t, s, session, 3)
+
+	if _, err := s.reservations.CancelReservation(booked.ID, booked.UserID); err != nil {
+		t.Fatal(err)
+	}
+	assertWaitlist(t, s, entries, models.WaitlistOffered, models.WaitlistWaiting, models.WaitlistWaiting)
+
+	var offered models.WaitlistEntry
+	if err := s.db.Preload("Reservation").First(&offered, entries[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if offered.Reservation == nil || offered.Reservation.Status != models.StatusPending || offered.Reservation.UserID != entries[0].UserID {
+		t.Fatalf("offer holds reservation %+v, want a pending reservation of the first in line", offered.Reservation)
+	}
+	if want := now.Add(cfg.WaitlistClaimWindow); offered.OfferExpiresAt == nil || !offered.OfferExpiresAt.Equal(want) {
+		t.Errorf("offer expires at %v, want %v", offered.OfferExpiresAt, want)
+	}
+
+	// The offer still holds the seat until the claim window ends
+	s.clock.Advance(cfg.WaitlistClaimWindow - time.Minute)
+	if err := s.expiry.ExpireHolds(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertWaitlist(t, s, entries, models.WaitlistOffered, models.WaitlistWaiting, models.WaitlistWaiting)
+
+	// An unclaimed offer expires and the seat moves on to the next in line
+	s.clock.Advance(time.Minute)
+	if err := s.expiry.ExpireHolds(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertWaitlist(t, s, entries, models.WaitlistExpired, models.WaitlistOffered, models.WaitlistWaiting)
+
+	expired, err := s.reservationRepo.FindByID(*offered.ReservationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired.Status != models.StatusCancelled {
+		t.Errorf("unclaimed reservation is %s, want %s", expired.Status, models.StatusCancelled)
+	}
+}
+
+func TestWaitlistClaimedOffer(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	session := seedSession(t, s.db, 1, now.AddDate(0, 0, 1), "07:00")
+	booked := seedReservation(t, s.db, seedUser(t, s.db, "booked@example.com"), session, models.StatusConfirmed, now)
+	entries := joinWaitlist(t, s, session, 2)
+
+	if _, err := s.reservations.CancelReservation(booked.ID, booked.UserID); err != nil {
+		t.Fatal(err)
+	}
+	var offered models.WaitlistEntry
+	if err := s.db.First(&offered, entries[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	payment, _, _, err := s.payments.CreatePayment(entries[0].UserID, dto.CreatePaymentRequest{ReservationID: *offered.ReservationID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.payments.SimulatePayment(payment.ID, entries[0].UserID, "settlement"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.clock.Advance(2 * time.Hour)
+	if err := s.expiry.ExpireHolds(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertWaitlist(t, s, entries, models.WaitlistClaimed, models.WaitlistWaiting)
+}
+
+func TestWaitlistNotPromotedAfterClassStart(t *testing.T) {
+	// Joined early on the day of a 07:00 class
+	now := time.Date(2030, 1, 7, 6, 0, 0, 0, jakarta)
+	s := newTestServices(t, testConfig(jakarta), now)
+
+	session := seedSession(t, s.db, 1, now, "07:00")
+	booked := seedReservation(t, s.db, seedUser(t, s.db, "booked@example.com"), session, models.StatusConfirmed, now)
+	entries := joinWaitlist(t, s, session, 1)
+
+	// A seat freed once the class is underway is not offered
+	s.clock.Advance(90 * time.Minute)
+	if err := s.db.Model(booked).Update("status", models.StatusCancelled).Error; err != nil {
+		t.Fatal(err)
+	}
+	s.waitlist.ReleaseSeat(booked)
+	assertWaitlist(t, s, entries, models.WaitlistWaiting)
+
+	var offers int64
+	if err := s.db.Model(&models.Reservation{}).Where("user_id = ?", entries[0].UserID).Count(&offers).Error; err != nil {
+		t.Fatal(err)
+	}
+	if offers != 0 {
+		t.Errorf("%d reservations offered for a class that started", offers)
+	}
+}
@@ -0,0 +1,69 @@
+// Package testdb connects tests to a PostgreSQL database
+package testdb
+
+import (
+	"fmt"
+	"os"
+	"reservation-api/internal/database"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open connects to the database at TEST_DATABASE_URL and migrates it into a
+// schema of its own, dropped when the test ends, so tests never see each
+// other's rows. The test is skipped when TEST_DATABASE_URL is not set.
+func Open(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(url, schema)), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("connect to test schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := database.AutoMigrate(db); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+
+	return db
+}
+
+// withSearchPath adds a search_path to a URL or key=value connection string
+func withSearchPath(url, schema string) string {
+	if !strings.Contains(url, "://") {
+		return fmt.Sprintf("%s search_path=%s", url, schema)
+	}
+	if strings.Contains(url, "?") {
+		return url + "&search_path=" + schema
+	}
+	return url + "?search_path=" + schema
+}
@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// Clock provides the current time so time-dependent logic can be tested with a fixed clock
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock backed by the system time
+type SystemClock struct{}
+
+// Now returns the current system time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
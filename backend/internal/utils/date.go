@@ -0,0 +1,13 @@
+package utils
+
+import "time"
+
+// Calendar dates (class dates, closure dates) are stored as midnight UTC of the day,
+// independent of the studio time zone. Combine them with a time of day in the studio
+// location to get an instant.
+
+// DateOf returns the calendar date of an instant as seen in the given location
+func DateOf(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestDateOf(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		t    time.Time
+		loc  *time.Location
+		want string
+	}{
+		{"Jakarta just before midnight", time.Date(2030, 1, 6, 16, 59, 59, 0, time.UTC), jakarta, "2030-01-06"},
+		{"Jakarta at midnight", time.Date(2030, 1, 6, 17, 0, 0, 0, time.UTC), jakarta, "2030-01-07"},
+		{"Jakarta instant given in Jakarta", time.Date(2030, 1, 7, 0, 30, 0, 0, jakarta), jakarta, "2030-01-07"},
+		{"Jakarta instant seen in UTC", time.Date(2030, 1, 7, 0, 30, 0, 0, jakarta), time.UTC, "2030-01-06"},
+		{"Jakarta new year", time.Date(2029, 12, 31, 17, 0, 0, 0, time.UTC), jakarta, "2030-01-01"},
+		// Clocks go from 02:00 to 03:00 on 10 March 2030
+		{"New York before spring forward", time.Date(2030, 3, 10, 4, 59, 0, 0, time.UTC), newYork, "2030-03-09"},
+		{"New York midnight before spring forward", time.Date(2030, 3, 10, 5, 0, 0, 0, time.UTC), newYork, "2030-03-10"},
+		{"New York midnight after spring forward", time.Date(2030, 3, 11, 4, 0, 0, 0, time.UTC), newYork, "2030-03-11"},
+		{"New York late on spring forward day", time.Date(2030, 3, 11, 3, 59, 0, 0, time.UTC), newYork, "2030-03-10"},
+		// Clocks go from 02:00 back to 01:00 on 3 November 2030
+		{"New York midnight after fall back", time.Date(2030, 11, 4, 5, 0, 0, 0, time.UTC), newYork, "2030-11-04"},
+		{"New York late on fall back day", time.Date(2030, 11, 4, 4, 59, 0, 0, time.UTC), newYork, "2030-11-03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DateOf(tt.t, tt.loc)
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("DateOf = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+			if got.Location() != time.UTC || got.Hour() != 0 || got.Minute() != 0 || got.Second() != 0 {
+				t.Errorf("DateOf = %s, want midnight UTC", got)
+			}
+		})
+	}
+}
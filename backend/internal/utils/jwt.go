@@ -9,18 +9,22 @@ import (
 
 // Claims represents JWT claims
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID uint   `json:"sid"` // Sign-in session the token was issued for
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a new JWT token
-func GenerateToken(userID uint, email string, secret string) (string, error) {
+// GenerateToken generates a new JWT access token for a session, valid for ttl
+func GenerateToken(userID uint, email string, role string, sessionID uint, ttl time.Duration, secret string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
@@ -52,14 +56,3 @@ func ValidateToken(tokenString string, secret string) (*Claims, error) {
 
 	return claims, nil
 }
-
-// RefreshToken generates a new token with extended expiration
-func RefreshToken(oldToken string, secret string) (string, error) {
-	claims, err := ValidateToken(oldToken, secret)
-	if err != nil {
-		return "", err
-	}
-
-	// Generate new token with same user info
-	return GenerateToken(claims.UserID, claims.Email, secret)
-}
\ No newline at end of file
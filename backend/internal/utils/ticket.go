@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTicket is returned for a ticket token that is malformed or whose
+// signature does not match
+var ErrInvalidTicket = errors.New("invalid ticket")
+
+// Ticket is the content of a signed reservation ticket
+type Ticket struct {
+	ReservationID uint
+	Date          string // Format: YYYY-MM-DD
+	CourtID       uint
+}
+
+// SignTicket returns the token of a ticket: its fields and an HMAC-SHA256 signature
+// separated by dots, e.g. "42.2026-01-25.1.<signature>"
+func SignTicket(ticket Ticket, secret string) string {
+	payload := fmt.Sprintf("%d.%s.%d", ticket.ReservationID, ticket.Date, ticket.CourtID)
+	return payload + "." + ticketSignature(payload, secret)
+}
+
+// ParseTicket verifies the signature of a ticket token and returns its content
+func ParseTicket(token string, secret string) (*Ticket, error) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return nil, ErrInvalidTicket
+	}
+	payload, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(ticketSignature(payload, secret))) {
+		return nil, ErrInvalidTicket
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidTicket
+	}
+	reservationID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidTicket
+	}
+	if _, err := time.Parse("2006-01-02", parts[1]); err != nil {
+		return nil, ErrInvalidTicket
+	}
+	courtID, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidTicket
+	}
+
+	return &Ticket{
+		ReservationID: uint(reservationID),
+		Date:          parts[1],
+		CourtID:       uint(courtID),
+	}, nil
+}
+
+// ticketSignature signs a ticket payload. The prefix keeps ticket signatures apart
+// from other uses of the same secret.
+func ticketSignature(payload, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("ticket:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSignAndParseTicket(t *testing.T) {
+	ticket := Ticket{ReservationID: 42, Date: "2030-01-07", CourtID: 3}
+	token := SignTicket(ticket, "secret")
+
+	got, err := ParseTicket(token, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != ticket {
+		t.Errorf("ParseTicket = %+v, want %+v", *got, ticket)
+	}
+}
+
+func TestParseTicketRejectsForgeries(t *testing.T) {
+	token := SignTicket(Ticket{ReservationID: 42, Date: "2030-01-07", CourtID: 3}, "secret")
+	signature := token[strings.LastIndex(token, ".")+1:]
+
+	// Flip the last character of the signature
+	last := signature[len(signature)-1]
+	flipped := byte('A')
+	if last == 'A' {
+		flipped = 'B'
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		secret string
+	}{
+		{"wrong secret", token, "other-secret"},
+		{"other date", "42.2030-01-08.3." + signature, "secret"},
+		{"other court", "42.2030-01-07.4." + signature, "secret"},
+		{"other reservation", "43.2030-01-07.3." + signature, "secret"},
+		{"tampered signature", token[:len(token)-1] + string(flipped), "secret"},
+		{"no signature", "42.2030-01-07.3", "secret"},
+		{"empty", "", "secret"},
+		{"signed bad date", SignTicket(Ticket{ReservationID: 42, Date: "07-01-2030", CourtID: 3}, "secret"), "secret"},
+		{"signed extra field", "42.2030-01-07.3.1." + ticketSignature("42.2030-01-07.3.1", "secret"), "secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ticket, err := ParseTicket(tt.token, tt.secret); !errors.Is(err, ErrInvalidTicket) {
+				t.Errorf("ParseTicket(%q) = %+v, %v, want ErrInvalidTicket", tt.token, ticket, err)
+			}
+		})
+	}
+}
@@ -57,13 +57,14 @@ const LoginPage = () => {
 
       console.log("Login successful", data);
 
-      // Extract token and user from response
+      // Extract tokens and user from response
       const token = data.data?.token || data.token;
+      const refreshToken = data.data?.refresh_token || data.refresh_token;
       const user = data.data?.user || data.user;
 
       if (token && user) {
         // Use context to login
-        login(token, user);
+        login(token, refreshToken, user);
 
         // Restore booking data if exists
         loadFromSessionStorage();
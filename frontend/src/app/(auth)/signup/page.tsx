@@ -67,13 +67,14 @@ const SignupPage = () => {
 
       console.log("Registration successful", data);
 
-      // Extract token and user from response
+      // Extract tokens and user from response
       const token = data.data?.token || data.token;
+      const refreshToken = data.data?.refresh_token || data.refresh_token;
       const user = data.data?.user || data.user;
 
       if (token && user) {
         // Use context to login
-        login(token, user);
+        login(token, refreshToken, user);
 
         // Redirect to home
         router.push("/");
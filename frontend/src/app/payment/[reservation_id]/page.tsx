@@ -4,8 +4,10 @@ import { Check, CreditCard } from "lucide-react";
 import { useParams } from "next/navigation";
 import Link from "next/link";
 import React, { useEffect } from "react";
+import { authFetch } from "@/lib/auth";
 
 interface PaymentData {
+  id?: number;
   court?: {
     name: string;
   };
@@ -25,7 +27,6 @@ const PaymentPage = () => {
   const [paymentData, setPaymentData] = React.useState<PaymentData | null>(
     null,
   );
-  const [transactionId, setTransactionId] = React.useState<string | null>(null);
   const [isSuccess, setIsSuccess] = React.useState<boolean | null>(null);
 
   const params = useParams();
@@ -35,13 +36,12 @@ const PaymentPage = () => {
   useEffect(() => {
     const fetchPaymentData = async () => {
       try {
-        const response = await fetch(
+        const response = await authFetch(
           `${process.env.NEXT_PUBLIC_API_URL}/api/v1/payments/create`,
           {
             method: "POST",
             headers: {
               "Content-Type": "application/json",
-              Authorization: `Bearer ${localStorage.getItem("auth_token")}`,
             },
             body: JSON.stringify({ reservation_id: id }),
           },
@@ -52,9 +52,6 @@ const PaymentPage = () => {
         }
 
         const { data } = await response.json();
-        const params = new URLSearchParams(data.payment_url.split("?")[1]);
-        setTransactionId(params.get("transaction_id"));
-
         setPaymentData(data.payment);
       } catch (error) {
         const errorMessage =
@@ -67,25 +64,25 @@ const PaymentPage = () => {
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, []);
 
-  const handleConfirmPayment = async () => {
-    const API_URL = process.env.NEXT_PUBLIC_API_URL; // Simulate payment success
-    const res = await fetch(`${API_URL}/api/v1/payments/callback`, {
+  // The dummy payment gateway settles or denies the payment and notifies the
+  // backend itself; only the owner of the payment can simulate it
+  const simulatePayment = (result: "settlement" | "deny") => {
+    const API_URL = process.env.NEXT_PUBLIC_API_URL;
+    return authFetch(`${API_URL}/api/v1/payments/${paymentData?.id}/simulate`, {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
-        Authorization: `Bearer ${localStorage.getItem("auth_token")}`,
       },
-      body: JSON.stringify({
-        order_id: transactionId,
-        transaction_status: "settlement", // SUCCESS
-        transaction_id: `MIDTRANS_${transactionId}`,
-        status_code: "200",
-        gross_amount: paymentData?.amount?.toFixed(2) || "0.00",
-      }),
+      body: JSON.stringify({ result }),
     });
+  };
+
+  const handleConfirmPayment = async () => {
+    // Simulate payment success
+    const res = await simulatePayment("settlement");
 
     if (!res.ok) {
-      throw new Error("Failed to process payment callback");
+      throw new Error("Failed to process payment");
     }
 
     const data = await res.json();
@@ -94,22 +91,8 @@ const PaymentPage = () => {
   };
 
   const handleCancelPayment = async () => {
-    const API_URL = process.env.NEXT_PUBLIC_API_URL;
     // Simulate payment failed
-    await fetch(`${API_URL}/api/v1/payments/callback`, {
-      method: "POST",
-      headers: {
-        "Content-Type": "application/json",
-        Authorization: `Bearer ${localStorage.getItem("auth_token")}`,
-      },
-      body: JSON.stringify({
-        order_id: transactionId,
-        transaction_status: "deny", // FAILED
-        transaction_id: `MIDTRANS_${transactionId}`,
-        status_code: "400",
-        gross_amount: paymentData?.amount?.toFixed(2) || "0.00",
-      }),
-    });
+    await simulatePayment("deny");
     setIsSuccess(false);
     console.log("Payment cancelled");
   };
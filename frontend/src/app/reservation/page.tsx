@@ -6,6 +6,7 @@ import { ChevronLeft, ChevronRight, Check, Loader2 } from "lucide-react";
 import Navbar from "@/components/Navbar";
 import Modal from "@/components/Modal";
 import { useBooking } from "@/contexts/BookingContext";
+import { authFetch } from "@/lib/auth";
 import { useRouter } from "next/dist/client/components/navigation";
 
 interface Timeslot {
@@ -159,10 +160,9 @@ const Book = () => {
     try {
       if (!selectedDate || !selectedTime || !selectedCourt) return;
 
-      const res = await fetch(`${API_URL}/api/v1/reservations`, {
+      const res = await authFetch(`${API_URL}/api/v1/reservations`, {
         method: "POST",
         headers: {
-          Authorization: `Bearer ${localStorage.getItem("auth_token")}`,
           "Content-Type": "application/json",
         },
         body: JSON.stringify({
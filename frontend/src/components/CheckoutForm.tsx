@@ -2,6 +2,7 @@
 
 import { useEffect, useState } from "react";
 import { useBooking } from "@/contexts/BookingContext";
+import { authFetch } from "@/lib/auth";
 
 export default function CheckoutForm() {
   const { bookingData, clearBookingData } = useBooking();
@@ -26,13 +27,12 @@ export default function CheckoutForm() {
     };
 
     try {
-      const response = await fetch(
+      const response = await authFetch(
         `${process.env.NEXT_PUBLIC_API_URL}/api/v1/reservations`,
         {
           method: "POST",
           headers: {
             "Content-Type": "application/json",
-            Authorization: `Bearer ${localStorage.getItem("auth_token")}`,
           },
           body: JSON.stringify(reservationData),
         },
@@ -7,6 +7,13 @@ import {
   useEffect,
   ReactNode,
 } from "react";
+import {
+  SESSION_EXPIRED_EVENT,
+  clearSession,
+  loadSession,
+  revokeSession,
+  saveSession,
+} from "@/lib/auth";
 
 interface User {
   id: number;
@@ -20,8 +27,8 @@ interface AuthContextType {
   token: string | null;
   isAuthenticated: boolean;
   isLoading: boolean;
-  login: (token: string, user: User) => void;
-  logout: () => void;
+  login: (token: string, refreshToken: string | null, user: User) => void;
+  logout: () => Promise<void>;
   checkAuth: () => void;
 }
 
@@ -34,41 +41,55 @@ export function AuthProvider({ children }: { children: ReactNode }) {
 
   const isAuthenticated = !!token && !!user;
 
-  // Check auth on mount
+  // Check auth on mount, and follow refreshes, logins and logouts of API calls
+  // and other tabs
   useEffect(() => {
     checkAuth();
+
+    const handleChange = (e: Event) => {
+      const key = e instanceof StorageEvent ? e.key : null;
+      if (key !== null && key !== "auth_token" && key !== "user") return;
+      checkAuth();
+    };
+    window.addEventListener("storage", handleChange);
+    window.addEventListener(SESSION_EXPIRED_EVENT, handleChange);
+    return () => {
+      window.removeEventListener("storage", handleChange);
+      window.removeEventListener(SESSION_EXPIRED_EVENT, handleChange);
+    };
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, []);
 
   const checkAuth = () => {
     try {
-      const savedToken = localStorage.getItem("auth_token");
-      const savedUser = localStorage.getItem("user");
-
-      if (savedToken && savedUser) {
-        setToken(savedToken);
-        setUser(JSON.parse(savedUser));
-      }
+      const session = loadSession<User>();
+      setToken(session?.token ?? null);
+      setUser(session?.user ?? null);
     } catch (error) {
       console.error("Error checking auth:", error);
-      logout();
+      clearSession();
+      setToken(null);
+      setUser(null);
     } finally {
       setIsLoading(false);
     }
   };
 
-  const login = (newToken: string, newUser: User) => {
+  const login = (
+    newToken: string,
+    newRefreshToken: string | null,
+    newUser: User,
+  ) => {
     setToken(newToken);
     setUser(newUser);
-    localStorage.setItem("auth_token", newToken);
-    localStorage.setItem("user", JSON.stringify(newUser));
+    saveSession(newToken, newRefreshToken, newUser);
   };
 
-  const logout = () => {
+  const logout = async () => {
     setToken(null);
     setUser(null);
-    localStorage.removeItem("auth_token");
-    localStorage.removeItem("user");
+    await revokeSession();
+    clearSession();
   };
 
   return (
@@ -0,0 +1,130 @@
+const API_URL = process.env.NEXT_PUBLIC_API_URL;
+
+const TOKEN_KEY = "auth_token";
+const REFRESH_TOKEN_KEY = "refresh_token";
+const USER_KEY = "user";
+
+// Fired on window when the session ends because it could not be refreshed
+export const SESSION_EXPIRED_EVENT = "auth:session-expired";
+
+export interface StoredSession<User> {
+  token: string;
+  refreshToken: string | null;
+  user: User;
+}
+
+export function loadSession<User>(): StoredSession<User> | null {
+  const token = localStorage.getItem(TOKEN_KEY);
+  const user = localStorage.getItem(USER_KEY);
+  if (!token || !user) return null;
+
+  return {
+    token,
+    refreshToken: localStorage.getItem(REFRESH_TOKEN_KEY),
+    user: JSON.parse(user),
+  };
+}
+
+export function saveSession<User>(
+  token: string,
+  refreshToken: string | null,
+  user?: User,
+) {
+  localStorage.setItem(TOKEN_KEY, token);
+  if (refreshToken) {
+    localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+  } else {
+    localStorage.removeItem(REFRESH_TOKEN_KEY);
+  }
+  if (user) {
+    localStorage.setItem(USER_KEY, JSON.stringify(user));
+  }
+}
+
+export function clearSession() {
+  localStorage.removeItem(TOKEN_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+  localStorage.removeItem(USER_KEY);
+}
+
+// Refresh tokens can be used once, and using one twice revokes the whole session,
+// so concurrent requests share a single refresh
+let refreshing: Promise<string | null> | null = null;
+
+// refreshSession exchanges the refresh token for a new access token and refresh
+// token. Returns the new access token, or null if the session has ended.
+export function refreshSession(): Promise<string | null> {
+  if (!refreshing) {
+    refreshing = doRefresh().finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+}
+
+async function doRefresh(): Promise<string | null> {
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+  if (!refreshToken) return null;
+
+  try {
+    const res = await fetch(`${API_URL}/api/v1/auth/refresh`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ refresh_token: refreshToken }),
+    });
+
+    if (!res.ok) {
+      if (res.status === 401) {
+        clearSession();
+        window.dispatchEvent(new Event(SESSION_EXPIRED_EVENT));
+      }
+      return null;
+    }
+
+    const { data } = await res.json();
+    saveSession(data.token, data.refresh_token, data.user);
+    return data.token;
+  } catch (error) {
+    console.error("Error refreshing session:", error);
+    return null;
+  }
+}
+
+// authFetch calls the API with the access token. An expired access token is
+// refreshed and the request sent once more.
+export async function authFetch(
+  input: string,
+  init: RequestInit = {},
+): Promise<Response> {
+  const send = (token: string | null) => {
+    const headers = new Headers(init.headers);
+    if (token) headers.set("Authorization", `Bearer ${token}`);
+    return fetch(input, { ...init, headers });
+  };
+
+  const token = localStorage.getItem(TOKEN_KEY);
+  const res = await send(token);
+  if (res.status !== 401 || !token) return res;
+
+  // The token may already have been refreshed by another request
+  const current = localStorage.getItem(TOKEN_KEY);
+  const fresh = current && current !== token ? current : await refreshSession();
+  return fresh ? send(fresh) : res;
+}
+
+// revokeSession ends the session at the server. The local session is cleared by
+// the caller whatever the outcome.
+export async function revokeSession() {
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+  if (!refreshToken) return;
+
+  try {
+    await fetch(`${API_URL}/api/v1/auth/logout`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ refresh_token: refreshToken }),
+    });
+  } catch (error) {
+    console.error("Error logging out:", error);
+  }
+}